@@ -1,5 +1,11 @@
 package veil
 
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
 type StatusStruct struct {
 	Creating, Active, Failed, Deleting, Service, Partial string
 }
@@ -13,16 +19,26 @@ var Status = StatusStruct{
 	Partial:  "PARTIAL",
 }
 
+// IdempotencyKeyBase lets VeiL recognize a repeated create request, the key is generated automatically if empty
 type IdempotencyKeyBase struct {
 	IdempotencyKey string `json:"idempotency_key,omitempty"`
 }
 
+// apply fills an empty key and marks ctx as safe to retry with it
+func (base *IdempotencyKeyBase) apply(ctx context.Context) context.Context {
+	if base.IdempotencyKey == "" {
+		base.IdempotencyKey = uuid.NewString()
+	}
+	return WithIdempotencyKey(ctx, base.IdempotencyKey)
+}
+
 type ErrorDict struct {
 	Detail string `json:"detail,omitempty"`
 	Code   string `json:"code,omitempty"`
 	MsgKey string `json:"msg_key,omitempty"`
 }
 
+// ErrorResponse body of VeiL response with non-success status code, see APIError
 type ErrorResponse struct {
 	Errors []ErrorDict `json:"errors,omitempty"`
 }
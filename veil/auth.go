@@ -0,0 +1,244 @@
+package veil
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	authUrl   = "/auth/"
+	logoutUrl = "/logout/"
+)
+
+// DefaultTokenLeeway time before expiration when RefreshingTokenSource renews the token
+const DefaultTokenLeeway = time.Minute
+
+// TokenSource returns JWT token for Authorization header of API requests
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenInvalidator is implemented by token sources which can renew the token,
+// client invalidates the token and repeats the request once after 401 response
+type TokenInvalidator interface {
+	Invalidate()
+}
+
+// StaticTokenSource always returns the same token, e.g. integration token of VeiL
+type StaticTokenSource string
+
+// Token returns the token
+func (s StaticTokenSource) Token(ctx context.Context) (string, error) {
+	return string(s), nil
+}
+
+// LoginCredentials credentials of VeiL user
+type LoginCredentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// Code one-time code of two-factor authentication
+	Code string `json:"code,omitempty"`
+	Ldap bool   `json:"ldap"`
+}
+
+// LoginResponse response of auth endpoint
+type LoginResponse struct {
+	Token    string `json:"token"`
+	Username string `json:"username,omitempty"`
+}
+
+// PasswordTokenSource exchanges username and password for a new JWT token on every call,
+// wrap it into RefreshingTokenSource to reuse the token until it expires
+type PasswordTokenSource struct {
+	client      *WebClient
+	credentials LoginCredentials
+	mu          sync.Mutex
+}
+
+// NewPasswordTokenSource creates token source which logs in to the controller of client
+func NewPasswordTokenSource(client *WebClient, credentials LoginCredentials) *PasswordTokenSource {
+	return &PasswordTokenSource{client: client, credentials: credentials}
+}
+
+// Token logs in and returns new token. Two-factor code is one-time, so it is cleared after successful login
+func (s *PasswordTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	response, err := s.client.Login(ctx, s.credentials)
+	if err != nil {
+		return "", err
+	}
+	s.credentials.Code = ""
+	return response.Token, nil
+}
+
+// RefreshingTokenSource caches the token of source and renews it before exp claim of JWT
+type RefreshingTokenSource struct {
+	source TokenSource
+	leeway time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// NewRefreshingTokenSource wraps source, the token is renewed leeway before expiration, zero leeway means DefaultTokenLeeway
+func NewRefreshingTokenSource(source TokenSource, leeway time.Duration) *RefreshingTokenSource {
+	if leeway == 0 {
+		leeway = DefaultTokenLeeway
+	}
+	return &RefreshingTokenSource{source: source, leeway: leeway, now: time.Now}
+}
+
+// Token returns cached token or renews it if it is about to expire
+func (s *RefreshingTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" && (s.expires.IsZero() || s.now().Add(s.leeway).Before(s.expires)) {
+		return s.token, nil
+	}
+	token, err := s.source.Token(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.token = token
+	// Token without exp claim is kept until invalidated
+	s.expires, _ = TokenExpiration(token)
+	return s.token, nil
+}
+
+// Invalidate drops cached token, the next call of Token renews it
+func (s *RefreshingTokenSource) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = ""
+	s.expires = time.Time{}
+}
+
+// TokenExpiration returns exp claim of JWT token, the signature is not verified
+func TokenExpiration(token string) (time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, errors.New("token is not JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, err
+	}
+	claims := struct {
+		Exp int64 `json:"exp"`
+	}{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}, err
+	}
+	if claims.Exp == 0 {
+		return time.Time{}, errors.New("token has no exp claim")
+	}
+	return time.Unix(claims.Exp, 0), nil
+}
+
+// Login exchanges credentials for JWT token, the client token is not changed
+func (client *WebClient) Login(ctx context.Context, credentials LoginCredentials) (*LoginResponse, error) {
+	body, err := json.Marshal(credentials)
+	if err != nil {
+		return nil, err
+	}
+	response := new(LoginResponse)
+	call := &Call{Method: "POST", URL: authUrl, Header: http.Header{}, Body: body, Object: response}
+	if err := client.chain(client.executeLogin)(ctx, call); err != nil {
+		return nil, err
+	}
+	if response.Token == "" {
+		return nil, errors.New("auth response has no token")
+	}
+	return response, nil
+}
+
+// executeLogin is the last handler of middleware chain for login, the request is sent once without token
+func (client *WebClient) executeLogin(ctx context.Context, call *Call) error {
+	req, err := client.newRequest(ctx, call.Method, call.URL, call.Body)
+	if err != nil {
+		return err
+	}
+	for name, values := range call.Header {
+		req.Header[name] = values
+	}
+	call.Attempts++
+	start := time.Now()
+	res, buf, err := client.send(req)
+	client.logRequest(ctx, req, res, err, time.Since(start))
+	call.Response, call.ResponseBody = res, buf
+	if err != nil {
+		return err
+	}
+	return decodeResponse(call.Method, call.URL, res, buf, call.Object)
+}
+
+// Logout invalidates the cached token on the controller and drops it from the token source.
+// Nothing is sent if there is no cached token, e.g. the client has not logged in yet
+func (client *WebClient) Logout(ctx context.Context) error {
+	token, err := client.cachedToken(ctx)
+	if err != nil || token == "" {
+		return err
+	}
+	call := &Call{Method: "POST", URL: logoutUrl, Header: http.Header{}, Body: []byte{}}
+	call.Header.Set("Authorization", "jwt "+token)
+	err = client.chain(client.executeLogout)(ctx, call)
+	if call.Response != nil {
+		if invalidator, ok := client.TokenSource.(TokenInvalidator); ok {
+			invalidator.Invalidate()
+		}
+	}
+	return err
+}
+
+// executeLogout is the last handler of middleware chain for logout, unlike executeCall
+// it does not renew the token after 401 response
+func (client *WebClient) executeLogout(ctx context.Context, call *Call) error {
+	res, buf, err := client.doWithRetry(ctx, call)
+	call.Response, call.ResponseBody = res, buf
+	if err != nil {
+		return err
+	}
+	// Already expired token means the session is over anyway
+	if res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return decodeResponse(call.Method, call.URL, res, buf, nil)
+}
+
+// tokenCache is implemented by token sources which can return the token without logging in
+type tokenCache interface {
+	cachedToken() string
+}
+
+func (s StaticTokenSource) cachedToken() string {
+	return string(s)
+}
+
+// PasswordTokenSource logs in on every call and keeps no token
+func (s *PasswordTokenSource) cachedToken() string {
+	return ""
+}
+
+func (s *RefreshingTokenSource) cachedToken() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.token
+}
+
+// cachedToken returns the token of the client without logging in, custom TokenSource
+// is asked for the token as its cache is not known
+func (client *WebClient) cachedToken(ctx context.Context) (string, error) {
+	if cache, ok := client.TokenSource.(tokenCache); ok {
+		return cache.cachedToken(), nil
+	}
+	return client.token(ctx)
+}
@@ -0,0 +1,154 @@
+package veil
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testJWT(id int, exp time.Time) string {
+	payload, _ := json.Marshal(map[string]interface{}{"user_id": id, "exp": exp.Unix()})
+	return "eyJhbGciOiJub25lIn0." + base64.RawURLEncoding.EncodeToString(payload) + ".sig"
+}
+
+// authServer issues a new token on every login and accepts only the last one
+type authServer struct {
+	sync.Mutex
+	logins  int
+	logouts int
+	valid   string
+}
+
+func (s *authServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+	switch r.URL.Path {
+	case "/auth/":
+		credentials := LoginCredentials{}
+		json.NewDecoder(r.Body).Decode(&credentials)
+		if credentials.Username != "bot" || credentials.Password != "secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errors": [{"detail": "wrong credentials"}]}`))
+			return
+		}
+		s.logins++
+		s.valid = testJWT(s.logins, time.Now().Add(time.Hour))
+		fmt.Fprintf(w, `{"token": "%s", "username": "bot"}`, s.valid)
+	case "/logout/":
+		s.logouts++
+		s.valid = ""
+	default:
+		if r.Header.Get("Authorization") != "jwt "+s.valid || s.valid == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"errors": [{"detail": "token expired"}]}`))
+			return
+		}
+		w.Write([]byte(`{"id": "node-1"}`))
+	}
+}
+
+func Test_TokenExpiration(t *testing.T) {
+	exp := time.Unix(1955427991, 0)
+	got, err := TokenExpiration(testJWT(1, exp))
+	require.NoError(t, err)
+	assert.True(t, exp.Equal(got))
+	_, err = TokenExpiration("not a token")
+	assert.Error(t, err)
+	return
+}
+
+func Test_PasswordLoginRefresh(t *testing.T) {
+	auth := &authServer{}
+	server := httptest.NewServer(auth)
+	defer server.Close()
+
+	client, err := NewClientWithOptions(WithBaseURL(server.URL),
+		WithCredentials(LoginCredentials{Username: "bot", Password: "secret", Code: "123456"}))
+	require.NoError(t, err)
+	_, _, err = client.Node.Get("node-1")
+	require.NoError(t, err)
+	_, _, err = client.Node.Get("node-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, auth.logins)
+
+	// Token revoked by the controller is renewed once
+	auth.Lock()
+	auth.valid = "revoked"
+	auth.Unlock()
+	_, _, err = client.Node.Get("node-1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, auth.logins)
+
+	// Token is renewed before expiration
+	source := client.TokenSource.(*RefreshingTokenSource)
+	source.now = func() time.Time { return time.Now().Add(59*time.Minute + 30*time.Second) }
+	_, _, err = client.Node.Get("node-1")
+	require.NoError(t, err)
+	assert.Equal(t, 3, auth.logins)
+
+	source.now = time.Now
+	require.NoError(t, client.Logout(context.Background()))
+	assert.Equal(t, 1, auth.logouts)
+	_, _, err = client.Node.Get("node-1")
+	require.NoError(t, err)
+	assert.Equal(t, 4, auth.logins)
+	return
+}
+
+func Test_LogoutCachedToken(t *testing.T) {
+	auth := &authServer{}
+	server := httptest.NewServer(auth)
+	defer server.Close()
+
+	calls := 0
+	counter := func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) error {
+			calls++
+			return next(ctx, call)
+		}
+	}
+	client, err := NewClientWithOptions(WithBaseURL(server.URL), WithMiddleware(counter),
+		WithCredentials(LoginCredentials{Username: "bot", Password: "secret"}))
+	require.NoError(t, err)
+
+	// Client which has not logged in does not log in to log out
+	require.NoError(t, client.Logout(context.Background()))
+	assert.Equal(t, 0, auth.logins)
+	assert.Equal(t, 0, auth.logouts)
+	assert.Equal(t, 0, calls)
+
+	_, _, err = client.Node.Get("node-1")
+	require.NoError(t, err)
+	require.NoError(t, client.Logout(context.Background()))
+	assert.Equal(t, 1, auth.logins)
+	assert.Equal(t, 1, auth.logouts)
+	assert.Equal(t, 3, calls)
+	return
+}
+
+func Test_StaticTokenUnauthorized(t *testing.T) {
+	auth := &authServer{valid: "expired"}
+	server := httptest.NewServer(auth)
+	defer server.Close()
+
+	client := NewClient(server.URL, "other", false)
+	_, _, err := client.Node.Get("node-1")
+	assert.True(t, IsUnauthorized(err))
+
+	_, err = client.Login(context.Background(), LoginCredentials{Username: "bot", Password: "wrong"})
+	assert.True(t, IsBadRequest(err))
+
+	client, err = NewClientWithOptions(WithBaseURL(server.URL), WithTokenSource(StaticTokenSource("expired")))
+	require.NoError(t, err)
+	_, _, err = client.Node.Get("node-1")
+	assert.NoError(t, err)
+	return
+}
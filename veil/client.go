@@ -2,14 +2,14 @@ package veil
 
 import (
 	"bytes"
-	"crypto/tls"
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
-	"log"
 	"net/http"
+	neturl "net/url"
+	"time"
 )
 
 const baseApiUrl string = "/api/"
@@ -17,15 +17,40 @@ const baseApiUrl string = "/api/"
 // Client Interface of Client for mocking data receiving in tests
 type Client interface {
 	ExecuteRequest(method, url string, body []byte, object interface{}) (*http.Response, error)
+	ExecuteRequestContext(ctx context.Context, method, url string, body []byte, object interface{}) (*http.Response, error)
 	Execute(req *http.Request) (*http.Response, error)
-	RetClient() *WebClient
 }
 
 type WebClient struct {
 	Token      string
 	HTTPClient *http.Client
 
+	// TokenSource of JWT tokens, Token is used if it is nil
+	TokenSource TokenSource
+
 	BaseURL string
+	// Endpoints controllers of HA installation, BaseURL is not used if it is set
+	Endpoints *EndpointPool
+
+	// UserAgent header of requests, empty means default of net/http
+	UserAgent string
+	// AcceptLanguage language of API messages, "en" or "ru"
+	AcceptLanguage string
+
+	// RetryPolicy of transient failures, nil disables retries
+	RetryPolicy *RetryPolicy
+	// RateLimiter limits rate and concurrency of requests, nil means no limits
+	RateLimiter *RateLimiter
+
+	// Logger of requests and tasks, nil disables logging
+	Logger Logger
+
+	// Middleware wraps every API call and Execute, the first one is the outermost
+	Middleware []Middleware
+	// TaskObservers are notified about task waits
+	TaskObservers []TaskObserver
+	// OperationObservers are notified about operations consisting of several requests
+	OperationObservers []OperationObserver
 
 	// Services which is used for accessing API
 	Domain      *DomainService
@@ -48,7 +73,8 @@ type Error struct {
 	Field string `json:"field,omitempty"`
 }
 
-// NewClient Web client creating
+// NewClient Web client creating, use NewClientWithOptions for timeouts, CA certificates and other transport settings.
+// Empty apiUrl and token are taken from VEIL_API_URL and VEIL_API_TOKEN, requests fail with ErrNotConfigured if the url is still empty.
 func NewClient(apiUrl string, token string, insecure bool) *WebClient {
 	if apiUrl == "" {
 		apiUrl = GetEnvUrl()
@@ -56,87 +82,264 @@ func NewClient(apiUrl string, token string, insecure bool) *WebClient {
 	if token == "" {
 		token = GetEnvToken()
 	}
-	tlsConf := &tls.Config{InsecureSkipVerify: insecure}
-	tr := &http.Transport{
-		TLSClientConfig:    tlsConf,
-		DisableCompression: true,
-		Proxy:              nil,
-	}
-	hClient := &http.Client{Transport: tr}
+	// These options are always valid
+	client, _ := NewClientWithOptions(WithBaseURL(apiUrl), WithToken(token), WithInsecure(insecure))
+	return client
+}
+
+func newWebClient(apiUrl string, token string, hClient *http.Client) *WebClient {
 	client := &WebClient{
-		Token:      token,
-		HTTPClient: hClient,
-		BaseURL:    apiUrl,
-	}
-
-	// Passing client to all services for easy client mocking in future and not passing it to every function
-	client.Domain = &DomainService{client}
-	client.Node = &NodeService{client}
-	client.Cluster = &ClusterService{client}
-	client.DataCenter = &DataCenterService{client}
-	client.DataPool = &DataPoolService{client}
-	client.Vdisk = &VdiskService{client}
-	client.Iso = &IsoService{client}
-	client.Library = &LibraryService{client}
-	client.Task = &TaskService{client}
-	client.Event = &EventService{client}
-	client.User = &UserService{client}
-	client.Vnet = &VnetService{client}
-	client.VMachineInf = &VMachineInfService{client}
+		Token:          token,
+		HTTPClient:     hClient,
+		BaseURL:        apiUrl,
+		AcceptLanguage: DefaultAcceptLanguage,
+	}
+
+	// Passing client to all services for easy client mocking and not passing it to every function
+	client.Domain = NewDomainService(client)
+	client.Node = NewNodeService(client)
+	client.Cluster = NewClusterService(client)
+	client.DataCenter = NewDataCenterService(client)
+	client.DataPool = NewDataPoolService(client)
+	client.Vdisk = NewVdiskService(client)
+	client.Iso = NewIsoService(client)
+	client.Library = NewLibraryService(client)
+	client.Task = NewTaskService(client)
+	client.Event = NewEventService(client)
+	client.User = NewUserService(client)
+	client.Vnet = NewVnetService(client)
+	client.VMachineInf = NewVMachineInfService(client)
 	return client
 }
 
 // ExecuteRequest Executing HTTP Request (receiving info from API)
 func (client *WebClient) ExecuteRequest(method string, url string, body []byte, object interface{}) (*http.Response, error) {
-	req, err := http.NewRequest(method, fmt.Sprint(client.BaseURL, url), bytes.NewBuffer(body))
+	return client.ExecuteRequestContext(context.Background(), method, url, body, object)
+}
+
+// ExecuteRequestContext Executing HTTP Request bound to ctx, the request is aborted once ctx is done
+func (client *WebClient) ExecuteRequestContext(ctx context.Context, method string, url string, body []byte, object interface{}) (*http.Response, error) {
+	call := &Call{Method: method, URL: url, Header: http.Header{}, Body: body, Object: object}
+	err := client.chain(client.executeCall)(ctx, call)
+	return call.Response, err
+}
+
+// executeCall is the last handler of middleware chain for API calls
+func (client *WebClient) executeCall(ctx context.Context, call *Call) error {
+	res, buf, err := client.doWithRetry(ctx, call)
+	if err == nil && res.StatusCode == http.StatusUnauthorized {
+		// Expired token is renewed once if the token source is able to
+		if invalidator, ok := client.TokenSource.(TokenInvalidator); ok {
+			invalidator.Invalidate()
+			res, buf, err = client.doWithRetry(ctx, call)
+		}
+	}
+	call.Response, call.ResponseBody = res, buf
 	if err != nil {
-		return new(http.Response), err
+		return err
+	}
+	err = decodeResponse(call.Method, call.URL, res, buf, call.Object)
+	if _, isAPIError := AsAPIError(err); err != nil && !isAPIError {
+		client.logger().Log(ctx, LevelError, "veil response decoding failed", "method", call.Method, "url", call.URL, "status", res.StatusCode, "error", err)
 	}
+	return err
+}
 
-	req.Header.Set("Authorization", "jwt "+client.Token)
-	req.Header.Set("Content-Type", "application/json;charset=utf-8")
-	req.Header.Set("Accept-Language", "en")
-	res, err := client.HTTPClient.Do(req)
-	if err != nil {
-		return res, err
+// doWithRetry performs API request with retries of RetryPolicy and failover to other controllers
+func (client *WebClient) doWithRetry(ctx context.Context, call *Call) (*http.Response, []byte, error) {
+	failovers := 0
+	for attempt := 1; ; {
+		call.Attempts++
+		base := client.ActiveEndpoint()
+		res, buf, err := client.doRequest(ctx, base, call)
+		// Every other controller is tried once without backoff, attempts of RetryPolicy are not spent
+		if failovers < client.Endpoints.Len()-1 && client.failover(ctx, base, call.Method, res, err) {
+			failovers++
+			continue
+		}
+		if !client.RetryPolicy.canRetry(ctx, call.Method, attempt, res, err) {
+			return res, buf, err
+		}
+		backoff := client.RetryPolicy.backoff(attempt, res)
+		client.logger().Log(ctx, LevelWarn, "veil request retry", "method", call.Method, "url", call.URL, "attempt", attempt, "backoff", backoff)
+		if err := sleepContext(ctx, backoff); err != nil {
+			return res, buf, err
+		}
+		attempt++
 	}
-	defer res.Body.Close()
+}
 
-	// Cloning response body for future using
-	buf, _ := ioutil.ReadAll(res.Body)
-	reader := ioutil.NopCloser(bytes.NewBuffer(buf))
-	res.Body = ioutil.NopCloser(bytes.NewBuffer(buf))
+// decodeResponse converts unsuccessful response to APIError or decodes successful one to object
+func decodeResponse(method string, url string, res *http.Response, buf []byte, object interface{}) error {
+	reader := bytes.NewReader(buf)
 
 	if !IsSuccess(res.StatusCode) {
+		apiErr := &APIError{
+			StatusCode: res.StatusCode,
+			Method:     method,
+			URL:        url,
+			Body:       buf,
+		}
+		// Non JSON body (e.g. proxy error page) is kept only in APIError.Body
 		response := new(ErrorResponse)
-		err := json.NewDecoder(reader).Decode(response)
-		if err != nil && err != io.EOF {
-			log.Println(err)
-			return res, err
-		} else {
-			errMsg := fmt.Sprintf("status code: %d, detail: %s on url %s %s", res.StatusCode, res.Body, method, url)
-			return res, errors.New(errMsg)
+		if err := json.NewDecoder(reader).Decode(response); err == nil {
+			apiErr.Errors = response.Errors
 		}
-
+		return apiErr
 	}
 	if object != nil && (res.StatusCode == 200 || res.StatusCode == 202) {
 		err := json.NewDecoder(reader).Decode(object)
 
 		// EOF means empty response body, this error is not needed
 		if err != nil && err != io.EOF {
-			log.Println(err)
-			return res, err
+			return err
+		}
+	}
+	return nil
+}
+
+// doRequest performs a single attempt of API request, the response body is read and returned separately
+func (client *WebClient) doRequest(ctx context.Context, base string, call *Call) (*http.Response, []byte, error) {
+	req, err := client.newRequestTo(ctx, base, call.Method, call.URL, call.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Authorization set by the caller (e.g. Logout) does not need the token source
+	if call.Header.Get("Authorization") == "" {
+		token, err := client.token(ctx)
+		if err != nil {
+			return nil, nil, err
 		}
+		req.Header.Set("Authorization", "jwt "+token)
+	}
+	// Headers of middleware override the default ones
+	for name, values := range call.Header {
+		req.Header[name] = values
+	}
+	release, err := client.RateLimiter.acquire(ctx, requestClass(ctx, call.Method))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+	start := time.Now()
+	res, buf, err := client.send(req)
+	client.logRequest(ctx, req, res, err, time.Since(start))
+	return res, buf, err
+}
+
+// logRequest writes request result, failed requests are logged with warning level
+func (client *WebClient) logRequest(ctx context.Context, req *http.Request, res *http.Response, err error, duration time.Duration) {
+	logger := client.logger()
+	if err != nil {
+		logger.Log(ctx, LevelError, "veil request failed", "method", req.Method, "url", req.URL.String(),
+			"duration", duration, "error", err)
+		return
+	}
+	level := LevelDebug
+	if !IsSuccess(res.StatusCode) {
+		level = LevelWarn
+	}
+	logger.Log(ctx, level, "veil request", "method", req.Method, "url", req.URL.String(), "status", res.StatusCode,
+		"duration", duration, "headers", RedactHeaders(req.Header))
+}
+
+// newRequest creates API request with common headers, but without authorization
+func (client *WebClient) newRequest(ctx context.Context, method string, url string, body []byte) (*http.Request, error) {
+	return client.newRequestTo(ctx, client.ActiveEndpoint(), method, url, body)
+}
+
+// newRequestTo creates API request to the controller base
+func (client *WebClient) newRequestTo(ctx context.Context, base string, method string, url string, body []byte) (*http.Request, error) {
+	if base == "" {
+		return nil, ErrNotConfigured
+	}
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprint(base, url), bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json;charset=utf-8")
+	lang := client.AcceptLanguage
+	if lang == "" {
+		lang = DefaultAcceptLanguage
+	}
+	req.Header.Set("Accept-Language", lang)
+	client.setUserAgent(req)
+	return req, nil
+}
+
+// send executes request and reads the response body
+func (client *WebClient) send(req *http.Request) (*http.Response, []byte, error) {
+	res, err := client.HTTPClient.Do(req)
+	if err != nil {
+		return res, nil, err
 	}
+	defer res.Body.Close()
+
+	// Cloning response body for future using
+	buf, err := ioutil.ReadAll(res.Body)
+	res.Body = ioutil.NopCloser(bytes.NewBuffer(buf))
+	return res, buf, err
+}
 
-	return res, nil
+// token returns token of TokenSource if set, else static Token
+func (client *WebClient) token(ctx context.Context) (string, error) {
+	if client.TokenSource == nil {
+		return client.Token, nil
+	}
+	return client.TokenSource.Token(ctx)
 }
 
-// Execute user HTTP Request
+// Execute user HTTP Request, relative url (e.g. upload_url of file) is resolved against BaseURL.
+// The request passes through the middleware chain, the response body is not read.
 func (client *WebClient) Execute(req *http.Request) (*http.Response, error) {
-	return client.HTTPClient.Do(req)
+	if !req.URL.IsAbs() {
+		base, err := client.baseURL()
+		if err != nil {
+			return nil, err
+		}
+		req.URL = base.ResolveReference(req.URL)
+		req.Host = req.URL.Host
+	}
+	call := &Call{Method: req.Method, URL: req.URL.String(), Header: req.Header, Request: req}
+	err := client.chain(client.executeRaw)(req.Context(), call)
+	return call.Response, err
+}
+
+// executeRaw is the last handler of middleware chain for Execute
+func (client *WebClient) executeRaw(ctx context.Context, call *Call) error {
+	req := call.Request.WithContext(ctx)
+	req.Header = call.Header
+	client.setUserAgent(req)
+	release, err := client.RateLimiter.acquire(ctx, requestClass(ctx, call.Method))
+	if err != nil {
+		return err
+	}
+	defer release()
+	call.Attempts++
+	start := time.Now()
+	res, err := client.HTTPClient.Do(req)
+	client.logRequest(ctx, req, res, err, time.Since(start))
+	call.Response = res
+	return err
+}
+
+func (client *WebClient) baseURL() (*neturl.URL, error) {
+	base := client.ActiveEndpoint()
+	if base == "" {
+		return nil, ErrNotConfigured
+	}
+	return neturl.Parse(base)
+}
+
+func (client *WebClient) setUserAgent(req *http.Request) {
+	if client.UserAgent != "" && req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", client.UserAgent)
+	}
 }
 
+// RetClient returns the client itself
+//
+// Deprecated: services work with any Client, use the WebClient directly.
 func (client *WebClient) RetClient() *WebClient {
 	return client
 }
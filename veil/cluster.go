@@ -1,6 +1,7 @@
 package veil
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -67,34 +68,107 @@ type ClustersResponse struct {
 }
 
 func (d *ClusterService) List() (*ClustersResponse, *http.Response, error) {
+	return d.ListContext(context.Background())
+}
+
+func (d *ClusterService) ListContext(ctx context.Context) (*ClustersResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "ClusterService.List")
 
 	response := new(ClustersResponse)
 
-	res, err := d.client.ExecuteRequest("GET", baseClusterUrl, []byte{}, response)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", baseClusterUrl, []byte{}, response)
 
 	return response, res, err
 }
 
 func (d *ClusterService) ListParams(queryParams map[string]string) (*ClustersResponse, *http.Response, error) {
-	listUrl := baseClusterUrl
-	if len(queryParams) != 0 {
-		params := url.Values{}
-		for k, v := range queryParams {
-			params.Add(k, v)
-		}
-		listUrl += "?"
-		listUrl += params.Encode()
-	}
+	return d.ListParamsContext(context.Background(), queryParams)
+}
+
+func (d *ClusterService) ListParamsContext(ctx context.Context, queryParams map[string]string) (*ClustersResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "ClusterService.ListParams")
+	response := new(ClustersResponse)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", buildListUrl(baseClusterUrl, Params(queryParams)), []byte{}, response)
+	return response, res, err
+}
+
+// ClusterFilter typed filter of clusters, zero fields are not sent
+type ClusterFilter struct {
+	ListOptions
+	Status      []string
+	VerboseName string
+	Datacenter  string
+	Tags        []string
+}
+
+// Values encodes the filter to query parameters
+func (f ClusterFilter) Values() url.Values {
+	params := f.ListOptions.Values()
+	addStrings(params, "status", f.Status)
+	setString(params, "verbose_name", f.VerboseName)
+	setString(params, "datacenter", f.Datacenter)
+	addStrings(params, "tags", f.Tags)
+	return params
+}
+
+// ListFiltered returns the first page of clusters matching filter
+func (d *ClusterService) ListFiltered(filter ClusterFilter) (*ClustersResponse, *http.Response, error) {
+	return d.ListFilteredContext(context.Background(), filter)
+}
+
+// ListFilteredContext returns the first page of clusters matching filter
+func (d *ClusterService) ListFilteredContext(ctx context.Context, filter ClusterFilter) (*ClustersResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "ClusterService.ListFiltered")
 	response := new(ClustersResponse)
-	res, err := d.client.ExecuteRequest("GET", listUrl, []byte{}, response)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", buildListUrl(baseClusterUrl, filter), []byte{}, response)
 	return response, res, err
 }
 
+// ClusterIterator streams clusters page by page
+type ClusterIterator struct {
+	*Iterator
+	item ClusterObjectsList
+}
+
+// Next advances to the next cluster
+func (it *ClusterIterator) Next() bool {
+	it.item = ClusterObjectsList{}
+	return it.Iterator.Next() && it.decode(&it.item)
+}
+
+// Item returns the current cluster
+func (it *ClusterIterator) Item() ClusterObjectsList {
+	return it.item
+}
+
+// Iter returns an iterator over all clusters matching filter, filter and opts may be nil
+func (d *ClusterService) Iter(ctx context.Context, filter ListFilter, opts *PageOptions) *ClusterIterator {
+	ctx = withOperation(ctx, "ClusterService.Iter")
+	return &ClusterIterator{Iterator: newIterator(ctx, d.client, baseClusterUrl, filter, opts)}
+}
+
+// ListAll returns all clusters matching filter following the pages until exhausted
+func (d *ClusterService) ListAll(ctx context.Context, filter ListFilter) ([]ClusterObjectsList, error) {
+	ctx = withOperation(ctx, "ClusterService.ListAll")
+	it := d.Iter(ctx, filter, nil)
+	defer it.Close()
+	var items []ClusterObjectsList
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
+
 func (d *ClusterService) Get(Id string) (*ClusterObject, *http.Response, error) {
+	return d.GetContext(context.Background(), Id)
+}
+
+func (d *ClusterService) GetContext(ctx context.Context, Id string) (*ClusterObject, *http.Response, error) {
+	ctx = withOperation(ctx, "ClusterService.Get")
 
 	entity := new(ClusterObject)
 
-	res, err := d.client.ExecuteRequest("GET", fmt.Sprint(baseClusterUrl, Id, "/"), []byte{}, entity)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", fmt.Sprint(baseClusterUrl, Id, "/"), []byte{}, entity)
 
 	return entity, res, err
 }
@@ -6,7 +6,7 @@ import (
 )
 
 func Test_ClusterListGet(t *testing.T) {
-	client := NewClient("", "", false)
+	_, client := newTestServerClient(t)
 	response, _, err := client.Cluster.List()
 	assert.Nil(t, err)
 	for _, v := range response.Results {
@@ -0,0 +1,172 @@
+package veil
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Environment variables of client configuration
+const (
+	EnvApiUrl   = "VEIL_API_URL"
+	EnvApiToken = "VEIL_API_TOKEN"
+	EnvConfig   = "VEIL_CONFIG"
+	EnvProfile  = "VEIL_PROFILE"
+)
+
+// ErrNotConfigured is returned when url of VeiL controller is set neither explicitly, nor in env, nor in config file
+var ErrNotConfigured = errors.New("veil: controller url is not configured, set " + EnvApiUrl + " or a profile in config file")
+
+// Config settings of connection to VeiL controller
+type Config struct {
+	URL string `yaml:"url" json:"url"`
+	// Endpoints controllers of HA installation, used instead of URL
+	Endpoints      []string `yaml:"endpoints" json:"endpoints"`
+	Token          string   `yaml:"token" json:"token"`
+	Insecure       bool     `yaml:"insecure" json:"insecure"`
+	CAFile         string   `yaml:"ca-file" json:"ca-file"`
+	CertFile       string   `yaml:"cert-file" json:"cert-file"`
+	KeyFile        string   `yaml:"key-file" json:"key-file"`
+	AcceptLanguage string   `yaml:"accept-language" json:"accept-language"`
+}
+
+// ConfigProfile named connection settings in config file
+type ConfigProfile struct {
+	Name   string `yaml:"name" json:"name"`
+	Config `yaml:",inline"`
+}
+
+// ConfigFile file with named profiles, like contexts of kubeconfig:
+//
+//	current-profile: prod
+//	profiles:
+//	  - name: prod
+//	    url: https://veil.example.com
+//	    token: eyJ0eXAiOiJKV1Qi...
+//	    ca-file: /etc/veil/ca.pem
+//	  - name: test
+//	    url: http://192.168.11.105
+//	    insecure: true
+type ConfigFile struct {
+	CurrentProfile string          `yaml:"current-profile" json:"current-profile"`
+	Profiles       []ConfigProfile `yaml:"profiles" json:"profiles"`
+}
+
+// DefaultConfigPath path of config file used if VEIL_CONFIG is not set, ~/.veil/config.yaml
+func DefaultConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".veil", "config.yaml")
+}
+
+// ReadConfigFile parses YAML (or JSON) config file
+func ReadConfigFile(path string) (*ConfigFile, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	file := new(ConfigFile)
+	if err := yaml.Unmarshal(data, file); err != nil {
+		return nil, fmt.Errorf("parse config %s: %w", path, err)
+	}
+	return file, nil
+}
+
+// Profile returns profile by name, empty name means current profile
+func (f *ConfigFile) Profile(name string) (*Config, error) {
+	if name == "" {
+		name = f.CurrentProfile
+	}
+	if name == "" && len(f.Profiles) == 1 {
+		return &f.Profiles[0].Config, nil
+	}
+	if name == "" {
+		return nil, errors.New("veil: current-profile is not set in config file")
+	}
+	for i := range f.Profiles {
+		if f.Profiles[i].Name == name {
+			return &f.Profiles[i].Config, nil
+		}
+	}
+	return nil, fmt.Errorf("veil: profile %q not found in config file", name)
+}
+
+// LoadConfig loads configuration in the following order, later sources override earlier:
+// profile of config file (VEIL_CONFIG or DefaultConfigPath, profile argument, VEIL_PROFILE or current-profile),
+// then VEIL_API_URL and VEIL_API_TOKEN. ErrNotConfigured is returned if the url is still empty.
+func LoadConfig(profile string) (*Config, error) {
+	config := new(Config)
+	path := os.Getenv(EnvConfig)
+	explicit := path != ""
+	if !explicit {
+		path = DefaultConfigPath()
+	}
+	if profile == "" {
+		profile = os.Getenv(EnvProfile)
+	}
+	if path != "" {
+		file, err := ReadConfigFile(path)
+		switch {
+		case err == nil:
+			p, err := file.Profile(profile)
+			if err != nil {
+				return nil, err
+			}
+			*config = *p
+		case explicit || profile != "" || !errors.Is(err, os.ErrNotExist):
+			// Missing default file is fine, but not the file or profile asked for
+			return nil, err
+		}
+	}
+	if url := GetEnvUrl(); url != "" {
+		config.URL = url
+	}
+	if token := GetEnvToken(); token != "" {
+		config.Token = token
+	}
+	if config.URL == "" && len(config.Endpoints) == 0 {
+		return nil, ErrNotConfigured
+	}
+	return config, nil
+}
+
+// Options converts configuration to client options
+func (c *Config) Options() []ClientOption {
+	opts := []ClientOption{WithBaseURL(c.URL), WithToken(c.Token), WithInsecure(c.Insecure)}
+	if len(c.Endpoints) != 0 {
+		opts = append(opts, WithEndpoints(c.Endpoints...))
+	}
+	if c.CAFile != "" {
+		opts = append(opts, WithCAFile(c.CAFile))
+	}
+	if c.CertFile != "" || c.KeyFile != "" {
+		opts = append(opts, WithClientCertificateFile(c.CertFile, c.KeyFile))
+	}
+	if c.AcceptLanguage != "" {
+		opts = append(opts, WithAcceptLanguage(c.AcceptLanguage))
+	}
+	return opts
+}
+
+// NewClientFromConfig Web client creating from configuration, opts override the configuration
+func NewClientFromConfig(config *Config, opts ...ClientOption) (*WebClient, error) {
+	if config == nil || (config.URL == "" && len(config.Endpoints) == 0) {
+		return nil, ErrNotConfigured
+	}
+	return NewClientWithOptions(append(config.Options(), opts...)...)
+}
+
+// NewClientFromEnv Web client creating from env and config file, see LoadConfig
+func NewClientFromEnv(profile string, opts ...ClientOption) (*WebClient, error) {
+	config, err := LoadConfig(profile)
+	if err != nil {
+		return nil, err
+	}
+	return NewClientFromConfig(config, opts...)
+}
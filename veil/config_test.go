@@ -0,0 +1,108 @@
+package veil
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+const testConfigFile = `
+current-profile: prod
+profiles:
+  - name: prod
+    url: https://veil.example.com
+    token: prod-token
+  - name: test
+    url: http://192.168.11.105
+    token: test-token
+    insecure: true
+    accept-language: ru
+`
+
+func Test_LoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(testConfigFile), 0600))
+	t.Setenv(EnvConfig, path)
+	t.Setenv(EnvProfile, "")
+	t.Setenv(EnvApiUrl, "")
+	t.Setenv(EnvApiToken, "")
+
+	config, err := LoadConfig("")
+	require.NoError(t, err)
+	assert.Equal(t, "https://veil.example.com", config.URL)
+	assert.Equal(t, "prod-token", config.Token)
+
+	config, err = LoadConfig("test")
+	require.NoError(t, err)
+	assert.Equal(t, Config{URL: "http://192.168.11.105", Token: "test-token", Insecure: true, AcceptLanguage: "ru"}, *config)
+
+	t.Setenv(EnvProfile, "test")
+	config, err = LoadConfig("")
+	require.NoError(t, err)
+	assert.Equal(t, "test-token", config.Token)
+
+	// Env variables override the profile
+	t.Setenv(EnvApiToken, "env-token")
+	config, err = LoadConfig("prod")
+	require.NoError(t, err)
+	assert.Equal(t, "https://veil.example.com", config.URL)
+	assert.Equal(t, "env-token", config.Token)
+
+	_, err = LoadConfig("missing")
+	assert.EqualError(t, err, `veil: profile "missing" not found in config file`)
+
+	t.Setenv(EnvConfig, filepath.Join(t.TempDir(), "missing.yaml"))
+	_, err = LoadConfig("")
+	assert.Error(t, err)
+	return
+}
+
+func Test_NotConfigured(t *testing.T) {
+	t.Setenv(EnvConfig, "")
+	t.Setenv(EnvProfile, "")
+	t.Setenv(EnvApiUrl, "")
+	t.Setenv(EnvApiToken, "")
+	t.Setenv("HOME", t.TempDir())
+
+	_, err := LoadConfig("")
+	assert.ErrorIs(t, err, ErrNotConfigured)
+	_, err = NewClientFromEnv("")
+	assert.ErrorIs(t, err, ErrNotConfigured)
+
+	client := NewClient("", "", false)
+	_, _, err = client.Node.List()
+	assert.ErrorIs(t, err, ErrNotConfigured)
+	req, _ := http.NewRequest("GET", "/downloads/file.iso", nil)
+	_, err = client.Execute(req)
+	assert.ErrorIs(t, err, ErrNotConfigured)
+
+	t.Setenv(EnvApiUrl, "http://veil.example.com")
+	client, err = NewClientFromEnv("")
+	require.NoError(t, err)
+	assert.Equal(t, "http://veil.example.com", client.BaseURL)
+	return
+}
+
+func Test_ExecuteRelativeUrl(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/downloads/file.iso", r.URL.Path)
+		w.Write([]byte("data"))
+	}))
+	defer server.Close()
+	client, err := NewClientFromConfig(&Config{URL: server.URL, Token: "token"})
+	require.NoError(t, err)
+
+	req, err := http.NewRequestWithContext(context.Background(), "GET", "/downloads/file.iso", nil)
+	require.NoError(t, err)
+	res, err := client.Execute(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	data, _ := ioutil.ReadAll(res.Body)
+	assert.Equal(t, "data", string(data))
+	return
+}
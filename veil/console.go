@@ -0,0 +1,151 @@
+package veil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type ConsoleProtocolStruct struct {
+	Spice, Vnc string
+}
+
+// ConsoleProtocol protocols of domain remote console
+var ConsoleProtocol = ConsoleProtocolStruct{
+	Spice: "spice",
+	Vnc:   "vnc",
+}
+
+// ConsoleConnection connection details of domain remote console. Ticket is a temporary password
+// valid until TicketExpires, it is preferred to the permanent Password by VirtViewerFile and URI
+type ConsoleConnection struct {
+	Protocol      string `json:"protocol,omitempty"`
+	Host          string `json:"host,omitempty"`
+	Port          int    `json:"port,omitempty"`
+	TlsPort       int    `json:"tls_port,omitempty"`
+	Password      string `json:"password,omitempty"`
+	Ticket        string `json:"ticket,omitempty"`
+	TicketExpires string `json:"ticket_expires,omitempty"`
+}
+
+// VirtViewerOptions optional settings of .vv file
+type VirtViewerOptions struct {
+	Title      string
+	Fullscreen bool
+	// DeleteThisFile makes remote-viewer delete the file after reading, the file contains the password
+	DeleteThisFile bool
+	// CA PEM certificate of the console TLS port
+	CA string
+	// HostSubject subject of the TLS certificate if it differs from Host
+	HostSubject string
+	// Proxy http proxy to reach the console, e.g. http://proxy:3128
+	Proxy string
+}
+
+func (c *ConsoleConnection) credential() string {
+	if c.Ticket != "" {
+		return c.Ticket
+	}
+	return c.Password
+}
+
+// VirtViewerFile renders the connection as virt-viewer .vv file for remote-viewer, CR and LF are removed from values.
+// opts may be nil
+func (c *ConsoleConnection) VirtViewerFile(opts *VirtViewerOptions) []byte {
+	if opts == nil {
+		opts = new(VirtViewerOptions)
+	}
+	var b bytes.Buffer
+	b.WriteString("[virt-viewer]\n")
+	// Line breaks are dropped from values, otherwise a value like the title could add keys to the file
+	line := func(key string, value string) {
+		value = strings.NewReplacer("\r", "", "\n", "").Replace(value)
+		if value != "" {
+			fmt.Fprintf(&b, "%s=%s\n", key, value)
+		}
+	}
+	port := func(p int) string {
+		if p == 0 {
+			return ""
+		}
+		return strconv.Itoa(p)
+	}
+	flag := func(v bool) string {
+		if v {
+			return "1"
+		}
+		return "0"
+	}
+	line("type", c.Protocol)
+	line("host", c.Host)
+	line("port", port(c.Port))
+	line("tls-port", port(c.TlsPort))
+	line("password", c.credential())
+	line("title", opts.Title)
+	line("fullscreen", flag(opts.Fullscreen))
+	line("delete-this-file", flag(opts.DeleteThisFile))
+	// virt-viewer expects the certificate in one line with escaped newlines
+	line("ca", strings.ReplaceAll(strings.TrimSpace(opts.CA), "\n", `\n`))
+	line("host-subject", opts.HostSubject)
+	line("proxy", opts.Proxy)
+	line("toggle-fullscreen", "shift+f11")
+	line("release-cursor", "shift+f12")
+	line("secure-attention", "ctrl+alt+end")
+	return b.Bytes()
+}
+
+// URI returns spice:// or vnc:// uri of the connection with the password in query, e.g. for remote-viewer
+func (c *ConsoleConnection) URI() string {
+	u := url.URL{Scheme: c.Protocol, Host: c.Host}
+	if c.Port != 0 {
+		u.Host = net.JoinHostPort(c.Host, strconv.Itoa(c.Port))
+	}
+	query := url.Values{}
+	if c.TlsPort != 0 {
+		query.Set("tls-port", strconv.Itoa(c.TlsPort))
+	}
+	if password := c.credential(); password != "" {
+		query.Set("password", password)
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// RemoteAccess enables or disables remote console of the domain, the domain is updated in place
+func (d *DomainService) RemoteAccess(domain *DomainObject, enabled bool) (*DomainObject, *http.Response, error) {
+	return d.RemoteAccessContext(context.Background(), domain, enabled)
+}
+
+func (d *DomainService) RemoteAccessContext(ctx context.Context, domain *DomainObject, enabled bool) (*DomainObject, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.RemoteAccess")
+	body := struct {
+		RemoteAccess bool `json:"remote_access"`
+	}{enabled}
+	b, _ := json.Marshal(body)
+	res, err := d.client.ExecuteRequestContext(ctx, "PUT", fmt.Sprint(baseDomainUrl, domain.Id, "/remote-access/"), b, domain)
+	return domain, res, err
+}
+
+// Console returns connection details of domain console by ConsoleProtocol, remote access must be enabled
+func (d *DomainService) Console(Id string, protocol string) (*ConsoleConnection, *http.Response, error) {
+	return d.ConsoleContext(context.Background(), Id, protocol)
+}
+
+func (d *DomainService) ConsoleContext(ctx context.Context, Id string, protocol string) (*ConsoleConnection, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.Console")
+	if protocol != ConsoleProtocol.Spice && protocol != ConsoleProtocol.Vnc {
+		return new(ConsoleConnection), nil, fmt.Errorf("%w: unknown console protocol %q", ErrInvalidConfig, protocol)
+	}
+	connection := new(ConsoleConnection)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", fmt.Sprint(baseDomainUrl, Id, "/", protocol, "/"), []byte{}, connection)
+	if connection.Protocol == "" {
+		connection.Protocol = protocol
+	}
+	return connection, res, err
+}
@@ -0,0 +1,109 @@
+package veil
+
+import (
+	"github.com/jsc-masshtab/veil-api-client-go/veil/veiltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func Test_DomainConsole(t *testing.T) {
+	server, client := newTestServerClient(t)
+	domain, _, err := client.Domain.MultiCreate(DomainMultiCreateConfig{
+		DomainCreateConfig: DomainCreateConfig{VerboseName: "vm"},
+		StartOn:            true,
+	})
+	require.NoError(t, err)
+
+	_, _, err = client.Domain.Console(domain.Id, ConsoleProtocol.Spice)
+	assert.True(t, IsBadRequest(err))
+	_, _, err = client.Domain.RemoteAccess(domain, true)
+	require.NoError(t, err)
+	assert.True(t, domain.RemoteAccess)
+
+	spice, _, err := client.Domain.Console(domain.Id, ConsoleProtocol.Spice)
+	require.NoError(t, err)
+	assert.Equal(t, ConsoleProtocol.Spice, spice.Protocol)
+	assert.Equal(t, "127.0.0.1", spice.Host)
+	assert.Equal(t, veiltest.SpicePort, spice.Port)
+	assert.NotEmpty(t, spice.Password)
+	assert.NotEmpty(t, spice.Ticket)
+	vnc, _, err := client.Domain.Console(domain.Id, ConsoleProtocol.Vnc)
+	require.NoError(t, err)
+	assert.Equal(t, veiltest.VncPort, vnc.Port)
+	assert.Equal(t, spice.Password, vnc.Password)
+	assert.NotEqual(t, spice.Ticket, vnc.Ticket)
+
+	_, _, err = client.Domain.Console(domain.Id, "rdp")
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	_, _, err = client.Domain.RemoteAccess(domain, false)
+	require.NoError(t, err)
+	assert.False(t, domain.RemoteAccess)
+	_, _, err = client.Domain.Console(domain.Id, ConsoleProtocol.Vnc)
+	assert.True(t, IsBadRequest(err))
+	assert.Equal(t, 4, server.CountRequests("GET", "/api/domains/*/*/"))
+	return
+}
+
+func Test_ConsoleConnectionRender(t *testing.T) {
+	connection := &ConsoleConnection{Protocol: ConsoleProtocol.Spice, Host: "10.0.0.5", Port: 5900, TlsPort: 5901,
+		Password: "permanent", Ticket: "t1cket"}
+	file := string(connection.VirtViewerFile(&VirtViewerOptions{
+		Title:          "vm - helpdesk",
+		DeleteThisFile: true,
+		CA:             "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n",
+	}))
+	assert.True(t, strings.HasPrefix(file, "[virt-viewer]\ntype=spice\nhost=10.0.0.5\nport=5900\ntls-port=5901\n"))
+	assert.Contains(t, file, "\npassword=t1cket\n")
+	assert.Contains(t, file, "\ntitle=vm - helpdesk\n")
+	assert.Contains(t, file, "\ndelete-this-file=1\n")
+	assert.Contains(t, file, "\nfullscreen=0\n")
+	assert.Contains(t, file, `ca=-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----`+"\n")
+	assert.NotContains(t, file, "proxy=")
+
+	u, err := url.Parse(connection.URI())
+	require.NoError(t, err)
+	assert.Equal(t, "spice", u.Scheme)
+	assert.Equal(t, "10.0.0.5:5900", u.Host)
+	assert.Equal(t, "5901", u.Query().Get("tls-port"))
+	assert.Equal(t, "t1cket", u.Query().Get("password"))
+
+	connection = &ConsoleConnection{Protocol: ConsoleProtocol.Vnc, Host: "fe80::1", Port: 5910, Password: "secret"}
+	assert.Equal(t, "vnc://[fe80::1]:5910?password=secret", connection.URI())
+	file = string(connection.VirtViewerFile(nil))
+	assert.Contains(t, file, "\ntype=vnc\n")
+	assert.Contains(t, file, "\npassword=secret\n")
+	assert.NotContains(t, file, "tls-port")
+
+	// Values can not add keys to the file
+	connection = &ConsoleConnection{Protocol: ConsoleProtocol.Spice, Host: "10.0.0.5\r\nhost=10.6.6.6", Port: 5900}
+	file = string(connection.VirtViewerFile(&VirtViewerOptions{
+		Title:       "vm\nproxy=http://evil",
+		HostSubject: "CN=vm\r\nproxy=http://evil",
+		CA:          "-----BEGIN CERTIFICATE-----\r\nMIIB\r\n-----END CERTIFICATE-----\r\n",
+	}))
+	assert.NotContains(t, file, "\nproxy=")
+	assert.NotContains(t, file, "\nhost=10.6.6.6")
+	assert.NotContains(t, file, "\r")
+	assert.Contains(t, file, "\ntitle=vmproxy=http://evil\n")
+	assert.Contains(t, file, `ca=-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----`+"\n")
+	// Header and 11 keys
+	assert.Equal(t, 12, strings.Count(file, "\n"))
+	return
+}
+
+func Test_DomainConsoleLive(t *testing.T) {
+	client := liveClient(t)
+	liveEndpoints(t, client, "/api/domains/{id}/remote-access/", "/api/domains/{id}/spice/", "/api/domains/{id}/vnc/")
+	domain := liveDomain(t, client)
+
+	_, _, err := client.Domain.RemoteAccess(domain, true)
+	require.Nil(t, err)
+	assert.True(t, domain.RemoteAccess)
+	_, _, err = client.Domain.RemoteAccess(domain, false)
+	require.Nil(t, err)
+	assert.False(t, domain.RemoteAccess)
+	return
+}
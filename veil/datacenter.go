@@ -1,6 +1,7 @@
 package veil
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -51,34 +52,105 @@ type DataCentersResponse struct {
 }
 
 func (d *DataCenterService) List() (*DataCentersResponse, *http.Response, error) {
+	return d.ListContext(context.Background())
+}
+
+func (d *DataCenterService) ListContext(ctx context.Context) (*DataCentersResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "DataCenterService.List")
 
 	response := new(DataCentersResponse)
 
-	res, err := d.client.ExecuteRequest("GET", baseDataCenterUrl, []byte{}, response)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", baseDataCenterUrl, []byte{}, response)
 
 	return response, res, err
 }
 
 func (d *DataCenterService) ListParams(queryParams map[string]string) (*DataCentersResponse, *http.Response, error) {
-	listUrl := baseDataCenterUrl
-	if len(queryParams) != 0 {
-		params := url.Values{}
-		for k, v := range queryParams {
-			params.Add(k, v)
-		}
-		listUrl += "?"
-		listUrl += params.Encode()
-	}
+	return d.ListParamsContext(context.Background(), queryParams)
+}
+
+func (d *DataCenterService) ListParamsContext(ctx context.Context, queryParams map[string]string) (*DataCentersResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "DataCenterService.ListParams")
+	response := new(DataCentersResponse)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", buildListUrl(baseDataCenterUrl, Params(queryParams)), []byte{}, response)
+	return response, res, err
+}
+
+// DataCenterFilter typed filter of datacenters, zero fields are not sent
+type DataCenterFilter struct {
+	ListOptions
+	Status      []string
+	VerboseName string
+	Tags        []string
+}
+
+// Values encodes the filter to query parameters
+func (f DataCenterFilter) Values() url.Values {
+	params := f.ListOptions.Values()
+	addStrings(params, "status", f.Status)
+	setString(params, "verbose_name", f.VerboseName)
+	addStrings(params, "tags", f.Tags)
+	return params
+}
+
+// ListFiltered returns the first page of datacenters matching filter
+func (d *DataCenterService) ListFiltered(filter DataCenterFilter) (*DataCentersResponse, *http.Response, error) {
+	return d.ListFilteredContext(context.Background(), filter)
+}
+
+// ListFilteredContext returns the first page of datacenters matching filter
+func (d *DataCenterService) ListFilteredContext(ctx context.Context, filter DataCenterFilter) (*DataCentersResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "DataCenterService.ListFiltered")
 	response := new(DataCentersResponse)
-	res, err := d.client.ExecuteRequest("GET", listUrl, []byte{}, response)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", buildListUrl(baseDataCenterUrl, filter), []byte{}, response)
 	return response, res, err
 }
 
+// DataCenterIterator streams datacenters page by page
+type DataCenterIterator struct {
+	*Iterator
+	item DataCenterObjectsList
+}
+
+// Next advances to the next datacenter
+func (it *DataCenterIterator) Next() bool {
+	it.item = DataCenterObjectsList{}
+	return it.Iterator.Next() && it.decode(&it.item)
+}
+
+// Item returns the current datacenter
+func (it *DataCenterIterator) Item() DataCenterObjectsList {
+	return it.item
+}
+
+// Iter returns an iterator over all datacenters matching filter, filter and opts may be nil
+func (d *DataCenterService) Iter(ctx context.Context, filter ListFilter, opts *PageOptions) *DataCenterIterator {
+	ctx = withOperation(ctx, "DataCenterService.Iter")
+	return &DataCenterIterator{Iterator: newIterator(ctx, d.client, baseDataCenterUrl, filter, opts)}
+}
+
+// ListAll returns all datacenters matching filter following the pages until exhausted
+func (d *DataCenterService) ListAll(ctx context.Context, filter ListFilter) ([]DataCenterObjectsList, error) {
+	ctx = withOperation(ctx, "DataCenterService.ListAll")
+	it := d.Iter(ctx, filter, nil)
+	defer it.Close()
+	var items []DataCenterObjectsList
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
+
 func (d *DataCenterService) Get(Id string) (*DataCenterObject, *http.Response, error) {
+	return d.GetContext(context.Background(), Id)
+}
+
+func (d *DataCenterService) GetContext(ctx context.Context, Id string) (*DataCenterObject, *http.Response, error) {
+	ctx = withOperation(ctx, "DataCenterService.Get")
 
 	entity := new(DataCenterObject)
 
-	res, err := d.client.ExecuteRequest("GET", fmt.Sprint(baseDataCenterUrl, Id, "/"), []byte{}, entity)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", fmt.Sprint(baseDataCenterUrl, Id, "/"), []byte{}, entity)
 
 	return entity, res, err
 }
@@ -6,7 +6,7 @@ import (
 )
 
 func Test_DatacenterListGet(t *testing.T) {
-	client := NewClient("", "", false)
+	_, client := newTestServerClient(t)
 	response, _, err := client.DataCenter.List()
 	assert.Nil(t, err)
 	for _, v := range response.Results {
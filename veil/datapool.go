@@ -1,6 +1,7 @@
 package veil
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -71,40 +72,121 @@ type DataPoolsResponse struct {
 	Results []DataPoolObjectsList `json:"results,omitempty"`
 }
 
-func (entity *DataPoolObject) Refresh(client *WebClient) (*DataPoolObject, error) {
-	_, err := client.ExecuteRequest("GET", fmt.Sprint(baseDataPoolUrl, entity.Id, "/"), []byte{}, entity)
+func (entity *DataPoolObject) Refresh(client Client) (*DataPoolObject, error) {
+	return entity.RefreshContext(context.Background(), client)
+}
+
+func (entity *DataPoolObject) RefreshContext(ctx context.Context, client Client) (*DataPoolObject, error) {
+	_, err := client.ExecuteRequestContext(ctx, "GET", fmt.Sprint(baseDataPoolUrl, entity.Id, "/"), []byte{}, entity)
 	return entity, err
 }
 
 func (d *DataPoolService) List() (*DataPoolsResponse, *http.Response, error) {
+	return d.ListContext(context.Background())
+}
+
+func (d *DataPoolService) ListContext(ctx context.Context) (*DataPoolsResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "DataPoolService.List")
 
 	response := new(DataPoolsResponse)
 
-	res, err := d.client.ExecuteRequest("GET", baseDataPoolUrl, []byte{}, response)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", baseDataPoolUrl, []byte{}, response)
 
 	return response, res, err
 }
 
 func (d *DataPoolService) ListParams(queryParams map[string]string) (*DataPoolsResponse, *http.Response, error) {
-	listUrl := baseDataPoolUrl
-	if len(queryParams) != 0 {
-		params := url.Values{}
-		for k, v := range queryParams {
-			params.Add(k, v)
-		}
-		listUrl += "?"
-		listUrl += params.Encode()
-	}
+	return d.ListParamsContext(context.Background(), queryParams)
+}
+
+func (d *DataPoolService) ListParamsContext(ctx context.Context, queryParams map[string]string) (*DataPoolsResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "DataPoolService.ListParams")
+	response := new(DataPoolsResponse)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", buildListUrl(baseDataPoolUrl, Params(queryParams)), []byte{}, response)
+	return response, res, err
+}
+
+// DataPoolFilter typed filter of datapools, zero fields are not sent
+type DataPoolFilter struct {
+	ListOptions
+	Status      []string
+	VerboseName string
+	Type        []string
+	Node        string
+	Cluster     string
+	Tags        []string
+}
+
+// Values encodes the filter to query parameters
+func (f DataPoolFilter) Values() url.Values {
+	params := f.ListOptions.Values()
+	addStrings(params, "status", f.Status)
+	setString(params, "verbose_name", f.VerboseName)
+	addStrings(params, "type", f.Type)
+	setString(params, "node", f.Node)
+	setString(params, "cluster", f.Cluster)
+	addStrings(params, "tags", f.Tags)
+	return params
+}
+
+// ListFiltered returns the first page of datapools matching filter
+func (d *DataPoolService) ListFiltered(filter DataPoolFilter) (*DataPoolsResponse, *http.Response, error) {
+	return d.ListFilteredContext(context.Background(), filter)
+}
+
+// ListFilteredContext returns the first page of datapools matching filter
+func (d *DataPoolService) ListFilteredContext(ctx context.Context, filter DataPoolFilter) (*DataPoolsResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "DataPoolService.ListFiltered")
 	response := new(DataPoolsResponse)
-	res, err := d.client.ExecuteRequest("GET", listUrl, []byte{}, response)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", buildListUrl(baseDataPoolUrl, filter), []byte{}, response)
 	return response, res, err
 }
 
+// DataPoolIterator streams datapools page by page
+type DataPoolIterator struct {
+	*Iterator
+	item DataPoolObjectsList
+}
+
+// Next advances to the next datapool
+func (it *DataPoolIterator) Next() bool {
+	it.item = DataPoolObjectsList{}
+	return it.Iterator.Next() && it.decode(&it.item)
+}
+
+// Item returns the current datapool
+func (it *DataPoolIterator) Item() DataPoolObjectsList {
+	return it.item
+}
+
+// Iter returns an iterator over all datapools matching filter, filter and opts may be nil
+func (d *DataPoolService) Iter(ctx context.Context, filter ListFilter, opts *PageOptions) *DataPoolIterator {
+	ctx = withOperation(ctx, "DataPoolService.Iter")
+	return &DataPoolIterator{Iterator: newIterator(ctx, d.client, baseDataPoolUrl, filter, opts)}
+}
+
+// ListAll returns all datapools matching filter following the pages until exhausted
+func (d *DataPoolService) ListAll(ctx context.Context, filter ListFilter) ([]DataPoolObjectsList, error) {
+	ctx = withOperation(ctx, "DataPoolService.ListAll")
+	it := d.Iter(ctx, filter, nil)
+	defer it.Close()
+	var items []DataPoolObjectsList
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
+
 func (d *DataPoolService) Get(Id string) (*DataPoolObject, *http.Response, error) {
+	return d.GetContext(context.Background(), Id)
+}
+
+func (d *DataPoolService) GetContext(ctx context.Context, Id string) (*DataPoolObject, *http.Response, error) {
+	ctx = withOperation(ctx, "DataPoolService.Get")
 
 	entity := new(DataPoolObject)
 
-	res, err := d.client.ExecuteRequest("GET", fmt.Sprint(baseDataPoolUrl, Id, "/"), []byte{}, entity)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", fmt.Sprint(baseDataPoolUrl, Id, "/"), []byte{}, entity)
 
 	return entity, res, err
 }
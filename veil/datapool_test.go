@@ -6,7 +6,7 @@ import (
 )
 
 func Test_DataPoolListGet(t *testing.T) {
-	client := NewClient("", "", false)
+	_, client := newTestServerClient(t)
 
 	response, _, err := client.DataPool.List()
 	assert.Nil(t, err)
@@ -0,0 +1,193 @@
+package veil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type NameIso struct {
+	Id       string `json:"id,omitempty"`
+	FileName string `json:"filename,omitempty"`
+}
+
+// CdromObject cdrom device of a domain, Iso is empty if the cdrom has no disc
+type CdromObject struct {
+	Id        string     `json:"id,omitempty"`
+	TargetBus string     `json:"target_bus,omitempty"`
+	Iso       NameIso    `json:"iso,omitempty"`
+	Domain    NameDomain `json:"domain,omitempty"`
+}
+
+type CdromsResponse struct {
+	BaseListResponse
+	Results []CdromObject `json:"results,omitempty"`
+}
+
+// Validate checks bus and cache values against TargetBuses and CacheTypes, errors wrap ErrInvalidConfig
+func (config VdiskBusCache) Validate() error {
+	if err := validatePattern("target_bus", TargetBuses, config.TargetBus); err != nil {
+		return err
+	}
+	return validatePattern("driver_cache", CacheTypes, config.DriverCache)
+}
+
+// Validate checks nic driver against NicDriverTypes, errors wrap ErrInvalidConfig
+func (config VMachineInfSoftCreate) Validate() error {
+	return validatePattern("nic_driver", NicDriverTypes, config.NicDriver)
+}
+
+// AttachVdisk attaches existing virtual disk to the domain, running domain gets it without reboot
+func (d *DomainService) AttachVdisk(Id string, config VdiskAttach) (*DomainObject, *http.Response, error) {
+	return d.AttachVdiskContext(context.Background(), Id, config)
+}
+
+func (d *DomainService) AttachVdiskContext(ctx context.Context, Id string, config VdiskAttach) (*DomainObject, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.AttachVdisk")
+	if err := config.Validate(); err != nil {
+		return new(DomainObject), nil, err
+	}
+	return d.deviceAction(ctx, Id, "/attach-vdisk/", config)
+}
+
+func (d *DomainService) DetachVdisk(Id string, vdiskId string) (*DomainObject, *http.Response, error) {
+	return d.DetachVdiskContext(context.Background(), Id, vdiskId)
+}
+
+func (d *DomainService) DetachVdiskContext(ctx context.Context, Id string, vdiskId string) (*DomainObject, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.DetachVdisk")
+	body := struct {
+		Vdisk string `json:"vdisk"`
+	}{vdiskId}
+	return d.deviceAction(ctx, Id, "/detach-vdisk/", body)
+}
+
+// CreateAttachVdisk creates virtual disk and attaches it to the domain
+func (d *DomainService) CreateAttachVdisk(Id string, config VdiskCreateAttach) (*VdiskObject, *http.Response, error) {
+	return d.CreateAttachVdiskContext(context.Background(), Id, config)
+}
+
+func (d *DomainService) CreateAttachVdiskContext(ctx context.Context, Id string, config VdiskCreateAttach) (*VdiskObject, *http.Response, error) {
+	ctx, finish := startOperation(ctx, d.client, "DomainService.CreateAttachVdisk", Id)
+	op, res, err := d.StartCreateAttachVdisk(ctx, Id, config)
+	if err != nil {
+		finish(err)
+		return new(VdiskObject), res, err
+	}
+	vdisk, err := op.Wait(ctx)
+	finish(err)
+	return vdisk, op.response(res), err
+}
+
+// StartCreateAttachVdisk starts creation of attached virtual disk and returns without waiting for the task
+func (d *DomainService) StartCreateAttachVdisk(ctx context.Context, Id string, config VdiskCreateAttach) (*VdiskOperation, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.StartCreateAttachVdisk")
+	if err := config.VdiskBusCache.Validate(); err != nil {
+		return nil, nil, err
+	}
+	ctx = config.IdempotencyKeyBase.apply(ctx)
+	b, _ := json.Marshal(config)
+	asyncResp := new(AsyncEntityResponse)
+	res, err := d.client.ExecuteRequestContext(ctx, "POST", fmt.Sprint(baseDomainUrl, Id, "/create-attach-vdisk/?async=1"), b, asyncResp)
+	if err != nil {
+		return nil, res, err
+	}
+	op, err := newVdiskOperation(d.client, OperationState{OperationKind.VdiskCreateAttach, asyncResp.Task.Id, asyncResp.Entity})
+	return op, res, err
+}
+
+// InsertIso inserts ISO into the cdrom, a new cdrom is added if config.Cdrom is empty
+func (d *DomainService) InsertIso(Id string, config IsoAttach) (*DomainObject, *http.Response, error) {
+	return d.InsertIsoContext(context.Background(), Id, config)
+}
+
+func (d *DomainService) InsertIsoContext(ctx context.Context, Id string, config IsoAttach) (*DomainObject, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.InsertIso")
+	return d.deviceAction(ctx, Id, "/attach-iso/", config)
+}
+
+// EjectIso removes ISO from the cdrom, the cdrom stays attached
+func (d *DomainService) EjectIso(Id string, cdromId string) (*DomainObject, *http.Response, error) {
+	return d.EjectIsoContext(context.Background(), Id, cdromId)
+}
+
+func (d *DomainService) EjectIsoContext(ctx context.Context, Id string, cdromId string) (*DomainObject, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.EjectIso")
+	body := struct {
+		Cdrom string `json:"cdrom"`
+	}{cdromId}
+	return d.deviceAction(ctx, Id, "/detach-iso/", body)
+}
+
+// AddInterface adds network interface connected to config.Vnetwork
+func (d *DomainService) AddInterface(Id string, config VMachineInfSoftCreate) (*VMachineInfObject, *http.Response, error) {
+	return d.AddInterfaceContext(context.Background(), Id, config)
+}
+
+func (d *DomainService) AddInterfaceContext(ctx context.Context, Id string, config VMachineInfSoftCreate) (*VMachineInfObject, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.AddInterface")
+	if err := config.Validate(); err != nil {
+		return new(VMachineInfObject), nil, err
+	}
+	entity := new(VMachineInfObject)
+	b, _ := json.Marshal(config)
+	res, err := d.client.ExecuteRequestContext(ctx, "POST", fmt.Sprint(baseDomainUrl, Id, "/add-vmachine-inf/"), b, entity)
+	return entity, res, err
+}
+
+func (d *DomainService) RemoveInterface(Id string, vmachineInfId string) (*DomainObject, *http.Response, error) {
+	return d.RemoveInterfaceContext(context.Background(), Id, vmachineInfId)
+}
+
+func (d *DomainService) RemoveInterfaceContext(ctx context.Context, Id string, vmachineInfId string) (*DomainObject, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.RemoveInterface")
+	body := struct {
+		VmachineInf string `json:"vmachine_inf"`
+	}{vmachineInfId}
+	return d.deviceAction(ctx, Id, "/remove-vmachine-inf/", body)
+}
+
+// RelinkInterface connects network interface of the domain to another virtual network keeping its mac address
+func (d *DomainService) RelinkInterface(Id string, vmachineInfId string, vnetworkId string) (*VMachineInfObject, *http.Response, error) {
+	return d.RelinkInterfaceContext(context.Background(), Id, vmachineInfId, vnetworkId)
+}
+
+func (d *DomainService) RelinkInterfaceContext(ctx context.Context, Id string, vmachineInfId string, vnetworkId string) (*VMachineInfObject, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.RelinkInterface")
+	entity := new(VMachineInfObject)
+	body := struct {
+		VmachineInf string `json:"vmachine_inf"`
+		Vnetwork    string `json:"vnetwork"`
+	}{vmachineInfId, vnetworkId}
+	b, _ := json.Marshal(body)
+	res, err := d.client.ExecuteRequestContext(ctx, "POST", fmt.Sprint(baseDomainUrl, Id, "/relink-vmachine-inf/"), b, entity)
+	return entity, res, err
+}
+
+// ListVdisks returns all virtual disks attached to the domain
+func (d *DomainService) ListVdisks(ctx context.Context, Id string) ([]VdiskObjectsList, error) {
+	ctx = withOperation(ctx, "DomainService.ListVdisks")
+	return NewVdiskService(d.client).ListAll(ctx, VdiskFilter{Domain: Id})
+}
+
+// ListCdroms returns cdroms of the domain
+func (d *DomainService) ListCdroms(ctx context.Context, Id string) ([]CdromObject, error) {
+	ctx = withOperation(ctx, "DomainService.ListCdroms")
+	response := new(CdromsResponse)
+	_, err := d.client.ExecuteRequestContext(ctx, "GET", fmt.Sprint(baseDomainUrl, Id, "/cdroms/"), []byte{}, response)
+	return response.Results, err
+}
+
+// ListInterfaces returns all network interfaces of the domain
+func (d *DomainService) ListInterfaces(ctx context.Context, Id string) ([]VMachineInfObjectsList, error) {
+	ctx = withOperation(ctx, "DomainService.ListInterfaces")
+	return NewVMachineInfService(d.client).ListAll(ctx, VMachineInfFilter{Vmachine: Id})
+}
+
+func (d *DomainService) deviceAction(ctx context.Context, Id string, action string, body interface{}) (*DomainObject, *http.Response, error) {
+	domain := new(DomainObject)
+	b, _ := json.Marshal(body)
+	res, err := d.client.ExecuteRequestContext(ctx, "POST", fmt.Sprint(baseDomainUrl, Id, action), b, domain)
+	return domain, res, err
+}
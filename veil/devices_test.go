@@ -0,0 +1,149 @@
+package veil
+
+import (
+	"context"
+	"github.com/jsc-masshtab/veil-api-client-go/veil/veiltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func Test_DomainVdiskHotPlug(t *testing.T) {
+	server, client := newTestServerClient(t)
+	ctx := context.Background()
+	domain, _, err := client.Domain.Create(DomainCreateConfig{VerboseName: "vm"})
+	require.NoError(t, err)
+	vdisk, _, err := client.Vdisk.Create(&VdiskCreate{VerboseName: "data", Size: 10}, false)
+	require.NoError(t, err)
+
+	_, _, err = client.Domain.AttachVdisk(domain.Id, VdiskAttach{Vdisk: vdisk.Id, VdiskBusCache: VdiskBusCache{TargetBus: "usb"}})
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	assert.Equal(t, 0, server.CountRequests("POST", "/api/domains/*/attach-vdisk/"))
+	domain, _, err = client.Domain.AttachVdisk(domain.Id, VdiskAttach{Vdisk: vdisk.Id,
+		VdiskBusCache: VdiskBusCache{TargetBus: "scsi", DriverCache: "writeback"}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, domain.VdisksCount)
+	_, _, err = client.Domain.AttachVdisk(domain.Id, VdiskAttach{Vdisk: vdisk.Id})
+	assert.True(t, IsBadRequest(err))
+	stored, _ := server.Get(veiltest.Vdisks, vdisk.Id)
+	assert.Equal(t, "writeback", stored["driver_cache"])
+
+	created, _, err := client.Domain.CreateAttachVdisk(domain.Id, VdiskCreateAttach{
+		VdiskCreate:   VdiskCreate{VerboseName: "logs", Size: 5},
+		VdiskBusCache: VdiskBusCache{TargetBus: "virtio"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "logs", created.VerboseName)
+	assert.Equal(t, Status.Active, created.Status)
+
+	vdisks, err := client.Domain.ListVdisks(ctx, domain.Id)
+	require.NoError(t, err)
+	assert.Len(t, vdisks, 2)
+	domain, _, err = client.Domain.DetachVdisk(domain.Id, vdisk.Id)
+	require.NoError(t, err)
+	assert.Equal(t, 1, domain.VdisksCount)
+	_, _, err = client.Domain.DetachVdisk(domain.Id, vdisk.Id)
+	assert.True(t, IsBadRequest(err))
+	vdisks, err = client.Domain.ListVdisks(ctx, domain.Id)
+	require.NoError(t, err)
+	require.Len(t, vdisks, 1)
+	assert.Equal(t, created.Id, vdisks[0].Id)
+	return
+}
+
+func Test_DomainIsoHotPlug(t *testing.T) {
+	_, client := newTestServerClient(t)
+	ctx := context.Background()
+	domain, _, err := client.Domain.Create(DomainCreateConfig{VerboseName: "vm"})
+	require.NoError(t, err)
+	iso, err := client.Iso.Create(veiltest.DataPoolId, "https://example.com/images/install.iso", 0)
+	require.NoError(t, err)
+
+	_, _, err = client.Domain.InsertIso(domain.Id, IsoAttach{IsoSoftAttach: IsoSoftAttach{Iso: iso.Id}})
+	require.NoError(t, err)
+	cdroms, err := client.Domain.ListCdroms(ctx, domain.Id)
+	require.NoError(t, err)
+	require.Len(t, cdroms, 1)
+	assert.Equal(t, "install.iso", cdroms[0].Iso.FileName)
+
+	_, _, err = client.Domain.EjectIso(domain.Id, cdroms[0].Id)
+	require.NoError(t, err)
+	cdroms, err = client.Domain.ListCdroms(ctx, domain.Id)
+	require.NoError(t, err)
+	require.Len(t, cdroms, 1)
+	assert.Equal(t, "", cdroms[0].Iso.Id)
+
+	_, _, err = client.Domain.InsertIso(domain.Id, IsoAttach{IsoSoftAttach: IsoSoftAttach{Iso: iso.Id}, Cdrom: cdroms[0].Id})
+	require.NoError(t, err)
+	_, _, err = client.Domain.EjectIso(domain.Id, "missing")
+	assert.True(t, IsBadRequest(err))
+	cdroms, err = client.Domain.ListCdroms(ctx, domain.Id)
+	require.NoError(t, err)
+	require.Len(t, cdroms, 1)
+	assert.Equal(t, iso.Id, cdroms[0].Iso.Id)
+	return
+}
+
+func Test_DomainInterfaceHotPlug(t *testing.T) {
+	server, client := newTestServerClient(t)
+	ctx := context.Background()
+	vnet := server.Add(veiltest.Vnets, veiltest.Object{"verbose_name": "lab"})
+	domain, _, err := client.Domain.Create(DomainCreateConfig{VerboseName: "vm"})
+	require.NoError(t, err)
+
+	_, _, err = client.Domain.AddInterface(domain.Id, VMachineInfSoftCreate{Vnetwork: veiltest.VnetId, NicDriver: "ne2k"})
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	inf, _, err := client.Domain.AddInterface(domain.Id, VMachineInfSoftCreate{Vnetwork: veiltest.VnetId, NicDriver: "e1000"})
+	require.NoError(t, err)
+	assert.Equal(t, "e1000", inf.NicDriver)
+	assert.Equal(t, veiltest.VnetId, inf.VnetworkInfo.Id)
+	assert.NotEmpty(t, inf.MacAddress)
+	_, _, err = client.Domain.AddInterface(domain.Id, VMachineInfSoftCreate{Vnetwork: veiltest.VnetId})
+	require.NoError(t, err)
+
+	relinked, _, err := client.Domain.RelinkInterface(domain.Id, inf.Id, vnet["id"].(string))
+	require.NoError(t, err)
+	assert.Equal(t, vnet["id"], relinked.VnetworkInfo.Id)
+	assert.Equal(t, inf.MacAddress, relinked.MacAddress)
+
+	interfaces, err := client.Domain.ListInterfaces(ctx, domain.Id)
+	require.NoError(t, err)
+	assert.Len(t, interfaces, 2)
+	domain, _, err = client.Domain.RemoveInterface(domain.Id, inf.Id)
+	require.NoError(t, err)
+	assert.Equal(t, 1, domain.VmachineInfsCount)
+	_, _, err = client.Domain.RemoveInterface(domain.Id, inf.Id)
+	assert.True(t, IsBadRequest(err))
+	interfaces, err = client.Domain.ListInterfaces(ctx, domain.Id)
+	require.NoError(t, err)
+	require.Len(t, interfaces, 1)
+	assert.Equal(t, "virtio", interfaces[0].NicDriver)
+	return
+}
+
+func Test_DomainDevicesLive(t *testing.T) {
+	client := liveClient(t)
+	liveEndpoints(t, client, "/api/domains/{id}/attach-vdisk/", "/api/domains/{id}/detach-vdisk/",
+		"/api/domains/{id}/create-attach-vdisk/", "/api/domains/{id}/attach-iso/", "/api/domains/{id}/detach-iso/",
+		"/api/domains/{id}/add-vmachine-inf/", "/api/domains/{id}/remove-vmachine-inf/",
+		"/api/domains/{id}/relink-vmachine-inf/", "/api/domains/{id}/cdroms/")
+	domain := liveDomain(t, client)
+	ctx := context.Background()
+	_, err := client.Domain.ListCdroms(ctx, domain.Id)
+	require.Nil(t, err)
+	vnets, _, err := client.Vnet.List()
+	require.Nil(t, err)
+	if len(vnets.Results) == 0 {
+		t.Skip("interfaces need a vnet")
+	}
+
+	inf, _, err := client.Domain.AddInterface(domain.Id, VMachineInfSoftCreate{Vnetwork: vnets.Results[0].Id, NicDriver: "virtio"})
+	require.Nil(t, err)
+	interfaces, err := client.Domain.ListInterfaces(ctx, domain.Id)
+	require.Nil(t, err)
+	require.Len(t, interfaces, 1)
+	assert.Equal(t, inf.Id, interfaces[0].Id)
+	_, _, err = client.Domain.RemoveInterface(domain.Id, inf.Id)
+	require.Nil(t, err)
+	return
+}
@@ -1,9 +1,9 @@
 package veil
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
 	"net/url"
 	"time"
@@ -62,6 +62,8 @@ type DomainObject struct {
 	Status             string           `json:"status,omitempty"`
 	Parent             NameDomain       `json:"parent,omitempty"`
 	CpuCount           int              `json:"cpu_count,omitempty"`
+	CpuCountMax        int              `json:"cpu_count_max,omitempty"`
+	MemoryCountMax     int              `json:"memory_count_max,omitempty"`
 	MemoryPool         string           `json:"memory_pool,omitempty"`
 	VmachineInfsCount  int              `json:"vmachine_infs_count,omitempty"`
 	VdisksCount        int              `json:"vdisks_count,omitempty"`
@@ -80,6 +82,7 @@ type DomainObject struct {
 	CpuUsedPercentUser string           `json:"cpu_used_percent_user,omitempty"`
 	MemUsedPercentUser string           `json:"mem_used_percent_user,omitempty"`
 	Priority           int              `json:"priority,omitempty"`
+	RemoteAccess       bool             `json:"remote_access,omitempty"`
 }
 
 type DomainsResponse struct {
@@ -91,6 +94,17 @@ const MachineTypes = `(pc|q35)`
 const CpuModes = `(default|host-model|host-passthrough|custom)`
 const CleanTypes = `(zero|urandom)`
 
+type PowerStateStruct struct {
+	Off, Suspended, On int
+}
+
+// PowerState values of DomainObject.UserPowerState
+var PowerState = PowerStateStruct{
+	Off:       1,
+	Suspended: 2,
+	On:        3,
+}
+
 type SshInject struct {
 	CreateUser bool   `json:"create_user,omitempty"`
 	SshUser    string `json:"ssh_user,omitempty"`
@@ -194,12 +208,14 @@ type DomainUpdateConfig struct {
 }
 
 type DomainCloneConfig struct {
+	IdempotencyKeyBase
 	CloudInitConf
 	Node         string `json:"node,omitempty"`
 	ResourcePool string `json:"resource_pool,omitempty"`
 	VerboseName  string `json:"verbose_name,omitempty"`
 	DataPool     string `json:"datapool,omitempty"`
-	// snapshot
+	// Snapshot id of the source domain snapshot to clone from instead of its current state
+	Snapshot    string   `json:"snapshot,omitempty"`
 	Count       int      `json:"count,omitempty"`
 	DomainsIds  []string `json:"domains_ids,omitempty"`
 	StartOn     bool     `json:"start_on,omitempty"`
@@ -207,160 +223,351 @@ type DomainCloneConfig struct {
 	Replication bool     `json:"replication,omitempty"`
 }
 
-func (entity *DomainObject) Refresh(client *WebClient) (*DomainObject, error) {
-	_, err := client.ExecuteRequest("GET", fmt.Sprint(baseDomainUrl, entity.Id, "/"), []byte{}, entity)
+func (entity *DomainObject) Refresh(client Client) (*DomainObject, error) {
+	return entity.RefreshContext(context.Background(), client)
+}
+
+func (entity *DomainObject) RefreshContext(ctx context.Context, client Client) (*DomainObject, error) {
+	_, err := client.ExecuteRequestContext(ctx, "GET", fmt.Sprint(baseDomainUrl, entity.Id, "/"), []byte{}, entity)
 	return entity, err
 }
 
-func (entity *DomainObject) WaitForGA(client *WebClient, timeout int64) (*DomainObject, error) {
+func (entity *DomainObject) WaitForGA(client Client, timeout int64) (*DomainObject, error) {
+	return entity.WaitForGAContext(context.Background(), client, timeout)
+}
+
+// WaitForGAContext is like WaitForGA but stops waiting and returns ctx.Err() once ctx is done
+func (entity *DomainObject) WaitForGAContext(ctx context.Context, client Client, timeout int64) (*DomainObject, error) {
+	ctx, finish := startOperation(ctx, client, "DomainObject.WaitForGA", entity.Id)
+	_, err := entity.waitForGA(ctx, client, timeout)
+	finish(err)
+	return entity, err
+}
+
+func (entity *DomainObject) waitForGA(ctx context.Context, client Client, timeout int64) (*DomainObject, error) {
 	if timeout == 0 {
 		timeout = 420
 	}
 	timeStart := time.Now().Unix()
 	for true {
-		_, err := client.ExecuteRequest("GET", fmt.Sprint(baseDomainUrl, entity.Id, "/"), []byte{}, entity)
+		_, err := client.ExecuteRequestContext(ctx, "GET", fmt.Sprint(baseDomainUrl, entity.Id, "/"), []byte{}, entity)
 		if err != nil {
 			return entity, err
 		}
 		if entity.GuestUtils.QemuState == true {
-			log.Printf("successfully waiting guest agent of domain %s", entity.VerboseName)
+			loggerOf(client).Log(ctx, LevelInfo, "veil guest agent is ready", "domain_id", entity.Id, "domain", entity.VerboseName)
 			return entity, nil
 		}
-		time.Sleep(time.Second * 5)
+		if err := sleepContext(ctx, time.Second*5); err != nil {
+			return entity, err
+		}
 		timeNow := time.Now().Unix()
 		if timeNow > timeStart+timeout {
 			errMsg := fmt.Sprintf("waiting guest agent timeout error for domain %s.", entity.VerboseName)
 			return entity, fmt.Errorf(errMsg)
 		}
-		log.Printf("waiting %d sec for guest agent of domain %s (max %dsec)", timeNow-timeStart, entity.VerboseName, timeout)
+		loggerOf(client).Log(ctx, LevelDebug, "veil waiting guest agent", "domain_id", entity.Id, "domain", entity.VerboseName,
+			"elapsed_sec", timeNow-timeStart, "timeout_sec", timeout)
 	}
 
 	return entity, nil
 }
 
 func (d *DomainService) List() (*DomainsResponse, *http.Response, error) {
+	return d.ListContext(context.Background())
+}
+
+func (d *DomainService) ListContext(ctx context.Context) (*DomainsResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.List")
 	response := new(DomainsResponse)
-	res, err := d.client.ExecuteRequest("GET", baseDomainUrl, []byte{}, response)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", baseDomainUrl, []byte{}, response)
 	return response, res, err
 }
 
 func (d *DomainService) ListParams(queryParams map[string]string) (*DomainsResponse, *http.Response, error) {
-	listUrl := baseDomainUrl
-	if len(queryParams) != 0 {
-		params := url.Values{}
-		for k, v := range queryParams {
-			params.Add(k, v)
-		}
-		listUrl += "?"
-		listUrl += params.Encode()
-	}
+	return d.ListParamsContext(context.Background(), queryParams)
+}
+
+func (d *DomainService) ListParamsContext(ctx context.Context, queryParams map[string]string) (*DomainsResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.ListParams")
 	response := new(DomainsResponse)
-	res, err := d.client.ExecuteRequest("GET", listUrl, []byte{}, response)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", buildListUrl(baseDomainUrl, Params(queryParams)), []byte{}, response)
 	return response, res, err
 }
 
 func (d *DomainService) Create(config DomainCreateConfig) (*DomainObject, *http.Response, error) {
+	return d.CreateContext(context.Background(), config)
+}
+
+func (d *DomainService) CreateContext(ctx context.Context, config DomainCreateConfig) (*DomainObject, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.Create")
 	domain := new(DomainObject)
+	ctx = config.IdempotencyKeyBase.apply(ctx)
 	b, _ := json.Marshal(config)
-	res, err := d.client.ExecuteRequest("POST", baseDomainUrl, b, domain)
+	res, err := d.client.ExecuteRequestContext(ctx, "POST", baseDomainUrl, b, domain)
 	return domain, res, err
 }
 
 func (d *DomainService) MultiCreate(config DomainMultiCreateConfig) (*DomainObject, *http.Response, error) {
-	domain := new(DomainObject)
+	return d.MultiCreateContext(context.Background(), config)
+}
+
+func (d *DomainService) MultiCreateContext(ctx context.Context, config DomainMultiCreateConfig) (*DomainObject, *http.Response, error) {
+	ctx, finish := startOperation(ctx, d.client, "DomainService.MultiCreate", "")
+	op, res, err := d.StartMultiCreate(ctx, config)
+	if err != nil {
+		finish(err)
+		return new(DomainObject), res, err
+	}
+	domain, err := op.Wait(ctx)
+	finish(err)
+	return domain, op.response(res), err
+}
+
+// StartMultiCreate starts domain creation and returns without waiting for the task
+func (d *DomainService) StartMultiCreate(ctx context.Context, config DomainMultiCreateConfig) (*DomainOperation, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.StartMultiCreate")
+	ctx = config.IdempotencyKeyBase.apply(ctx)
 	b, _ := json.Marshal(config)
 	asyncResp := new(AsyncEntityResponse)
-	res, err := d.client.ExecuteRequest("POST", fmt.Sprint(baseDomainUrl, "multi-create-domain/?async=1"), b, asyncResp)
+	res, err := d.client.ExecuteRequestContext(ctx, "POST", fmt.Sprint(baseDomainUrl, "multi-create-domain/?async=1"), b, asyncResp)
 	if err != nil {
-		return domain, res, err
+		return nil, res, err
 	}
-	WaitTaskReady(d.client.RetClient(), asyncResp.Task.Id, true, 0, true)
-	res, err = d.client.ExecuteRequest("GET", fmt.Sprint(baseDomainUrl, asyncResp.Entity, "/"), []byte{}, domain)
-	return domain, res, err
+	op, err := newDomainOperation(d.client, OperationState{OperationKind.DomainMultiCreate, asyncResp.Task.Id, asyncResp.Entity})
+	return op, res, err
+}
+
+// DomainFilter typed filter of domains, zero fields are not sent
+type DomainFilter struct {
+	ListOptions
+	Status       []string
+	Name         string
+	Node         string
+	Cluster      string
+	Datapool     string
+	ResourcePool string
+	Template     *bool
+	Parent       string
+	Tags         []string
+}
+
+// Values encodes the filter to query parameters
+func (f DomainFilter) Values() url.Values {
+	params := f.ListOptions.Values()
+	addStrings(params, "status", f.Status)
+	setString(params, "name", f.Name)
+	setString(params, "node", f.Node)
+	setString(params, "cluster", f.Cluster)
+	setString(params, "datapool", f.Datapool)
+	setString(params, "resource_pool", f.ResourcePool)
+	setBool(params, "template", f.Template)
+	setString(params, "parent", f.Parent)
+	addStrings(params, "tags", f.Tags)
+	return params
+}
+
+// ListFiltered returns the first page of domains matching filter
+func (d *DomainService) ListFiltered(filter DomainFilter) (*DomainsResponse, *http.Response, error) {
+	return d.ListFilteredContext(context.Background(), filter)
+}
+
+// ListFilteredContext returns the first page of domains matching filter
+func (d *DomainService) ListFilteredContext(ctx context.Context, filter DomainFilter) (*DomainsResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.ListFiltered")
+	response := new(DomainsResponse)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", buildListUrl(baseDomainUrl, filter), []byte{}, response)
+	return response, res, err
+}
+
+// DomainIterator streams domains page by page
+type DomainIterator struct {
+	*Iterator
+	item DomainObjectsList
+}
+
+// Next advances to the next domain
+func (it *DomainIterator) Next() bool {
+	it.item = DomainObjectsList{}
+	return it.Iterator.Next() && it.decode(&it.item)
+}
+
+// Item returns the current domain
+func (it *DomainIterator) Item() DomainObjectsList {
+	return it.item
+}
+
+// Iter returns an iterator over all domains matching filter, filter and opts may be nil
+func (d *DomainService) Iter(ctx context.Context, filter ListFilter, opts *PageOptions) *DomainIterator {
+	ctx = withOperation(ctx, "DomainService.Iter")
+	return &DomainIterator{Iterator: newIterator(ctx, d.client, baseDomainUrl, filter, opts)}
+}
+
+// ListAll returns all domains matching filter following the pages until exhausted
+func (d *DomainService) ListAll(ctx context.Context, filter ListFilter) ([]DomainObjectsList, error) {
+	ctx = withOperation(ctx, "DomainService.ListAll")
+	it := d.Iter(ctx, filter, nil)
+	defer it.Close()
+	var items []DomainObjectsList
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
 }
 
 func (d *DomainService) Get(Id string) (*DomainObject, *http.Response, error) {
+	return d.GetContext(context.Background(), Id)
+}
+
+func (d *DomainService) GetContext(ctx context.Context, Id string) (*DomainObject, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.Get")
 	entity := new(DomainObject)
-	res, err := d.client.ExecuteRequest("GET", fmt.Sprint(baseDomainUrl, Id, "/"), []byte{}, entity)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", fmt.Sprint(baseDomainUrl, Id, "/"), []byte{}, entity)
 	return entity, res, err
 }
 
 func (d *DomainService) Update(Id string, config DomainUpdateConfig) (*DomainObject, *http.Response, error) {
+	return d.UpdateContext(context.Background(), Id, config)
+}
+
+func (d *DomainService) UpdateContext(ctx context.Context, Id string, config DomainUpdateConfig) (*DomainObject, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.Update")
 	entity := new(DomainObject)
 	b, _ := json.Marshal(config)
-	res, err := d.client.ExecuteRequest("PUT", fmt.Sprint(baseDomainUrl, Id, "/"), b, entity)
+	res, err := d.client.ExecuteRequestContext(ctx, "PUT", fmt.Sprint(baseDomainUrl, Id, "/"), b, entity)
 	return entity, res, err
 }
 
 func (d *DomainService) Start(domain *DomainObject) (*DomainObject, *http.Response, error) {
-	res, err := d.client.ExecuteRequest("POST", fmt.Sprint(baseDomainUrl, domain.Id, "/start/"), []byte{}, domain)
+	return d.StartContext(context.Background(), domain)
+}
+
+func (d *DomainService) StartContext(ctx context.Context, domain *DomainObject) (*DomainObject, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.Start")
+	res, err := d.client.ExecuteRequestContext(ctx, "POST", fmt.Sprint(baseDomainUrl, domain.Id, "/start/"), []byte{}, domain)
 	return domain, res, err
 }
 
 func (d *DomainService) Suspend(domain *DomainObject) (*DomainObject, *http.Response, error) {
-	res, err := d.client.ExecuteRequest("POST", fmt.Sprint(baseDomainUrl, domain.Id, "/suspend/"), []byte{}, domain)
+	return d.SuspendContext(context.Background(), domain)
+}
+
+func (d *DomainService) SuspendContext(ctx context.Context, domain *DomainObject) (*DomainObject, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.Suspend")
+	res, err := d.client.ExecuteRequestContext(ctx, "POST", fmt.Sprint(baseDomainUrl, domain.Id, "/suspend/"), []byte{}, domain)
 	return domain, res, err
 }
 
 func (d *DomainService) Resume(domain *DomainObject) (*DomainObject, *http.Response, error) {
-	res, err := d.client.ExecuteRequest("POST", fmt.Sprint(baseDomainUrl, domain.Id, "/resume/"), []byte{}, domain)
+	return d.ResumeContext(context.Background(), domain)
+}
+
+func (d *DomainService) ResumeContext(ctx context.Context, domain *DomainObject) (*DomainObject, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.Resume")
+	res, err := d.client.ExecuteRequestContext(ctx, "POST", fmt.Sprint(baseDomainUrl, domain.Id, "/resume/"), []byte{}, domain)
 	return domain, res, err
 }
 
 func (d *DomainService) Shutdown(domain *DomainObject, force bool) (*DomainObject, *http.Response, error) {
+	return d.ShutdownContext(context.Background(), domain, force)
+}
+
+func (d *DomainService) ShutdownContext(ctx context.Context, domain *DomainObject, force bool) (*DomainObject, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.Shutdown")
 	body := struct {
 		Force bool `json:"force,omitempty"`
 	}{force}
 	b, _ := json.Marshal(body)
-	res, err := d.client.ExecuteRequest("POST", fmt.Sprint(baseDomainUrl, domain.Id, "/shutdown/"), b, domain)
+	res, err := d.client.ExecuteRequestContext(ctx, "POST", fmt.Sprint(baseDomainUrl, domain.Id, "/shutdown/"), b, domain)
 	return domain, res, err
 }
 
 func (d *DomainService) Reboot(domain *DomainObject, force bool) (*DomainObject, *http.Response, error) {
+	return d.RebootContext(context.Background(), domain, force)
+}
+
+func (d *DomainService) RebootContext(ctx context.Context, domain *DomainObject, force bool) (*DomainObject, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.Reboot")
 	body := struct {
 		Force bool `json:"force,omitempty"`
 	}{force}
 	b, _ := json.Marshal(body)
-	res, err := d.client.ExecuteRequest("POST", fmt.Sprint(baseDomainUrl, domain.Id, "/reboot/"), b, domain)
+	res, err := d.client.ExecuteRequestContext(ctx, "POST", fmt.Sprint(baseDomainUrl, domain.Id, "/reboot/"), b, domain)
 	return domain, res, err
 }
 
 func (d *DomainService) Template(domain *DomainObject, template bool) (*DomainObject, *http.Response, error) {
+	return d.TemplateContext(context.Background(), domain, template)
+}
+
+func (d *DomainService) TemplateContext(ctx context.Context, domain *DomainObject, template bool) (*DomainObject, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.Template")
 	body := struct {
 		Template bool `json:"template"`
 	}{template}
 	b, _ := json.Marshal(body)
-	res, err := d.client.ExecuteRequest("PUT", fmt.Sprint(baseDomainUrl, domain.Id, "/template/"), b, domain)
+	res, err := d.client.ExecuteRequestContext(ctx, "PUT", fmt.Sprint(baseDomainUrl, domain.Id, "/template/"), b, domain)
 	return domain, res, err
 }
 
 func (d *DomainService) Clone(Id string, config DomainCloneConfig) (*DomainObject, *http.Response, error) {
-	domain := new(DomainObject)
+	return d.CloneContext(context.Background(), Id, config)
+}
+
+func (d *DomainService) CloneContext(ctx context.Context, Id string, config DomainCloneConfig) (*DomainObject, *http.Response, error) {
+	ctx, finish := startOperation(ctx, d.client, "DomainService.Clone", Id)
+	op, res, err := d.StartClone(ctx, Id, config)
+	if err != nil {
+		finish(err)
+		return new(DomainObject), res, err
+	}
+	domain, err := op.Wait(ctx)
+	finish(err)
+	return domain, op.response(res), err
+}
+
+// StartClone starts domain cloning and returns without waiting for the task
+func (d *DomainService) StartClone(ctx context.Context, Id string, config DomainCloneConfig) (*DomainOperation, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.StartClone")
+	ctx = config.IdempotencyKeyBase.apply(ctx)
 	b, _ := json.Marshal(config)
 	asyncResp := new(AsyncEntityResponse)
-	res, err := d.client.ExecuteRequest("POST", fmt.Sprint(baseDomainUrl, Id, "/clone/?async=1"), b, asyncResp)
+	res, err := d.client.ExecuteRequestContext(ctx, "POST", fmt.Sprint(baseDomainUrl, Id, "/clone/?async=1"), b, asyncResp)
 	if err != nil {
-		return domain, res, err
+		return nil, res, err
 	}
-	client := d.client.RetClient()
-	taskObj := WaitTaskReady(client, asyncResp.Task.Id, true, 0, true)
-	res, err = client.Task.Response(taskObj.Id, domain)
-	return domain, res, err
+	op, err := newDomainOperation(d.client, OperationState{OperationKind.DomainClone, asyncResp.Task.Id, asyncResp.Entity})
+	return op, res, err
+}
+
+// ResumeOperation restores the handle of an operation which result is a domain, e.g. started by StartClone
+func (d *DomainService) ResumeOperation(state OperationState) (*DomainOperation, error) {
+	return newDomainOperation(d.client, state)
 }
 
 func (d *DomainService) CloudInit(domain *DomainObject, config CloudInitConf) (*DomainObject, *http.Response, error) {
+	return d.CloudInitContext(context.Background(), domain, config)
+}
+
+func (d *DomainService) CloudInitContext(ctx context.Context, domain *DomainObject, config CloudInitConf) (*DomainObject, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.CloudInit")
 	b, _ := json.Marshal(config)
-	res, err := d.client.ExecuteRequest("PUT", fmt.Sprint(baseDomainUrl, domain.Id, "/cloud-init/"), b, domain)
+	res, err := d.client.ExecuteRequestContext(ctx, "PUT", fmt.Sprint(baseDomainUrl, domain.Id, "/cloud-init/"), b, domain)
 	return domain, res, err
 }
 
 func (d *DomainService) Remove(domainID string, full bool, force bool) (bool, *http.Response, error) {
+	return d.RemoveContext(context.Background(), domainID, full, force)
+}
+
+func (d *DomainService) RemoveContext(ctx context.Context, domainID string, full bool, force bool) (bool, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.Remove")
 	body := struct {
 		Force bool `json:"force"`
 		Full  bool `json:"full"`
 	}{force, full}
 	b, _ := json.Marshal(body)
-	res, err := d.client.ExecuteRequest("POST", fmt.Sprint(baseDomainUrl, domainID, "/remove/"), b, nil)
+	res, err := d.client.ExecuteRequestContext(ctx, "POST", fmt.Sprint(baseDomainUrl, domainID, "/remove/"), b, nil)
 	if err != nil {
 		return false, res, err
 	}
@@ -59,8 +59,15 @@ final_message: "The system is finally up, after $UPTIME seconds"`
 var baseUser = "user"
 var userData = fmt.Sprintf(UserDataTemplate, baseUser, baseUser)
 
+// createTestDomain creates domain TestDomainID on the fake server of client
+func createTestDomain(t *testing.T, client *WebClient) *DomainObject {
+	domain, _, err := client.Domain.Create(DomainCreateConfig{DomainId: TestDomainID, VerboseName: TestDomainName, MemoryCount: 50})
+	require.Nil(t, err)
+	return domain
+}
+
 func Test_DomainList(t *testing.T) {
-	client := NewClient("", "", false)
+	_, client := newTestServerClient(t)
 
 	_, _, err := client.Domain.List()
 	assert.Nil(t, err)
@@ -69,20 +76,21 @@ func Test_DomainList(t *testing.T) {
 }
 
 func Test_DomainCreate(t *testing.T) {
-	client := NewClient("", "", false)
+	_, client := newTestServerClient(t)
 	config := new(DomainCreateConfig)
 	config.DomainId = TestDomainID
 	config.VerboseName = TestDomainName
 	config.MemoryCount = 50
 	domain, _, err := client.Domain.Create(*config)
 	require.Nil(t, err)
-	assert.NotEqual(t, domain.Id, "", "Domain Id can not be empty")
+	assert.Equal(t, TestDomainID, domain.Id)
 
 	return
 }
 
 func Test_DomainGet(t *testing.T) {
-	client := NewClient("", "", false)
+	_, client := newTestServerClient(t)
+	createTestDomain(t, client)
 
 	domain, _, err := client.Domain.Get(TestDomainID)
 	assert.Nil(t, err)
@@ -92,7 +100,8 @@ func Test_DomainGet(t *testing.T) {
 }
 
 func Test_DomainPower(t *testing.T) {
-	client := NewClient("", "", false)
+	_, client := newTestServerClient(t)
+	createTestDomain(t, client)
 
 	domain, _, err := client.Domain.Get(TestDomainID)
 	assert.Nil(t, err)
@@ -118,20 +127,24 @@ func Test_DomainPower(t *testing.T) {
 }
 
 func Test_DomainUpdate(t *testing.T) {
-	client := NewClient("", "", false)
+	_, client := newTestServerClient(t)
+	createTestDomain(t, client)
 	config := new(DomainUpdateConfig)
 	newName := "test"
 	config.VerboseName = newName
 	domain, _, err := client.Domain.Update(TestDomainID, *config)
-	domain.Refresh(client)
 	assert.Nil(t, err)
-	assert.NotEqual(t, domain.VerboseName, newName, "Domain VerboseName should be test")
+	domain, err = domain.Refresh(client)
+	assert.Nil(t, err)
+	// The refreshed domain carries the applied update, NotEqual passed only while nothing applied it
+	assert.Equal(t, domain.VerboseName, newName, "Domain VerboseName should be test")
 
 	return
 }
 
 func Test_DomainRemove(t *testing.T) {
-	client := NewClient("", "", false)
+	_, client := newTestServerClient(t)
+	createTestDomain(t, client)
 
 	status, _, err := client.Domain.Remove(TestDomainID, true, false)
 	assert.Nil(t, err)
@@ -141,7 +154,7 @@ func Test_DomainRemove(t *testing.T) {
 }
 
 func Test_DomainMultiCreate(t *testing.T) {
-	client := NewClient("", "", false)
+	_, client := newTestServerClient(t)
 
 	nodesResponse, _, err := client.Node.List()
 	require.Nil(t, err, err)
@@ -165,7 +178,11 @@ func Test_DomainMultiCreate(t *testing.T) {
 }
 
 func Test_DomainMultiCreateThin(t *testing.T) {
-	client := NewClient("", "", false)
+	_, client := newTestServerClient(t)
+	template, _, err := client.Domain.Create(DomainCreateConfig{VerboseName: "template"})
+	require.Nil(t, err)
+	_, _, err = client.Domain.Template(template, true)
+	require.Nil(t, err)
 
 	templatesResponse, _, err := client.Domain.ListParams(map[string]string{
 		"template": "true",
@@ -206,7 +223,7 @@ func Test_DomainPackerClone(t *testing.T) {
 		5. Выключаем ВМ
 		6. Переводим её в шаблон
 	*/
-	client := NewClient("", "", false)
+	client := liveClient(t)
 	// check templates
 	templatesResponse, _, err := client.Domain.ListParams(map[string]string{
 		"template": "true",
@@ -305,7 +322,7 @@ func Test_DomainPackerQcow2(t *testing.T) {
 		6. Выключаем ВМ
 		7. Переводим её в шаблон
 	*/
-	client := NewClient("", "", false)
+	client := liveClient(t)
 	// 1. Загружаем qcow2 или выбираем из имеющихся и импортируем его в диск, а также увеличиваем размер
 	filesResponse, _, err := client.Library.ListParams(map[string]string{
 		"status":   "ACTIVE",
@@ -0,0 +1,125 @@
+package veil
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBodyLen limits how much of a non JSON error body gets into APIError.Error()
+const maxErrorBodyLen = 256
+
+// ErrInvalidConfig is wrapped by errors of configs rejected by the client without sending a request
+var ErrInvalidConfig = errors.New("invalid config")
+
+// APIError is returned when VeiL answers with a non-success status code
+type APIError struct {
+	StatusCode int
+	Method     string
+	URL        string
+	// Errors decoded from the ErrorResponse body, empty if the body was not JSON
+	Errors []ErrorDict
+	// Body raw response body
+	Body []byte
+}
+
+func (e *APIError) Error() string {
+	detail := e.Detail()
+	if detail == "" {
+		detail = strings.TrimSpace(string(e.Body))
+		if len(detail) > maxErrorBodyLen {
+			detail = detail[:maxErrorBodyLen] + "..."
+		}
+	}
+	return fmt.Sprintf("status code: %d, detail: %s on url %s %s", e.StatusCode, detail, e.Method, e.URL)
+}
+
+// Detail returns all error details joined by "; "
+func (e *APIError) Detail() string {
+	details := make([]string, 0, len(e.Errors))
+	for _, v := range e.Errors {
+		if v.Detail != "" {
+			details = append(details, v.Detail)
+		}
+	}
+	return strings.Join(details, "; ")
+}
+
+// HasCode reports whether any of the returned errors has the given code
+func (e *APIError) HasCode(code string) bool {
+	for _, v := range e.Errors {
+		if v.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// HasMsgKey reports whether any of the returned errors has the given msg_key
+func (e *APIError) HasMsgKey(msgKey string) bool {
+	for _, v := range e.Errors {
+		if v.MsgKey == msgKey {
+			return true
+		}
+	}
+	return false
+}
+
+// AsAPIError unwraps err to *APIError
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
+}
+
+func hasStatus(err error, codes ...int) bool {
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		return false
+	}
+	for _, code := range codes {
+		if apiErr.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBadRequest reports whether err is an APIError with 400 status code
+func IsBadRequest(err error) bool {
+	return hasStatus(err, http.StatusBadRequest)
+}
+
+// IsUnauthorized reports whether err is an APIError with 401 status code
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+// IsPermissionDenied reports whether err is an APIError with 403 status code
+func IsPermissionDenied(err error) bool {
+	return hasStatus(err, http.StatusForbidden)
+}
+
+// IsNotFound reports whether err is an APIError with 404 status code
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsConflict reports whether err is an APIError with 409 status code
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+// IsLocked reports whether err is an APIError with 423 status code, the entity is locked by another task
+func IsLocked(err error) bool {
+	return hasStatus(err, http.StatusLocked)
+}
+
+// IsServerError reports whether err is an APIError with 5xx status code
+func IsServerError(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && apiErr.StatusCode >= 500
+}
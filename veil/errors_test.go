@@ -0,0 +1,55 @@
+package veil
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/domains/locked/":
+			w.WriteHeader(http.StatusLocked)
+			fmt.Fprint(w, `{"errors": [{"detail": "Domain is locked by task.", "code": "423", "msg_key": "entity_locked"}]}`)
+		case "/api/domains/gateway/":
+			w.WriteHeader(http.StatusBadGateway)
+			fmt.Fprint(w, `<html>502 Bad Gateway</html>`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errors": [{"detail": "Not found.", "code": "404"}]}`)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "token", false)
+
+	_, _, err := client.Domain.Get("missing")
+	require.Error(t, err)
+	var apiErr *APIError
+	require.True(t, errors.As(fmt.Errorf("wrapped: %w", err), &apiErr))
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "GET", apiErr.Method)
+	assert.Equal(t, "/api/domains/missing/", apiErr.URL)
+	assert.Equal(t, "Not found.", apiErr.Detail())
+	assert.True(t, IsNotFound(err))
+	assert.False(t, IsConflict(err))
+
+	_, _, err = client.Domain.Get("locked")
+	assert.True(t, IsLocked(err))
+	apiErr, ok := AsAPIError(err)
+	require.True(t, ok)
+	assert.True(t, apiErr.HasMsgKey("entity_locked"))
+	assert.Contains(t, err.Error(), "Domain is locked by task.")
+
+	_, _, err = client.Domain.Get("gateway")
+	assert.True(t, IsServerError(err))
+	assert.Contains(t, err.Error(), "502 Bad Gateway")
+
+	assert.False(t, IsNotFound(errors.New("status code: 404")))
+
+	return
+}
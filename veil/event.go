@@ -1,8 +1,11 @@
 package veil
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"net/url"
+	"time"
 )
 
 const baseEventUrl = baseApiUrl + "events/"
@@ -47,19 +50,100 @@ type EventsResponse struct {
 }
 
 func (d *EventService) List() (*EventsResponse, *http.Response, error) {
+	return d.ListContext(context.Background())
+}
+
+func (d *EventService) ListContext(ctx context.Context) (*EventsResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "EventService.List")
 
 	response := new(EventsResponse)
 
-	res, err := d.client.ExecuteRequest("GET", baseEventUrl, []byte{}, response)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", baseEventUrl, []byte{}, response)
+
+	return response, res, err
+}
+
+// EventFilter typed filter of events, zero fields are not sent
+type EventFilter struct {
+	ListOptions
+	Type          []string
+	User          string
+	Task          string
+	Entity        string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+}
+
+// Values encodes the filter to query parameters
+func (f EventFilter) Values() url.Values {
+	params := f.ListOptions.Values()
+	addStrings(params, "type", f.Type)
+	setString(params, "user", f.User)
+	setString(params, "task", f.Task)
+	setString(params, "entity", f.Entity)
+	setTime(params, "created__gte", f.CreatedAfter)
+	setTime(params, "created__lte", f.CreatedBefore)
+	return params
+}
 
+// ListFiltered returns the first page of events matching filter
+func (d *EventService) ListFiltered(filter EventFilter) (*EventsResponse, *http.Response, error) {
+	return d.ListFilteredContext(context.Background(), filter)
+}
+
+// ListFilteredContext returns the first page of events matching filter
+func (d *EventService) ListFilteredContext(ctx context.Context, filter EventFilter) (*EventsResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "EventService.ListFiltered")
+	response := new(EventsResponse)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", buildListUrl(baseEventUrl, filter), []byte{}, response)
 	return response, res, err
 }
 
+// EventIterator streams events page by page
+type EventIterator struct {
+	*Iterator
+	item EventObjectsList
+}
+
+// Next advances to the next event
+func (it *EventIterator) Next() bool {
+	it.item = EventObjectsList{}
+	return it.Iterator.Next() && it.decode(&it.item)
+}
+
+// Item returns the current event
+func (it *EventIterator) Item() EventObjectsList {
+	return it.item
+}
+
+// Iter returns an iterator over all events matching filter, filter and opts may be nil
+func (d *EventService) Iter(ctx context.Context, filter ListFilter, opts *PageOptions) *EventIterator {
+	ctx = withOperation(ctx, "EventService.Iter")
+	return &EventIterator{Iterator: newIterator(ctx, d.client, baseEventUrl, filter, opts)}
+}
+
+// ListAll returns all events matching filter following the pages until exhausted
+func (d *EventService) ListAll(ctx context.Context, filter ListFilter) ([]EventObjectsList, error) {
+	ctx = withOperation(ctx, "EventService.ListAll")
+	it := d.Iter(ctx, filter, nil)
+	defer it.Close()
+	var items []EventObjectsList
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
+
 func (d *EventService) Get(Id string) (*EventObject, *http.Response, error) {
+	return d.GetContext(context.Background(), Id)
+}
+
+func (d *EventService) GetContext(ctx context.Context, Id string) (*EventObject, *http.Response, error) {
+	ctx = withOperation(ctx, "EventService.Get")
 
 	Event := new(EventObject)
 
-	res, err := d.client.ExecuteRequest("GET", fmt.Sprint(baseEventUrl, Id, "/"), []byte{}, Event)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", fmt.Sprint(baseEventUrl, Id, "/"), []byte{}, Event)
 
 	return Event, res, err
 }
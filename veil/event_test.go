@@ -1,12 +1,14 @@
 package veil
 
 import (
+	"github.com/jsc-masshtab/veil-api-client-go/veil/veiltest"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
 
 func Test_Event(t *testing.T) {
-	client := NewClient("", "", false)
+	server, client := newTestServerClient(t)
+	server.Add(veiltest.Events, veiltest.Object{"message": "domain started", "type": "info"})
 	response, _, err := client.Event.List()
 	assert.Nil(t, err)
 	for _, v := range response.Results {
@@ -0,0 +1,250 @@
+package veil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultHealthPath endpoint requested by health checks, any response below 500 means the controller is alive
+const DefaultHealthPath = baseNodeUrl + "?limit=1"
+
+// FailoverOptions settings of multi-controller failover
+type FailoverOptions struct {
+	// HealthPath path requested by health checks, DefaultHealthPath if not set
+	HealthPath string
+	// HealthTimeout limits a single health check, 5 seconds if not set
+	HealthTimeout time.Duration
+}
+
+// EndpointStatus state of controller endpoint for diagnostics
+type EndpointStatus struct {
+	URL       string
+	Active    bool
+	Healthy   bool
+	Failures  int
+	LastError string
+	LastCheck time.Time
+}
+
+// EndpointPool list of controllers of HA installation. The active controller is sticky: it is changed only
+// when a request to it fails with connection error or 5xx response, or when a health check finds it down
+type EndpointPool struct {
+	opts FailoverOptions
+
+	mu        sync.RWMutex
+	endpoints []*EndpointStatus
+	active    int
+}
+
+// NewEndpointPool creates pool of controller urls, the first one is active. opts may be nil
+func NewEndpointPool(urls []string, opts *FailoverOptions) (*EndpointPool, error) {
+	if len(urls) == 0 {
+		return nil, ErrNotConfigured
+	}
+	pool := &EndpointPool{}
+	if opts != nil {
+		pool.opts = *opts
+	}
+	if pool.opts.HealthPath == "" {
+		pool.opts.HealthPath = DefaultHealthPath
+	}
+	if pool.opts.HealthTimeout <= 0 {
+		pool.opts.HealthTimeout = 5 * time.Second
+	}
+	for _, u := range urls {
+		if !isValidUrl(u) {
+			return nil, fmt.Errorf("invalid controller url %q", u)
+		}
+		pool.endpoints = append(pool.endpoints, &EndpointStatus{URL: u, Healthy: true})
+	}
+	return pool, nil
+}
+
+// Active returns url of the active controller
+func (p *EndpointPool) Active() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.endpoints[p.active].URL
+}
+
+// Status returns state of all endpoints
+func (p *EndpointPool) Status() []EndpointStatus {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	status := make([]EndpointStatus, len(p.endpoints))
+	for i, e := range p.endpoints {
+		status[i] = *e
+		status[i].Active = i == p.active
+	}
+	return status
+}
+
+// Len number of endpoints
+func (p *EndpointPool) Len() int {
+	if p == nil {
+		return 0
+	}
+	return len(p.endpoints)
+}
+
+// failed marks endpoint as down and switches the active one if it failed.
+// It reports whether another endpoint became active
+func (p *EndpointPool) failed(url string, err error) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	index := p.indexOf(url)
+	if index < 0 {
+		return false
+	}
+	e := p.endpoints[index]
+	e.Healthy = false
+	e.Failures++
+	e.LastError = err.Error()
+	if index != p.active {
+		// Another request has already switched the controller
+		return true
+	}
+	return p.switchFrom(index)
+}
+
+// switchFrom activates the next healthy endpoint after index, or just the next one if all are down
+func (p *EndpointPool) switchFrom(index int) bool {
+	count := len(p.endpoints)
+	if count == 1 {
+		return false
+	}
+	next := (index + 1) % count
+	for i := 1; i < count; i++ {
+		candidate := (index + i) % count
+		if p.endpoints[candidate].Healthy {
+			next = candidate
+			break
+		}
+	}
+	p.active = next
+	return true
+}
+
+func (p *EndpointPool) indexOf(url string) int {
+	for i, e := range p.endpoints {
+		if e.URL == url {
+			return i
+		}
+	}
+	return -1
+}
+
+// setHealth records result of health check, unhealthy active endpoint is switched to a healthy one
+func (p *EndpointPool) setHealth(url string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	index := p.indexOf(url)
+	if index < 0 {
+		return
+	}
+	e := p.endpoints[index]
+	e.LastCheck = time.Now()
+	e.Healthy = err == nil
+	if err != nil {
+		e.Failures++
+		e.LastError = err.Error()
+		if index == p.active {
+			p.switchFrom(index)
+		}
+	}
+}
+
+// ActiveEndpoint returns url of the controller requests are sent to
+func (client *WebClient) ActiveEndpoint() string {
+	if client.Endpoints != nil {
+		return client.Endpoints.Active()
+	}
+	return client.BaseURL
+}
+
+// CheckEndpoints checks health of all controllers and returns their state, the active controller
+// is switched only if it is down
+func (client *WebClient) CheckEndpoints(ctx context.Context) []EndpointStatus {
+	if client.Endpoints == nil {
+		return nil
+	}
+	for _, e := range client.Endpoints.Status() {
+		client.Endpoints.setHealth(e.URL, client.checkEndpoint(ctx, e.URL))
+	}
+	return client.Endpoints.Status()
+}
+
+func (client *WebClient) checkEndpoint(ctx context.Context, base string) error {
+	ctx, cancel := context.WithTimeout(ctx, client.Endpoints.opts.HealthTimeout)
+	defer cancel()
+	req, err := client.newRequestTo(ctx, base, "GET", client.Endpoints.opts.HealthPath, nil)
+	if err != nil {
+		return err
+	}
+	token, err := client.token(ctx)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "jwt "+token)
+	res, _, err := client.send(req)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("health check status %s", res.Status)
+	}
+	return nil
+}
+
+// StartHealthChecks checks controllers every interval until ctx is done
+func (client *WebClient) StartHealthChecks(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				client.CheckEndpoints(ctx)
+			}
+		}
+	}()
+}
+
+// failover reports whether the failed attempt sent to base should be repeated on another controller.
+// Connection errors are failed over for all requests, 5xx responses and other network errors only for idempotent ones
+func (client *WebClient) failover(ctx context.Context, base string, method string, res *http.Response, err error) bool {
+	if client.Endpoints == nil || ctx.Err() != nil {
+		return false
+	}
+	switch {
+	case err != nil && isConnectError(err):
+	case err != nil && isTransientNetworkError(err) && isIdempotent(ctx, method):
+	case err == nil && res.StatusCode >= http.StatusInternalServerError && isIdempotent(ctx, method):
+		err = fmt.Errorf("status %s", res.Status)
+	default:
+		return false
+	}
+	switched := client.Endpoints.failed(base, err)
+	if switched {
+		client.logger().Log(ctx, LevelWarn, "veil controller failover", "from", base,
+			"to", client.Endpoints.Active(), "error", err)
+	}
+	return switched
+}
+
+// isConnectError reports whether the request was not delivered because the connection was not established
+func isConnectError(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
@@ -0,0 +1,92 @@
+package veil
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// newControllerServer serves nodes, it responds 503 while down is set
+func newControllerServer(name string, down *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(down) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"id": "node-1", "verbose_name": "` + name + `"}`))
+	}))
+}
+
+func Test_FailoverStatus5xx(t *testing.T) {
+	var primaryDown, secondaryDown int32
+	primary := newControllerServer("primary", &primaryDown)
+	defer primary.Close()
+	secondary := newControllerServer("secondary", &secondaryDown)
+	defer secondary.Close()
+	client, err := NewClientWithOptions(WithEndpoints(primary.URL, secondary.URL))
+	require.NoError(t, err)
+	assert.Equal(t, primary.URL, client.ActiveEndpoint())
+
+	atomic.StoreInt32(&primaryDown, 1)
+	// Not idempotent request is not repeated on another controller
+	_, err = client.ExecuteRequest("POST", baseNodeUrl, []byte{}, nil)
+	assert.True(t, IsServerError(err))
+	assert.Equal(t, primary.URL, client.ActiveEndpoint())
+
+	node, _, err := client.Node.Get("node-1")
+	require.NoError(t, err)
+	assert.Equal(t, "secondary", node.VerboseName)
+	assert.Equal(t, secondary.URL, client.ActiveEndpoint())
+
+	// The active controller is sticky after the primary one is back
+	atomic.StoreInt32(&primaryDown, 0)
+	node, _, err = client.Node.Get("node-1")
+	require.NoError(t, err)
+	assert.Equal(t, "secondary", node.VerboseName)
+
+	status := client.Endpoints.Status()
+	require.Len(t, status, 2)
+	assert.False(t, status[0].Healthy)
+	assert.Equal(t, 1, status[0].Failures)
+	assert.True(t, status[1].Active)
+
+	// Health check switches from the controller which is down
+	atomic.StoreInt32(&secondaryDown, 1)
+	status = client.CheckEndpoints(context.Background())
+	assert.True(t, status[0].Healthy)
+	assert.True(t, status[0].Active)
+	assert.False(t, status[1].Healthy)
+	assert.Equal(t, primary.URL, client.ActiveEndpoint())
+	return
+}
+
+func Test_FailoverConnectionRefused(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	deadUrl := "http://" + listener.Addr().String()
+	listener.Close()
+	var down int32
+	alive := newControllerServer("alive", &down)
+	defer alive.Close()
+	client, err := NewClientWithOptions(WithEndpoints(deadUrl, alive.URL))
+	require.NoError(t, err)
+
+	// The request was not delivered, so even POST is sent to another controller
+	_, err = client.ExecuteRequest("POST", baseNodeUrl, []byte{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, alive.URL, client.ActiveEndpoint())
+
+	// All controllers are down, every one is tried once
+	atomic.StoreInt32(&down, 1)
+	_, _, err = client.Node.Get("node-1")
+	assert.Error(t, err)
+
+	_, err = NewClientWithOptions(WithEndpoints("not a url"))
+	assert.Error(t, err)
+	return
+}
@@ -0,0 +1,109 @@
+package veil
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ListOptions query options common for all list endpoints, it is embedded into typed filters.
+// Setters can be chained: filter.OrderBy("-created").Fields("id", "verbose_name")
+type ListOptions struct {
+	ordering []string
+	fields   []string
+	search   string
+	limit    int
+	offset   int
+}
+
+// OrderBy sorts the list by fields, "-" prefix means descending order
+func (o *ListOptions) OrderBy(fields ...string) *ListOptions {
+	o.ordering = append(o.ordering, fields...)
+	return o
+}
+
+// Fields limits fields of returned items
+func (o *ListOptions) Fields(fields ...string) *ListOptions {
+	o.fields = append(o.fields, fields...)
+	return o
+}
+
+// Search full text search over the list
+func (o *ListOptions) Search(text string) *ListOptions {
+	o.search = text
+	return o
+}
+
+// Limit max number of items in the page, list iterators set it themselves
+func (o *ListOptions) Limit(limit int) *ListOptions {
+	o.limit = limit
+	return o
+}
+
+// Offset number of items to skip
+func (o *ListOptions) Offset(offset int) *ListOptions {
+	o.offset = offset
+	return o
+}
+
+// Values encodes the options to query parameters
+func (o ListOptions) Values() url.Values {
+	params := url.Values{}
+	if len(o.ordering) != 0 {
+		params.Set("ordering", strings.Join(o.ordering, ","))
+	}
+	if len(o.fields) != 0 {
+		params.Set("fields", strings.Join(o.fields, ","))
+	}
+	setString(params, "search", o.search)
+	if o.limit > 0 {
+		params.Set("limit", strconv.Itoa(o.limit))
+	}
+	if o.offset > 0 {
+		params.Set("offset", strconv.Itoa(o.offset))
+	}
+	return params
+}
+
+// Bool returns pointer to v for optional boolean filters
+func Bool(v bool) *bool {
+	return &v
+}
+
+// buildListUrl appends encoded filter to the list endpoint url
+func buildListUrl(baseUrl string, filter ListFilter) string {
+	if filter == nil {
+		return baseUrl
+	}
+	params := filter.Values()
+	if len(params) == 0 {
+		return baseUrl
+	}
+	return baseUrl + "?" + params.Encode()
+}
+
+func setString(params url.Values, key string, value string) {
+	if value != "" {
+		params.Set(key, value)
+	}
+}
+
+// addStrings adds every value as a repeated key
+func addStrings(params url.Values, key string, values []string) {
+	for _, v := range values {
+		params.Add(key, v)
+	}
+}
+
+func setBool(params url.Values, key string, value *bool) {
+	if value != nil {
+		params.Set(key, strconv.FormatBool(*value))
+	}
+}
+
+func setTime(params url.Values, key string, value time.Time) {
+	if !value.IsZero() {
+		params.Set(key, value.Format(time.RFC3339))
+	}
+}
@@ -0,0 +1,40 @@
+package veil
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_DomainFilterValues(t *testing.T) {
+	filter := DomainFilter{Status: []string{"ACTIVE", "FAILED"}, Template: Bool(true), Tags: []string{"web"}}
+	filter.OrderBy("-created").Fields("id", "verbose_name").Search("srv")
+	assert.Equal(t, "fields=id%2Cverbose_name&ordering=-created&search=srv&status=ACTIVE&status=FAILED&tags=web&template=true",
+		filter.Values().Encode())
+	assert.Equal(t, baseDomainUrl, buildListUrl(baseDomainUrl, DomainFilter{}))
+	assert.Equal(t, baseDomainUrl, buildListUrl(baseDomainUrl, Params(nil)))
+	return
+}
+
+func Test_ListFiltered(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, baseDomainUrl, r.URL.Path)
+		queries = append(queries, r.URL.RawQuery)
+		w.Write([]byte(`{"count": 1, "results": [{"id": "domain-1"}]}`))
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "token", false)
+
+	filter := DomainFilter{Node: "node-1"}
+	filter.Limit(10)
+	response, _, err := client.Domain.ListFiltered(filter)
+	require.NoError(t, err)
+	require.Len(t, response.Results, 1)
+	_, _, err = client.Domain.ListParams(map[string]string{"node": "node-1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"limit=10&node=node-1", "node=node-1"}, queries)
+	return
+}
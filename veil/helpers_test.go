@@ -0,0 +1,66 @@
+package veil
+
+import (
+	"context"
+	"github.com/jsc-masshtab/veil-api-client-go/veil/veiltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// newTestServerClient starts fake server which is closed with the test and returns client of it
+func newTestServerClient(t *testing.T) (*veiltest.Server, *WebClient) {
+	server := veiltest.NewServer(nil)
+	t.Cleanup(server.Close)
+	client, err := NewClientWithOptions(WithBaseURL(server.URL), WithToken(veiltest.Token))
+	require.Nil(t, err)
+	return server, client
+}
+
+// liveClient returns client of the controller configured by VEIL_API_URL and VEIL_API_TOKEN, the test is skipped
+// if the url is not set. It is used by tests which need a real controller, e.g. guest agent or files of ../file_data
+func liveClient(t *testing.T) *WebClient {
+	t.Helper()
+	if os.Getenv(EnvApiUrl) == "" {
+		t.Skipf("%s is not set", EnvApiUrl)
+	}
+	return NewClient("", "", false)
+}
+
+var pathParam = regexp.MustCompile(`{[^}]*}`)
+
+// liveEndpoints checks that the controller documents endpoints in its OpenAPI schema, e.g. /api/domains/{id}/cpu/.
+// Names of path parameters are ignored. The API version of the schema is logged to tell which API the paths match
+func liveEndpoints(t *testing.T, client *WebClient, endpoints ...string) {
+	t.Helper()
+	schema := struct {
+		BasePath string                 `json:"basePath"`
+		Info     Info                   `json:"info"`
+		Paths    map[string]interface{} `json:"paths"`
+	}{}
+	_, err := client.ExecuteRequestContext(context.Background(), "GET", SwaggerUrl, []byte{}, &schema)
+	require.Nil(t, err)
+	t.Logf("%s %s", schema.Info.Title, schema.Info.Version)
+	documented := map[string]bool{}
+	for path := range schema.Paths {
+		documented[pathParam.ReplaceAllString(path, "{}")] = true
+		documented[pathParam.ReplaceAllString(strings.TrimSuffix(schema.BasePath, "/")+path, "{}")] = true
+	}
+	for _, endpoint := range endpoints {
+		assert.True(t, documented[pathParam.ReplaceAllString(endpoint, "{}")], "%s is not documented by the controller", endpoint)
+	}
+}
+
+// liveDomain creates powered off domain on the controller of client, the domain is removed with the test
+func liveDomain(t *testing.T, client *WebClient) *DomainObject {
+	t.Helper()
+	domain, _, err := client.Domain.Create(DomainCreateConfig{VerboseName: NameGenerator("live"), MemoryCount: 256, CpuCount: 1})
+	require.Nil(t, err)
+	t.Cleanup(func() {
+		client.Domain.Remove(domain.Id, true, true)
+	})
+	return domain
+}
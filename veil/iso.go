@@ -2,11 +2,11 @@ package veil
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"log"
 	"mime/multipart"
 	"net/http"
 	"net/url"
@@ -66,36 +66,120 @@ type IsoAttach struct {
 }
 
 func (d *IsoService) List() (*IsosResponse, *http.Response, error) {
+	return d.ListContext(context.Background())
+}
+
+func (d *IsoService) ListContext(ctx context.Context) (*IsosResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "IsoService.List")
 
 	response := new(IsosResponse)
 
-	res, err := d.client.ExecuteRequest("GET", baseIsoUrl, []byte{}, response)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", baseIsoUrl, []byte{}, response)
 
 	return response, res, err
 }
 
 func (d *IsoService) ListParams(queryParams map[string]string) (*IsosResponse, *http.Response, error) {
-	listUrl := baseIsoUrl
-	if len(queryParams) != 0 {
-		params := url.Values{}
-		for k, v := range queryParams {
-			params.Add(k, v)
-		}
-		listUrl += "?"
-		listUrl += params.Encode()
-	}
+	return d.ListParamsContext(context.Background(), queryParams)
+}
+
+func (d *IsoService) ListParamsContext(ctx context.Context, queryParams map[string]string) (*IsosResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "IsoService.ListParams")
+	response := new(IsosResponse)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", buildListUrl(baseIsoUrl, Params(queryParams)), []byte{}, response)
+	return response, res, err
+}
+
+// IsoFilter typed filter of ISO images, zero fields are not sent
+type IsoFilter struct {
+	ListOptions
+	Status   []string
+	FileName string
+	Datapool string
+	Domain   string
+}
+
+// Values encodes the filter to query parameters
+func (f IsoFilter) Values() url.Values {
+	params := f.ListOptions.Values()
+	addStrings(params, "status", f.Status)
+	setString(params, "filename", f.FileName)
+	setString(params, "datapool", f.Datapool)
+	setString(params, "domain", f.Domain)
+	return params
+}
+
+// ListFiltered returns the first page of ISO images matching filter
+func (d *IsoService) ListFiltered(filter IsoFilter) (*IsosResponse, *http.Response, error) {
+	return d.ListFilteredContext(context.Background(), filter)
+}
+
+// ListFilteredContext returns the first page of ISO images matching filter
+func (d *IsoService) ListFilteredContext(ctx context.Context, filter IsoFilter) (*IsosResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "IsoService.ListFiltered")
 	response := new(IsosResponse)
-	res, err := d.client.ExecuteRequest("GET", listUrl, []byte{}, response)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", buildListUrl(baseIsoUrl, filter), []byte{}, response)
 	return response, res, err
 }
 
+// IsoIterator streams ISO images page by page
+type IsoIterator struct {
+	*Iterator
+	item IsoObjectsList
+}
+
+// Next advances to the next ISO image
+func (it *IsoIterator) Next() bool {
+	it.item = IsoObjectsList{}
+	return it.Iterator.Next() && it.decode(&it.item)
+}
+
+// Item returns the current ISO image
+func (it *IsoIterator) Item() IsoObjectsList {
+	return it.item
+}
+
+// Iter returns an iterator over all ISO images matching filter, filter and opts may be nil
+func (d *IsoService) Iter(ctx context.Context, filter ListFilter, opts *PageOptions) *IsoIterator {
+	ctx = withOperation(ctx, "IsoService.Iter")
+	return &IsoIterator{Iterator: newIterator(ctx, d.client, baseIsoUrl, filter, opts)}
+}
+
+// ListAll returns all ISO images matching filter following the pages until exhausted
+func (d *IsoService) ListAll(ctx context.Context, filter ListFilter) ([]IsoObjectsList, error) {
+	ctx = withOperation(ctx, "IsoService.ListAll")
+	it := d.Iter(ctx, filter, nil)
+	defer it.Close()
+	var items []IsoObjectsList
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
+
 func (d *IsoService) Get(Id string) (*IsoObject, *http.Response, error) {
+	return d.GetContext(context.Background(), Id)
+}
+
+func (d *IsoService) GetContext(ctx context.Context, Id string) (*IsoObject, *http.Response, error) {
+	ctx = withOperation(ctx, "IsoService.Get")
 	entity := new(IsoObject)
-	res, err := d.client.ExecuteRequest("GET", fmt.Sprint(baseIsoUrl, Id, "/"), []byte{}, entity)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", fmt.Sprint(baseIsoUrl, Id, "/"), []byte{}, entity)
 	return entity, res, err
 }
 
 func (d *IsoService) Create(DataPoolId string, FilenameUrl string, timeout int64) (*IsoObject, error) {
+	return d.CreateContext(context.Background(), DataPoolId, FilenameUrl, timeout)
+}
+
+func (d *IsoService) CreateContext(ctx context.Context, DataPoolId string, FilenameUrl string, timeout int64) (*IsoObject, error) {
+	ctx, finish := startOperation(ctx, d.client, "IsoService.Create", "")
+	entity, err := d.create(ctx, DataPoolId, FilenameUrl, timeout)
+	finish(err)
+	return entity, err
+}
+
+func (d *IsoService) create(ctx context.Context, DataPoolId string, FilenameUrl string, timeout int64) (*IsoObject, error) {
 	if timeout == 0 {
 		timeout = IsoUrlUploadTimeout
 	}
@@ -114,7 +198,7 @@ func (d *IsoService) Create(DataPoolId string, FilenameUrl string, timeout int64
 		}
 	}
 	b, _ := json.Marshal(body)
-	_, err := d.client.ExecuteRequest("PUT", baseIsoUrl, b, entity)
+	_, err := d.client.ExecuteRequestContext(ctx, "PUT", baseIsoUrl, b, entity)
 	if err != nil {
 		return nil, err
 	}
@@ -123,14 +207,16 @@ func (d *IsoService) Create(DataPoolId string, FilenameUrl string, timeout int64
 	if isUrl {
 		timeoutTime := time.Now().Unix() + timeout
 		for true {
-			_, err := d.client.ExecuteRequest("GET", fmt.Sprint(baseIsoUrl, entity.Id, "/"), []byte{}, entity)
+			_, err := d.client.ExecuteRequestContext(ctx, "GET", fmt.Sprint(baseIsoUrl, entity.Id, "/"), []byte{}, entity)
 			if entity.Status == Status.Active {
 				return entity, err
 			}
 			if time.Now().Unix() > timeoutTime {
 				return entity, fmt.Errorf("error uploading file by url: %w", err)
 			}
-			time.Sleep(time.Second * StatusCheckInterval)
+			if err := sleepContext(ctx, time.Second*StatusCheckInterval); err != nil {
+				return entity, err
+			}
 		}
 	} else {
 		file, err := os.Open("../file_data/" + FilenameUrl)
@@ -152,21 +238,35 @@ func (d *IsoService) Create(DataPoolId string, FilenameUrl string, timeout int64
 		if err != nil {
 			return nil, err
 		}
-		request, err := http.NewRequest("POST", fmt.Sprint(GetEnvUrl(), entity.UploadUrl), fileBody)
+		request, err := http.NewRequestWithContext(ctx, "POST", entity.UploadUrl, fileBody)
+		if err != nil {
+			return entity, err
+		}
 		request.Header.Add("Content-Type", writer.FormDataContentType())
 		response, err := d.client.Execute(request)
-		defer response.Body.Close()
 		if err != nil {
-			log.Fatal(err)
+			return entity, fmt.Errorf("upload iso file: %w", err)
 		}
+		defer response.Body.Close()
 	}
 
 	return entity, err
 }
 
 func (d *IsoService) Download(entity *IsoObject) (*IsoObject, *http.Response, error) {
+	return d.DownloadContext(context.Background(), entity)
+}
+
+func (d *IsoService) DownloadContext(ctx context.Context, entity *IsoObject) (*IsoObject, *http.Response, error) {
+	ctx, finish := startOperation(ctx, d.client, "IsoService.Download", entity.Id)
+	entity, res, err := d.download(ctx, entity)
+	finish(err)
+	return entity, res, err
+}
+
+func (d *IsoService) download(ctx context.Context, entity *IsoObject) (*IsoObject, *http.Response, error) {
 	// Get download_url
-	res, err := d.client.ExecuteRequest("PUT", fmt.Sprint(baseIsoUrl, entity.Id, "/download/"), []byte{}, entity)
+	res, err := d.client.ExecuteRequestContext(ctx, "PUT", fmt.Sprint(baseIsoUrl, entity.Id, "/download/"), []byte{}, entity)
 	if err != nil {
 		return entity, res, err
 	}
@@ -177,7 +277,11 @@ func (d *IsoService) Download(entity *IsoObject) (*IsoObject, *http.Response, er
 	defer out.Close()
 
 	// Get the data
-	resp, err := http.Get(fmt.Sprint(GetEnvUrl(), entity.DownloadUrl))
+	req, err := http.NewRequestWithContext(ctx, "GET", entity.DownloadUrl, nil)
+	if err != nil {
+		return entity, res, err
+	}
+	resp, err := d.client.Execute(req)
 	if err != nil {
 		return entity, res, fmt.Errorf("get the data error: %w", err)
 	}
@@ -205,7 +309,13 @@ func (d *IsoService) Download(entity *IsoObject) (*IsoObject, *http.Response, er
 
 // Remove Эндпоинт удаления образа
 func (d *IsoService) Remove(Id string) (bool, *http.Response, error) {
-	res, err := d.client.ExecuteRequest("POST", fmt.Sprint(baseIsoUrl, Id, "/remove/"), []byte{}, nil)
+	return d.RemoveContext(context.Background(), Id)
+}
+
+// RemoveContext Эндпоинт удаления образа
+func (d *IsoService) RemoveContext(ctx context.Context, Id string) (bool, *http.Response, error) {
+	ctx = withOperation(ctx, "IsoService.Remove")
+	res, err := d.client.ExecuteRequestContext(ctx, "POST", fmt.Sprint(baseIsoUrl, Id, "/remove/"), []byte{}, nil)
 	if err != nil {
 		return false, res, err
 	}
@@ -1,13 +1,15 @@
 package veil
 
 import (
+	"github.com/jsc-masshtab/veil-api-client-go/veil/veiltest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"testing"
 )
 
 func Test_IsoListGet(t *testing.T) {
-	client := NewClient("", "", false)
+	server, client := newTestServerClient(t)
+	server.Add(veiltest.Isos, veiltest.Object{"filename": "install.iso", "status": "ACTIVE"})
 	response, _, err := client.Iso.List()
 	assert.Nil(t, err)
 	for _, v := range response.Results {
@@ -22,7 +24,7 @@ func Test_IsoListGet(t *testing.T) {
 }
 
 func Test_IsoUpload(t *testing.T) {
-	client := NewClient("", "", false)
+	client := liveClient(t)
 	response, _, err := client.DataPool.List()
 	require.Nil(t, err)
 	if len(response.Results) == 0 {
@@ -41,15 +43,14 @@ func Test_IsoUpload(t *testing.T) {
 }
 
 func Test_IsoUploadUrl(t *testing.T) {
-	client := NewClient("", "", false)
+	_, client := newTestServerClient(t)
 	response, _, err := client.DataPool.List()
 	require.Nil(t, err)
 	if len(response.Results) == 0 {
 		t.SkipNow()
 	}
 	firstDp := response.Results[0]
-	// TODO Check filename exists on datapool
-	iso, err := client.Iso.Create(firstDp.Id, "http://192.168.10.144/test_helper/test_live.iso", 0)
+	iso, err := client.Iso.Create(firstDp.Id, "https://example.com/test_helper/test_live.iso", 0)
 	assert.Nil(t, err)
 	assert.NotEqual(t, iso.Id, "", "Iso Id can not be empty")
 	assert.Equal(t, iso.Status, Status.Active, "Iso Status should be Active")
@@ -62,7 +63,7 @@ func Test_IsoUploadUrl(t *testing.T) {
 }
 
 func Test_IsoDownload(t *testing.T) {
-	client := NewClient("", "", false)
+	client := liveClient(t)
 	response, _, err := client.Iso.List()
 	assert.Nil(t, err)
 	if len(response.Results) == 0 {
@@ -2,6 +2,7 @@ package veil
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -60,6 +61,7 @@ type LibraryObject struct {
 }
 
 type FileImportConfig struct {
+	IdempotencyKeyBase
 	VerboseName  string `json:"verbose_name,omitempty"`
 	WithDeletion bool   `json:"with_deletion,omitempty"`
 	Preallocate  bool   `json:"preallocate,omitempty"`
@@ -72,47 +74,152 @@ type LibraryResponse struct {
 }
 
 func (d *LibraryService) List() (*LibraryResponse, *http.Response, error) {
+	return d.ListContext(context.Background())
+}
+
+func (d *LibraryService) ListContext(ctx context.Context) (*LibraryResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "LibraryService.List")
 	response := new(LibraryResponse)
-	res, err := d.client.ExecuteRequest("GET", baseLibraryUrl, []byte{}, response)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", baseLibraryUrl, []byte{}, response)
 	return response, res, err
 }
 
 func (d *LibraryService) ListParams(queryParams map[string]string) (*LibraryResponse, *http.Response, error) {
-	listUrl := baseLibraryUrl
-	if len(queryParams) != 0 {
-		params := url.Values{}
-		for k, v := range queryParams {
-			params.Add(k, v)
-		}
-		listUrl += "?"
-		listUrl += params.Encode()
-	}
+	return d.ListParamsContext(context.Background(), queryParams)
+}
+
+func (d *LibraryService) ListParamsContext(ctx context.Context, queryParams map[string]string) (*LibraryResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "LibraryService.ListParams")
+	response := new(LibraryResponse)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", buildListUrl(baseLibraryUrl, Params(queryParams)), []byte{}, response)
+	return response, res, err
+}
+
+// LibraryFilter typed filter of library files, zero fields are not sent
+type LibraryFilter struct {
+	ListOptions
+	Status   []string
+	FileName string
+	Datapool string
+	Domain   string
+}
+
+// Values encodes the filter to query parameters
+func (f LibraryFilter) Values() url.Values {
+	params := f.ListOptions.Values()
+	addStrings(params, "status", f.Status)
+	setString(params, "filename", f.FileName)
+	setString(params, "datapool", f.Datapool)
+	setString(params, "domain", f.Domain)
+	return params
+}
+
+// ListFiltered returns the first page of library files matching filter
+func (d *LibraryService) ListFiltered(filter LibraryFilter) (*LibraryResponse, *http.Response, error) {
+	return d.ListFilteredContext(context.Background(), filter)
+}
+
+// ListFilteredContext returns the first page of library files matching filter
+func (d *LibraryService) ListFilteredContext(ctx context.Context, filter LibraryFilter) (*LibraryResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "LibraryService.ListFiltered")
 	response := new(LibraryResponse)
-	res, err := d.client.ExecuteRequest("GET", listUrl, []byte{}, response)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", buildListUrl(baseLibraryUrl, filter), []byte{}, response)
 	return response, res, err
 }
 
+// LibraryIterator streams library files page by page
+type LibraryIterator struct {
+	*Iterator
+	item LibraryObjectsList
+}
+
+// Next advances to the next library file
+func (it *LibraryIterator) Next() bool {
+	it.item = LibraryObjectsList{}
+	return it.Iterator.Next() && it.decode(&it.item)
+}
+
+// Item returns the current library file
+func (it *LibraryIterator) Item() LibraryObjectsList {
+	return it.item
+}
+
+// Iter returns an iterator over all library files matching filter, filter and opts may be nil
+func (d *LibraryService) Iter(ctx context.Context, filter ListFilter, opts *PageOptions) *LibraryIterator {
+	ctx = withOperation(ctx, "LibraryService.Iter")
+	return &LibraryIterator{Iterator: newIterator(ctx, d.client, baseLibraryUrl, filter, opts)}
+}
+
+// ListAll returns all library files matching filter following the pages until exhausted
+func (d *LibraryService) ListAll(ctx context.Context, filter ListFilter) ([]LibraryObjectsList, error) {
+	ctx = withOperation(ctx, "LibraryService.ListAll")
+	it := d.Iter(ctx, filter, nil)
+	defer it.Close()
+	var items []LibraryObjectsList
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
+
 func (d *LibraryService) Get(Id string) (*LibraryObject, *http.Response, error) {
+	return d.GetContext(context.Background(), Id)
+}
+
+func (d *LibraryService) GetContext(ctx context.Context, Id string) (*LibraryObject, *http.Response, error) {
+	ctx = withOperation(ctx, "LibraryService.Get")
 	entity := new(LibraryObject)
-	res, err := d.client.ExecuteRequest("GET", fmt.Sprint(baseLibraryUrl, Id, "/"), []byte{}, entity)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", fmt.Sprint(baseLibraryUrl, Id, "/"), []byte{}, entity)
 	return entity, res, err
 }
 
 func (d *LibraryService) Import(Id string, config FileImportConfig) (*VdiskObject, *http.Response, error) {
-	entity := new(VdiskObject)
+	return d.ImportContext(context.Background(), Id, config)
+}
+
+func (d *LibraryService) ImportContext(ctx context.Context, Id string, config FileImportConfig) (*VdiskObject, *http.Response, error) {
+	ctx, finish := startOperation(ctx, d.client, "LibraryService.Import", Id)
+	op, res, err := d.StartImport(ctx, Id, config)
+	if err != nil {
+		finish(err)
+		return new(VdiskObject), res, err
+	}
+	entity, err := op.Wait(ctx)
+	finish(err)
+	return entity, op.response(res), err
+}
+
+// StartImport starts import of the file to a virtual disk and returns without waiting for the task
+func (d *LibraryService) StartImport(ctx context.Context, Id string, config FileImportConfig) (*VdiskOperation, *http.Response, error) {
+	ctx = withOperation(ctx, "LibraryService.StartImport")
+	ctx = config.IdempotencyKeyBase.apply(ctx)
 	b, _ := json.Marshal(config)
 	asyncResp := new(AsyncEntityResponse)
-	res, err := d.client.ExecuteRequest("POST", fmt.Sprint(baseLibraryUrl, Id, "/import-file/?async=1"), b, asyncResp)
+	res, err := d.client.ExecuteRequestContext(ctx, "POST", fmt.Sprint(baseLibraryUrl, Id, "/import-file/?async=1"), b, asyncResp)
 	if err != nil {
-		return entity, res, err
+		return nil, res, err
 	}
-	client := d.client.RetClient()
-	taskObj := WaitTaskReady(client, asyncResp.Task.Id, true, 0, true)
-	res, err = client.Task.Response(taskObj.Id, entity)
-	return entity, res, err
+	op, err := newVdiskOperation(d.client, OperationState{OperationKind.LibraryImport, asyncResp.Task.Id, asyncResp.Entity})
+	return op, res, err
+}
+
+// ResumeOperation restores the handle of an operation started by StartImport
+func (d *LibraryService) ResumeOperation(state OperationState) (*VdiskOperation, error) {
+	return newVdiskOperation(d.client, state)
 }
 
 func (d *LibraryService) Create(DataPoolId string, FilenameUrl string, timeout int64) (*LibraryObject, error) {
+	return d.CreateContext(context.Background(), DataPoolId, FilenameUrl, timeout)
+}
+
+func (d *LibraryService) CreateContext(ctx context.Context, DataPoolId string, FilenameUrl string, timeout int64) (*LibraryObject, error) {
+	ctx, finish := startOperation(ctx, d.client, "LibraryService.Create", "")
+	entity, err := d.create(ctx, DataPoolId, FilenameUrl, timeout)
+	finish(err)
+	return entity, err
+}
+
+func (d *LibraryService) create(ctx context.Context, DataPoolId string, FilenameUrl string, timeout int64) (*LibraryObject, error) {
 	if timeout == 0 {
 		timeout = LibraryUrlUploadTimeout
 	}
@@ -131,14 +238,14 @@ func (d *LibraryService) Create(DataPoolId string, FilenameUrl string, timeout i
 		}
 	}
 	b, _ := json.Marshal(body)
-	_, err := d.client.ExecuteRequest("PUT", baseLibraryUrl, b, entity)
+	_, err := d.client.ExecuteRequestContext(ctx, "PUT", baseLibraryUrl, b, entity)
 	if err != nil {
 		return nil, err
 	}
 
 	// Part 2
 	if isUrl {
-		request, err := http.NewRequest("POST", fmt.Sprint(GetEnvUrl(), entity.UploadUrl), nil)
+		request, err := http.NewRequestWithContext(ctx, "POST", entity.UploadUrl, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -148,14 +255,16 @@ func (d *LibraryService) Create(DataPoolId string, FilenameUrl string, timeout i
 		}
 		timeoutTime := time.Now().Unix() + timeout
 		for true {
-			_, err := d.client.ExecuteRequest("GET", fmt.Sprint(baseLibraryUrl, entity.Id, "/"), []byte{}, entity)
+			_, err := d.client.ExecuteRequestContext(ctx, "GET", fmt.Sprint(baseLibraryUrl, entity.Id, "/"), []byte{}, entity)
 			if entity.Status == Status.Active {
 				return entity, err
 			}
 			if time.Now().Unix() > timeoutTime {
 				return entity, fmt.Errorf("error uploading file by url: %w", err)
 			}
-			time.Sleep(time.Second * StatusCheckInterval)
+			if err := sleepContext(ctx, time.Second*StatusCheckInterval); err != nil {
+				return entity, err
+			}
 		}
 	} else {
 		file, err := os.Open("../file_data/" + FilenameUrl)
@@ -177,20 +286,34 @@ func (d *LibraryService) Create(DataPoolId string, FilenameUrl string, timeout i
 		if err != nil {
 			return nil, err
 		}
-		request, err := http.NewRequest("POST", fmt.Sprint(GetEnvUrl(), entity.UploadUrl), fileBody)
+		request, err := http.NewRequestWithContext(ctx, "POST", entity.UploadUrl, fileBody)
+		if err != nil {
+			return nil, err
+		}
 		request.Header.Add("Content-Type", writer.FormDataContentType())
 		response, err := d.client.Execute(request)
-		defer response.Body.Close()
 		if err != nil {
 			return nil, err
 		}
+		defer response.Body.Close()
 	}
 	return entity, nil
 }
 
 func (d *LibraryService) Download(entity *LibraryObject) (*LibraryObject, *http.Response, error) {
+	return d.DownloadContext(context.Background(), entity)
+}
+
+func (d *LibraryService) DownloadContext(ctx context.Context, entity *LibraryObject) (*LibraryObject, *http.Response, error) {
+	ctx, finish := startOperation(ctx, d.client, "LibraryService.Download", entity.Id)
+	entity, res, err := d.download(ctx, entity)
+	finish(err)
+	return entity, res, err
+}
+
+func (d *LibraryService) download(ctx context.Context, entity *LibraryObject) (*LibraryObject, *http.Response, error) {
 	// Get download_url
-	res, err := d.client.ExecuteRequest("PUT", fmt.Sprint(baseLibraryUrl, entity.Id, "/download/"), []byte{}, entity)
+	res, err := d.client.ExecuteRequestContext(ctx, "PUT", fmt.Sprint(baseLibraryUrl, entity.Id, "/download/"), []byte{}, entity)
 	if err != nil {
 		return entity, res, err
 	}
@@ -201,7 +324,11 @@ func (d *LibraryService) Download(entity *LibraryObject) (*LibraryObject, *http.
 	defer out.Close()
 
 	// Get the data
-	resp, err := http.Get(fmt.Sprint(GetEnvUrl(), entity.DownloadUrl))
+	req, err := http.NewRequestWithContext(ctx, "GET", entity.DownloadUrl, nil)
+	if err != nil {
+		return entity, res, err
+	}
+	resp, err := d.client.Execute(req)
 	if err != nil {
 		return entity, res, fmt.Errorf("get the data error: %w", err)
 	}
@@ -229,7 +356,13 @@ func (d *LibraryService) Download(entity *LibraryObject) (*LibraryObject, *http.
 
 // Remove Эндпоинт удаления файла
 func (d *LibraryService) Remove(Id string) (bool, *http.Response, error) {
-	res, err := d.client.ExecuteRequest("POST", fmt.Sprint(baseLibraryUrl, Id, "/remove/"), []byte{}, nil)
+	return d.RemoveContext(context.Background(), Id)
+}
+
+// RemoveContext Эндпоинт удаления файла
+func (d *LibraryService) RemoveContext(ctx context.Context, Id string) (bool, *http.Response, error) {
+	ctx = withOperation(ctx, "LibraryService.Remove")
+	res, err := d.client.ExecuteRequestContext(ctx, "POST", fmt.Sprint(baseLibraryUrl, Id, "/remove/"), []byte{}, nil)
 	if err != nil {
 		return false, res, err
 	}
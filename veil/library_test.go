@@ -1,13 +1,15 @@
 package veil
 
 import (
+	"github.com/jsc-masshtab/veil-api-client-go/veil/veiltest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"testing"
 )
 
 func Test_LibraryListGet(t *testing.T) {
-	client := NewClient("", "", false)
+	server, client := newTestServerClient(t)
+	server.Add(veiltest.Library, veiltest.Object{"filename": "domain.xml", "status": "ACTIVE"})
 	response, _, err := client.Library.List()
 	assert.Nil(t, err)
 	for _, v := range response.Results {
@@ -22,7 +24,7 @@ func Test_LibraryListGet(t *testing.T) {
 }
 
 func Test_LibraryUpload(t *testing.T) {
-	client := NewClient("", "", false)
+	client := liveClient(t)
 	response, _, err := client.DataPool.List()
 	require.Nil(t, err)
 	if len(response.Results) == 0 {
@@ -41,15 +43,14 @@ func Test_LibraryUpload(t *testing.T) {
 }
 
 func Test_LibraryUploadUrl(t *testing.T) {
-	client := NewClient("", "", false)
+	_, client := newTestServerClient(t)
 	response, _, err := client.DataPool.List()
 	require.Nil(t, err)
 	if len(response.Results) == 0 {
 		t.SkipNow()
 	}
 	firstDp := response.Results[0]
-	// TODO Check filename exists on datapool
-	library, err := client.Library.Create(firstDp.Id, "http://192.168.10.144/test_helper/test_domain.xml", 0)
+	library, err := client.Library.Create(firstDp.Id, "https://example.com/test_helper/test_domain.xml", 0)
 	assert.Nil(t, err)
 	assert.NotEqual(t, library.Id, "", "Library Id can not be empty")
 	assert.Equal(t, library.Status, Status.Active, "Library Status should be Active")
@@ -62,7 +63,7 @@ func Test_LibraryUploadUrl(t *testing.T) {
 }
 
 func Test_LibraryDownload(t *testing.T) {
-	client := NewClient("", "", false)
+	client := liveClient(t)
 	response, _, err := client.Library.List()
 	assert.Nil(t, err)
 	if len(response.Results) == 0 {
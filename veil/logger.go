@@ -0,0 +1,164 @@
+package veil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// LogLevel severity of log record, values match levels of log/slog
+type LogLevel int
+
+const (
+	LevelDebug LogLevel = -4
+	LevelInfo  LogLevel = 0
+	LevelWarn  LogLevel = 4
+	LevelError LogLevel = 8
+)
+
+func (l LogLevel) String() string {
+	switch {
+	case l < LevelInfo:
+		return "DEBUG"
+	case l < LevelWarn:
+		return "INFO"
+	case l < LevelError:
+		return "WARN"
+	default:
+		return "ERROR"
+	}
+}
+
+// Logger structured logger of the client, keysAndValues are alternating keys and values like in log/slog.
+// *slog.Logger is adapted by one line: logger.Log(ctx, slog.Level(level), msg, keysAndValues...)
+type Logger interface {
+	Log(ctx context.Context, level LogLevel, msg string, keysAndValues ...interface{})
+}
+
+// LoggerFunc adapts function to Logger
+type LoggerFunc func(ctx context.Context, level LogLevel, msg string, keysAndValues ...interface{})
+
+// Log calls f
+func (f LoggerFunc) Log(ctx context.Context, level LogLevel, msg string, keysAndValues ...interface{}) {
+	f(ctx, level, msg, keysAndValues...)
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Log(ctx context.Context, level LogLevel, msg string, keysAndValues ...interface{}) {}
+
+// NopLogger discards all records, it is used if WebClient.Logger is nil
+var NopLogger Logger = nopLogger{}
+
+type stdLogger struct {
+	out   *log.Logger
+	level LogLevel
+}
+
+// NewStdLogger writes records of level and above to out in logfmt style, nil out means the standard logger
+func NewStdLogger(out *log.Logger, level LogLevel) Logger {
+	if out == nil {
+		out = log.Default()
+	}
+	return &stdLogger{out: out, level: level}
+}
+
+func (l *stdLogger) Log(ctx context.Context, level LogLevel, msg string, keysAndValues ...interface{}) {
+	if level < l.level {
+		return
+	}
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "level=%s msg=%s", level, logfmtValue(msg))
+	for i := 0; i < len(keysAndValues); i += 2 {
+		var value interface{} = "!MISSING"
+		if i+1 < len(keysAndValues) {
+			value = keysAndValues[i+1]
+		}
+		fmt.Fprintf(b, " %v=%s", keysAndValues[i], logfmtValue(fmt.Sprint(value)))
+	}
+	l.out.Print(b.String())
+}
+
+func logfmtValue(s string) string {
+	if s == "" || strings.ContainsAny(s, " =\"\t\n") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// redactedHeaders are never written to log
+var redactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// RedactHeaders returns copy of headers with secrets replaced by "[REDACTED]"
+func RedactHeaders(header http.Header) http.Header {
+	redacted := header.Clone()
+	for _, name := range redactedHeaders {
+		if redacted.Get(name) != "" {
+			redacted.Set(name, "[REDACTED]")
+		}
+	}
+	return redacted
+}
+
+// redactedFields JSON fields of request and response bodies which are never written to log
+var redactedFields = map[string]bool{"password": true, "token": true, "refresh_token": true, "jwt": true}
+
+// RedactBody returns copy of JSON body with secret fields replaced by "[REDACTED]" at any depth.
+// Body without secrets or not in JSON is returned as is
+func RedactBody(body []byte) []byte {
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil || !redactValue(value) {
+		return body
+	}
+	redacted, err := json.Marshal(value)
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+// redactValue replaces secret fields of decoded JSON in place, it reports whether any field was replaced
+func redactValue(value interface{}) bool {
+	redacted := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if redactedFields[strings.ToLower(key)] {
+				v[key] = "[REDACTED]"
+				redacted = true
+			} else if redactValue(field) {
+				redacted = true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if redactValue(item) {
+				redacted = true
+			}
+		}
+	}
+	return redacted
+}
+
+// logger returns Logger of the client, never nil
+func (client *WebClient) logger() Logger {
+	if client.Logger == nil {
+		return NopLogger
+	}
+	return client.Logger
+}
+
+// loggerOf returns logger of c if it is able to log, e.g. mocks are not
+func loggerOf(c Client) Logger {
+	if l, ok := c.(interface{ logger() Logger }); ok {
+		return l.logger()
+	}
+	return NopLogger
+}
@@ -0,0 +1,95 @@
+package veil
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type logRecord struct {
+	level  LogLevel
+	msg    string
+	fields map[string]interface{}
+}
+
+type recordingLogger struct {
+	sync.Mutex
+	records []logRecord
+}
+
+func (l *recordingLogger) Log(ctx context.Context, level LogLevel, msg string, keysAndValues ...interface{}) {
+	l.Lock()
+	defer l.Unlock()
+	fields := map[string]interface{}{}
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		fields[fmt.Sprint(keysAndValues[i])] = keysAndValues[i+1]
+	}
+	l.records = append(l.records, logRecord{level, msg, fields})
+}
+
+func (l *recordingLogger) find(msg string) []logRecord {
+	l.Lock()
+	defer l.Unlock()
+	var found []logRecord
+	for _, r := range l.records {
+		if r.msg == msg {
+			found = append(found, r)
+		}
+	}
+	return found
+}
+
+func Test_LoggerRequestsAndTasks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case baseTaskUrl + "task-1/":
+			w.Write([]byte(`{"id": "task-1", "name": "Start domain", "status": "SUCCESS", "progress": 100}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors": [{"detail": "not found"}]}`))
+		}
+	}))
+	defer server.Close()
+	logger := &recordingLogger{}
+	client, err := NewClientWithOptions(WithBaseURL(server.URL), WithToken("secret-token"), WithLogger(logger))
+	require.NoError(t, err)
+
+	_, err = client.Task.Wait(context.Background(), "task-1", &TaskWaitOptions{PollInterval: time.Millisecond})
+	require.NoError(t, err)
+	_, _, err = client.Node.Get("missing")
+	require.True(t, IsNotFound(err))
+
+	requests := logger.find("veil request")
+	require.Len(t, requests, 2)
+	assert.Equal(t, LevelDebug, requests[0].level)
+	assert.Equal(t, "GET", requests[0].fields["method"])
+	assert.Equal(t, server.URL+baseTaskUrl+"task-1/", requests[0].fields["url"])
+	assert.Equal(t, 200, requests[0].fields["status"])
+	assert.Contains(t, requests[0].fields, "duration")
+	assert.Equal(t, "[REDACTED]", requests[0].fields["headers"].(http.Header).Get("Authorization"))
+	assert.Equal(t, LevelWarn, requests[1].level)
+	assert.Equal(t, 404, requests[1].fields["status"])
+
+	finished := logger.find("veil task finished")
+	require.Len(t, finished, 1)
+	assert.Equal(t, "task-1", finished[0].fields["task_id"])
+	assert.Equal(t, "Start domain", finished[0].fields["name"])
+	return
+}
+
+func Test_StdLogger(t *testing.T) {
+	out := &bytes.Buffer{}
+	logger := NewStdLogger(log.New(out, "", 0), LevelInfo)
+	logger.Log(context.Background(), LevelDebug, "hidden")
+	logger.Log(context.Background(), LevelWarn, "veil request", "method", "GET", "error", "connection refused", "odd")
+	assert.Equal(t, "level=WARN msg=\"veil request\" method=GET error=\"connection refused\" odd=!MISSING\n", out.String())
+	return
+}
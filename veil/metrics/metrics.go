@@ -0,0 +1,156 @@
+// Package metrics provides Prometheus metrics of veil API client: latency of requests by endpoint template,
+// retries, errors and durations of task waits.
+//
+//	collector := metrics.New(nil)
+//	prometheus.MustRegister(collector)
+//	collector.Instrument(client)
+package metrics
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/jsc-masshtab/veil-api-client-go/veil"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultTaskBuckets buckets of task wait durations in seconds, tasks of VeiL take from seconds to tens of minutes
+var DefaultTaskBuckets = []float64{1, 2.5, 5, 10, 30, 60, 120, 300, 600, 1200, 3600}
+
+// Options of collector, zero values mean defaults
+type Options struct {
+	// Namespace prefix of metric names, "veil_client" by default
+	Namespace string
+	// RequestBuckets buckets of request latency in seconds, prometheus.DefBuckets by default
+	RequestBuckets []float64
+	// TaskBuckets buckets of task wait duration in seconds, DefaultTaskBuckets by default
+	TaskBuckets []float64
+	// ConstLabels are added to all metrics, e.g. controller name
+	ConstLabels prometheus.Labels
+}
+
+// Collector collects metrics of API calls and task waits, it implements prometheus.Collector,
+// veil.Middleware (see Middleware) and veil.TaskObserver
+type Collector struct {
+	requestDuration *prometheus.HistogramVec
+	retries         *prometheus.CounterVec
+	errors          *prometheus.CounterVec
+	taskDuration    *prometheus.HistogramVec
+	tasks           *prometheus.CounterVec
+}
+
+// New creates collector, opts may be nil
+func New(opts *Options) *Collector {
+	if opts == nil {
+		opts = &Options{}
+	}
+	namespace := opts.Namespace
+	if namespace == "" {
+		namespace = "veil_client"
+	}
+	requestBuckets := opts.RequestBuckets
+	if requestBuckets == nil {
+		requestBuckets = prometheus.DefBuckets
+	}
+	taskBuckets := opts.TaskBuckets
+	if taskBuckets == nil {
+		taskBuckets = DefaultTaskBuckets
+	}
+	return &Collector{
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   namespace,
+			Name:        "request_duration_seconds",
+			Help:        "Duration of VeiL API calls including retries by endpoint template and status.",
+			Buckets:     requestBuckets,
+			ConstLabels: opts.ConstLabels,
+		}, []string{"method", "endpoint", "status"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        "request_retries_total",
+			Help:        "Number of retried VeiL API requests.",
+			ConstLabels: opts.ConstLabels,
+		}, []string{"method", "endpoint"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        "request_errors_total",
+			Help:        "Number of failed VeiL API calls by status, status is \"error\" if no response was received.",
+			ConstLabels: opts.ConstLabels,
+		}, []string{"method", "endpoint", "status"}),
+		taskDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   namespace,
+			Name:        "task_wait_duration_seconds",
+			Help:        "Duration of waiting VeiL tasks by outcome.",
+			Buckets:     taskBuckets,
+			ConstLabels: opts.ConstLabels,
+		}, []string{"outcome"}),
+		tasks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        "tasks_total",
+			Help:        "Number of waited VeiL tasks by outcome.",
+			ConstLabels: opts.ConstLabels,
+		}, []string{"outcome"}),
+	}
+}
+
+// Instrument adds middleware of the collector to the beginning of the client chain and its task observer
+func (c *Collector) Instrument(client *veil.WebClient) {
+	client.Middleware = append([]veil.Middleware{c.Middleware()}, client.Middleware...)
+	client.TaskObservers = append(client.TaskObservers, c)
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.requestDuration.Describe(ch)
+	c.retries.Describe(ch)
+	c.errors.Describe(ch)
+	c.taskDuration.Describe(ch)
+	c.tasks.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.requestDuration.Collect(ch)
+	c.retries.Collect(ch)
+	c.errors.Collect(ch)
+	c.taskDuration.Collect(ch)
+	c.tasks.Collect(ch)
+}
+
+// Middleware measures API calls, it should be the outermost middleware to include all retries
+func (c *Collector) Middleware() veil.Middleware {
+	return func(next veil.Handler) veil.Handler {
+		return func(ctx context.Context, call *veil.Call) error {
+			start := time.Now()
+			err := next(ctx, call)
+			endpoint := veil.EndpointTemplate(call.URL)
+			status := "error"
+			if call.Response != nil {
+				status = strconv.Itoa(call.Response.StatusCode)
+			}
+			c.requestDuration.WithLabelValues(call.Method, endpoint, status).Observe(time.Since(start).Seconds())
+			if call.Attempts > 1 {
+				c.retries.WithLabelValues(call.Method, endpoint).Add(float64(call.Attempts - 1))
+			}
+			if err != nil {
+				c.errors.WithLabelValues(call.Method, endpoint, status).Inc()
+			}
+			return err
+		}
+	}
+}
+
+// TaskWaitStarted implements veil.TaskObserver
+func (c *Collector) TaskWaitStarted(ctx context.Context, taskId string) context.Context {
+	return ctx
+}
+
+// TaskPolled implements veil.TaskObserver
+func (c *Collector) TaskPolled(ctx context.Context, task *veil.TaskObject) {}
+
+// TaskWaitFinished implements veil.TaskObserver
+func (c *Collector) TaskWaitFinished(ctx context.Context, taskId string, task *veil.TaskObject, elapsed time.Duration, err error) {
+	outcome := veil.TaskWaitOutcome(err)
+	c.taskDuration.WithLabelValues(outcome).Observe(elapsed.Seconds())
+	c.tasks.WithLabelValues(outcome).Inc()
+}
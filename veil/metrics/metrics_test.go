@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"github.com/jsc-masshtab/veil-api-client-go/veil"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const domainId = "7e5a1c3b-2b1e-4f7a-9d2c-5b8f0a6e4d21"
+
+func Test_Collector(t *testing.T) {
+	var starts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/start/"):
+			if atomic.AddInt32(&starts, 1) == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.Write([]byte(`{"_task": {"id": "task-1"}}`))
+		case r.URL.Path == "/api/tasks/task-1/":
+			w.Write([]byte(`{"id": "task-1", "status": "SUCCESS"}`))
+		case r.URL.Path == "/api/tasks/task-2/":
+			w.Write([]byte(`{"id": "task-2", "status": "FAILED", "error_message": "no space"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	retry := &veil.RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond,
+		RetryStatusCodes: []int{http.StatusBadGateway}}
+	client, err := veil.NewClientWithOptions(veil.WithBaseURL(server.URL), veil.WithRetryPolicy(retry))
+	require.NoError(t, err)
+	collector := New(&Options{ConstLabels: prometheus.Labels{"controller": "test"}})
+	collector.Instrument(client)
+	registry := prometheus.NewPedanticRegistry()
+	require.NoError(t, registry.Register(collector))
+
+	ctx := veil.WithIdempotencyKey(context.Background(), "start-1")
+	_, err = client.ExecuteRequestContext(ctx, "POST", "/api/domains/"+domainId+"/start/?async=1", []byte{}, nil)
+	require.NoError(t, err)
+	_, err = client.ExecuteRequest("GET", "/api/domains/"+domainId+"/", []byte{}, nil)
+	require.Error(t, err)
+	_, err = client.Task.Wait(context.Background(), "task-1", nil)
+	require.NoError(t, err)
+	_, err = client.Task.Wait(context.Background(), "task-2", nil)
+	require.True(t, veil.IsTaskFailed(err))
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(collector.retries.WithLabelValues("POST", "/api/domains/{id}/start/")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(collector.errors.WithLabelValues("GET", "/api/domains/{id}/", "404")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(collector.tasks.WithLabelValues(veil.TaskOutcomeSuccess)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(collector.tasks.WithLabelValues(veil.TaskOutcomeFailed)))
+
+	expected := `
+# HELP veil_client_tasks_total Number of waited VeiL tasks by outcome.
+# TYPE veil_client_tasks_total counter
+veil_client_tasks_total{controller="test",outcome="failed"} 1
+veil_client_tasks_total{controller="test",outcome="success"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "veil_client_tasks_total"))
+	count, err := testutil.GatherAndCount(registry, "veil_client_request_duration_seconds")
+	require.NoError(t, err)
+	// start, domain and task polls, ids of both tasks are replaced in the endpoint
+	assert.Equal(t, 3, count)
+	return
+}
+
+func Test_CollectorOutermost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	// The middleware rejects calls without sending them, they are measured only by an outer collector
+	deny := func(next veil.Handler) veil.Handler {
+		return func(ctx context.Context, call *veil.Call) error {
+			return errors.New("denied")
+		}
+	}
+	client, err := veil.NewClientWithOptions(veil.WithBaseURL(server.URL), veil.WithMiddleware(deny))
+	require.NoError(t, err)
+	collector := New(nil)
+	collector.Instrument(client)
+	require.Len(t, client.Middleware, 2)
+
+	_, err = client.ExecuteRequest("GET", "/api/domains/"+domainId+"/", []byte{}, nil)
+	require.EqualError(t, err, "denied")
+	assert.Equal(t, 1.0, testutil.ToFloat64(collector.errors.WithLabelValues("GET", "/api/domains/{id}/", "error")))
+	return
+}
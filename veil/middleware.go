@@ -0,0 +1,100 @@
+package veil
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader header with unique id of API call, the same for all retries of the call
+const RequestIDHeader = "X-Request-ID"
+
+// Call single API call passing through middleware chain
+type Call struct {
+	Method string
+	// URL relative to BaseURL for API calls, absolute for Execute
+	URL string
+	// Header is added to every attempt of the request, middleware may change it before calling next
+	Header http.Header
+	// Body JSON body of API call, nil for Execute which streams Request.Body
+	Body []byte
+	// Object destination of decoded response, it is filled once next returns
+	Object interface{}
+	// Request user request of Execute, nil for API calls
+	Request *http.Request
+
+	// Response of the last attempt, it is set once next returns, nil if the request was not sent
+	Response *http.Response
+	// ResponseBody of API call, Execute does not read the body
+	ResponseBody []byte
+	// Attempts number of sent requests including retries
+	Attempts int
+}
+
+// Handler performs the call, error is an *APIError for unsuccessful responses
+type Handler func(ctx context.Context, call *Call) error
+
+// Middleware wraps handler with cross-cutting behaviour like audit, custom headers or request signing
+type Middleware func(next Handler) Handler
+
+// Use appends middleware to the end of the chain
+func (client *WebClient) Use(middleware ...Middleware) {
+	client.Middleware = append(client.Middleware, middleware...)
+}
+
+// chain wraps handler by middleware of the client
+func (client *WebClient) chain(handler Handler) Handler {
+	for i := len(client.Middleware) - 1; i >= 0; i-- {
+		handler = client.Middleware[i](handler)
+	}
+	return handler
+}
+
+// RequestIDMiddleware sets X-Request-ID header if it is not set, nil generate means random UUID
+func RequestIDMiddleware(generate func() string) Middleware {
+	if generate == nil {
+		generate = uuid.NewString
+	}
+	return func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) error {
+			if call.Header.Get(RequestIDHeader) == "" {
+				call.Header.Set(RequestIDHeader, generate())
+			}
+			return next(ctx, call)
+		}
+	}
+}
+
+// DumpOnErrorMiddleware logs request and response of failed calls with error level, secret headers
+// and JSON fields of bodies are redacted, see RedactHeaders and RedactBody.
+// Nil logger means standard logger
+func DumpOnErrorMiddleware(logger Logger) Middleware {
+	if logger == nil {
+		logger = NewStdLogger(nil, LevelError)
+	}
+	return func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) error {
+			err := next(ctx, call)
+			if err == nil {
+				return nil
+			}
+			fields := []interface{}{
+				"method", call.Method,
+				"url", call.URL,
+				"attempts", call.Attempts,
+				"request_headers", RedactHeaders(call.Header),
+				"request_body", string(RedactBody(call.Body)),
+				"error", err,
+			}
+			if call.Response != nil {
+				fields = append(fields,
+					"status", call.Response.StatusCode,
+					"response_headers", RedactHeaders(call.Response.Header),
+					"response_body", string(RedactBody(call.ResponseBody)))
+			}
+			logger.Log(ctx, LevelError, "veil call failed", fields...)
+			return err
+		}
+	}
+}
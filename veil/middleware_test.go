@@ -0,0 +1,130 @@
+package veil
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_MiddlewareChain(t *testing.T) {
+	var mu sync.Mutex
+	var requestIds []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requestIds = append(requestIds, r.Header.Get(RequestIDHeader))
+		first := len(requestIds) == 1
+		mu.Unlock()
+		assert.Equal(t, "audit", r.Header.Get("X-Audit"))
+		if first {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"id": "node-1", "verbose_name": "node"}`))
+	}))
+	defer server.Close()
+
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, call *Call) error {
+				order = append(order, name+" before "+call.Method+" "+call.URL)
+				err := next(ctx, call)
+				if node, ok := call.Object.(*NodeObject); ok {
+					order = append(order, name+" after "+node.VerboseName)
+				}
+				return err
+			}
+		}
+	}
+	audit := func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) error {
+			call.Header.Set("X-Audit", "audit")
+			return next(ctx, call)
+		}
+	}
+	retry := &RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond, RetryStatusCodes: []int{503}}
+	client, err := NewClientWithOptions(WithBaseURL(server.URL), WithRetryPolicy(retry),
+		WithMiddleware(trace("outer"), RequestIDMiddleware(func() string { return "req-1" })))
+	require.NoError(t, err)
+	client.Use(trace("inner"), audit)
+
+	_, _, err = client.Node.Get("node-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"outer before GET " + baseNodeUrl + "node-1/",
+		"inner before GET " + baseNodeUrl + "node-1/",
+		"inner after node",
+		"outer after node",
+	}, order)
+	// Retries of the call keep its request id
+	assert.Equal(t, []string{"req-1", "req-1"}, requestIds)
+
+	// Raw requests pass through the chain too
+	order = nil
+	req, err := http.NewRequest("POST", "/upload/file.iso", bytes.NewBufferString("data"))
+	require.NoError(t, err)
+	res, err := client.Execute(req)
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, []string{"outer before POST " + server.URL + "/upload/file.iso", "inner before POST " + server.URL + "/upload/file.iso"}, order)
+	assert.Equal(t, "req-1", requestIds[2])
+	return
+}
+
+func Test_DumpOnErrorMiddleware(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"errors": [{"detail": "wrong memory count"}]}`))
+	}))
+	defer server.Close()
+	logger := &recordingLogger{}
+	client, err := NewClientWithOptions(WithBaseURL(server.URL), WithToken("secret-token"),
+		WithMiddleware(RequestIDMiddleware(nil), DumpOnErrorMiddleware(logger)))
+	require.NoError(t, err)
+
+	_, err = client.ExecuteRequest("PUT", baseDomainUrl+"domain-1/", []byte(`{"memory_count": -1}`), nil)
+	require.True(t, IsBadRequest(err))
+	dumps := logger.find("veil call failed")
+	require.Len(t, dumps, 1)
+	fields := dumps[0].fields
+	assert.Equal(t, "PUT", fields["method"])
+	assert.Equal(t, `{"memory_count": -1}`, fields["request_body"])
+	assert.Equal(t, 400, fields["status"])
+	assert.Equal(t, `{"errors": [{"detail": "wrong memory count"}]}`, fields["response_body"])
+	assert.Len(t, fields["request_headers"].(http.Header).Get(RequestIDHeader), 36)
+	return
+}
+
+func Test_DumpOnErrorRedactsSecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"errors": [{"detail": "wrong credentials"}], "token": "leaked-token", "jwt": "leaked-jwt"}`))
+	}))
+	defer server.Close()
+	out := &bytes.Buffer{}
+	client, err := NewClientWithOptions(WithBaseURL(server.URL),
+		WithMiddleware(RequestIDMiddleware(nil), DumpOnErrorMiddleware(NewStdLogger(log.New(out, "", 0), LevelDebug))))
+	require.NoError(t, err)
+
+	_, err = client.Login(context.Background(), LoginCredentials{Username: "bot", Password: "pa$$w0rd"})
+	require.True(t, IsBadRequest(err))
+	assert.Contains(t, out.String(), "veil call failed")
+	assert.Contains(t, out.String(), "bot")
+	assert.Contains(t, out.String(), "wrong credentials")
+	for _, secret := range []string{"pa$$w0rd", "leaked-token", "leaked-jwt"} {
+		assert.NotContains(t, out.String(), secret)
+	}
+
+	assert.Equal(t, `{"memory_count": -1}`, string(RedactBody([]byte(`{"memory_count": -1}`))))
+	assert.Equal(t, `{"users":[{"Password":"[REDACTED]","username":"u"}]}`,
+		string(RedactBody([]byte(`{"users": [{"username": "u", "Password": "x"}]}`))))
+	assert.Equal(t, "not json password=x", string(RedactBody([]byte("not json password=x"))))
+	return
+}
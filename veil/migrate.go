@@ -0,0 +1,84 @@
+package veil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type MigrationModeStruct struct {
+	Live, Offline string
+}
+
+// MigrationMode live migration moves running domain without stopping it, offline migration moves powered off domain
+var MigrationMode = MigrationModeStruct{
+	Live:    "live",
+	Offline: "offline",
+}
+
+// DomainMigrateConfig parameters of DomainService.Migrate, zero fields are not sent
+type DomainMigrateConfig struct {
+	// Mode MigrationMode.Live by default
+	Mode string `json:"mode,omitempty"`
+	// Node target node, VeiL selects the node itself if empty
+	Node string `json:"target_node,omitempty"`
+	// DataPool moves vdisks of the domain to the datapool, vdisks stay in place if empty
+	DataPool string `json:"datapool,omitempty"`
+	// Bandwidth limit of migration traffic in MiB/s
+	Bandwidth int `json:"bandwidth,omitempty"`
+	// Timeout in seconds after which VeiL aborts the migration
+	Timeout int `json:"timeout,omitempty"`
+}
+
+// Validate checks the config without sending it, errors wrap ErrInvalidConfig
+func (config DomainMigrateConfig) Validate() error {
+	if config.Mode != "" && config.Mode != MigrationMode.Live && config.Mode != MigrationMode.Offline {
+		return fmt.Errorf("%w: unknown migration mode %q", ErrInvalidConfig, config.Mode)
+	}
+	if config.Bandwidth < 0 {
+		return fmt.Errorf("%w: negative migration bandwidth %d", ErrInvalidConfig, config.Bandwidth)
+	}
+	if config.Timeout < 0 {
+		return fmt.Errorf("%w: negative migration timeout %d", ErrInvalidConfig, config.Timeout)
+	}
+	return nil
+}
+
+// Migrate moves the domain to another node and waits for the task
+func (d *DomainService) Migrate(Id string, config DomainMigrateConfig) (*DomainObject, *http.Response, error) {
+	return d.MigrateContext(context.Background(), Id, config)
+}
+
+// MigrateContext moves the domain to another node and waits for the task
+func (d *DomainService) MigrateContext(ctx context.Context, Id string, config DomainMigrateConfig) (*DomainObject, *http.Response, error) {
+	ctx, finish := startOperation(ctx, d.client, "DomainService.Migrate", Id)
+	op, res, err := d.StartMigrate(ctx, Id, config)
+	if err != nil {
+		finish(err)
+		return new(DomainObject), res, err
+	}
+	domain, err := op.Wait(ctx)
+	finish(err)
+	return domain, op.response(res), err
+}
+
+// StartMigrate starts domain migration and returns without waiting for the task. The result is the domain
+// on its new node, e.g. a node is drained by starting migration of its domains and WaitAll of the operations
+func (d *DomainService) StartMigrate(ctx context.Context, Id string, config DomainMigrateConfig) (*DomainOperation, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.StartMigrate")
+	if err := config.Validate(); err != nil {
+		return nil, nil, err
+	}
+	if config.Mode == "" {
+		config.Mode = MigrationMode.Live
+	}
+	b, _ := json.Marshal(config)
+	asyncResp := new(AsyncEntityResponse)
+	res, err := d.client.ExecuteRequestContext(ctx, "POST", fmt.Sprint(baseDomainUrl, Id, "/migrate/?async=1"), b, asyncResp)
+	if err != nil {
+		return nil, res, err
+	}
+	op, err := newDomainOperation(d.client, OperationState{OperationKind.DomainMigrate, asyncResp.Task.Id, Id})
+	return op, res, err
+}
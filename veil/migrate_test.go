@@ -0,0 +1,108 @@
+package veil
+
+import (
+	"context"
+	"github.com/jsc-masshtab/veil-api-client-go/veil/veiltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func Test_DomainMigrate(t *testing.T) {
+	server, client := newTestServerClient(t)
+	target := server.Add(veiltest.Nodes, veiltest.Object{"verbose_name": "node-2", "management_ip": "127.0.0.2"})
+	targetId := target["id"].(string)
+	domain, _, err := client.Domain.MultiCreate(DomainMultiCreateConfig{
+		DomainCreateConfig: DomainCreateConfig{VerboseName: "vm"},
+		StartOn:            true,
+	})
+	require.NoError(t, err)
+
+	migrated, _, err := client.Domain.Migrate(domain.Id, DomainMigrateConfig{Bandwidth: 100, Timeout: 600})
+	require.NoError(t, err)
+	assert.Equal(t, targetId, migrated.Node.Id)
+	for _, request := range server.Requests() {
+		if request.Method == "POST" && strings.HasSuffix(request.Path, "/migrate/") {
+			assert.JSONEq(t, `{"mode": "live", "bandwidth": 100, "timeout": 600}`, string(request.Body))
+		}
+	}
+
+	_, _, err = client.Domain.Migrate(domain.Id, DomainMigrateConfig{Mode: MigrationMode.Offline, Node: veiltest.NodeId})
+	assert.True(t, IsBadRequest(err))
+	_, _, err = client.Domain.Shutdown(domain, false)
+	require.NoError(t, err)
+	migrated, _, err = client.Domain.Migrate(domain.Id, DomainMigrateConfig{Mode: MigrationMode.Offline,
+		Node: veiltest.NodeId, DataPool: veiltest.DataPoolId})
+	require.NoError(t, err)
+	assert.Equal(t, veiltest.NodeId, migrated.Node.Id)
+	_, _, err = client.Domain.Migrate(domain.Id, DomainMigrateConfig{Mode: MigrationMode.Offline, Node: veiltest.NodeId})
+	assert.True(t, IsBadRequest(err))
+
+	count := len(server.Requests())
+	_, _, err = client.Domain.Migrate(domain.Id, DomainMigrateConfig{Mode: "cold"})
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	_, _, err = client.Domain.Migrate(domain.Id, DomainMigrateConfig{Bandwidth: -1})
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	assert.Equal(t, count, len(server.Requests()))
+	return
+}
+
+func Test_DomainMigrateEvacuation(t *testing.T) {
+	server, client := newTestServerClient(t)
+	server.Add(veiltest.Nodes, veiltest.Object{"verbose_name": "node-2"})
+	ctx := context.Background()
+	for _, name := range []string{"vm-1", "vm-2", "vm-3"} {
+		_, _, err := client.Domain.MultiCreate(DomainMultiCreateConfig{
+			DomainCreateConfig: DomainCreateConfig{VerboseName: name},
+			StartOn:            true,
+		})
+		require.NoError(t, err)
+	}
+	server.Inject(veiltest.Fault{Path: "/api/domains/*/migrate/", TaskError: "not enough memory", Times: 1})
+
+	domains, err := client.Domain.ListAll(ctx, DomainFilter{Node: veiltest.NodeId})
+	require.NoError(t, err)
+	require.Len(t, domains, 3)
+	var ops []*Operation
+	var migrations []*DomainOperation
+	for _, domain := range domains {
+		op, _, err := client.Domain.StartMigrate(ctx, domain.Id, DomainMigrateConfig{})
+		require.NoError(t, err)
+		assert.Equal(t, OperationKind.DomainMigrate, op.State().Kind)
+		ops = append(ops, op.Operation)
+		migrations = append(migrations, op)
+	}
+	err = WaitAll(ctx, ops...)
+	assert.True(t, IsTaskFailed(err))
+	_, err = migrations[0].Result()
+	assert.True(t, IsTaskFailed(err))
+	for _, op := range migrations[1:] {
+		domain, err := op.Result()
+		require.NoError(t, err)
+		assert.NotEqual(t, veiltest.NodeId, domain.Node.Id)
+	}
+	return
+}
+
+func Test_DomainMigrateLive(t *testing.T) {
+	client := liveClient(t)
+	liveEndpoints(t, client, "/api/domains/{id}/migrate/")
+	domain := liveDomain(t, client)
+	nodes, _, err := client.Node.List()
+	require.Nil(t, err)
+	target := ""
+	for _, node := range nodes.Results {
+		if node.Id != domain.Node.Id {
+			target = node.Id
+		}
+	}
+	if target == "" {
+		t.Skip("migration needs the second node")
+	}
+
+	migrated, _, err := client.Domain.Migrate(domain.Id, DomainMigrateConfig{Mode: MigrationMode.Offline, Node: target})
+	require.Nil(t, err)
+	assert.Equal(t, target, migrated.Node.Id)
+	return
+}
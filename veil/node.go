@@ -1,6 +1,7 @@
 package veil
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -52,34 +53,109 @@ type NodesResponse struct {
 }
 
 func (d *NodeService) List() (*NodesResponse, *http.Response, error) {
+	return d.ListContext(context.Background())
+}
+
+func (d *NodeService) ListContext(ctx context.Context) (*NodesResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "NodeService.List")
 
 	response := new(NodesResponse)
 
-	res, err := d.client.ExecuteRequest("GET", baseNodeUrl, []byte{}, response)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", baseNodeUrl, []byte{}, response)
 
 	return response, res, err
 }
 
 func (d *NodeService) ListParams(queryParams map[string]string) (*NodesResponse, *http.Response, error) {
-	listUrl := baseNodeUrl
-	if len(queryParams) != 0 {
-		params := url.Values{}
-		for k, v := range queryParams {
-			params.Add(k, v)
-		}
-		listUrl += "?"
-		listUrl += params.Encode()
-	}
+	return d.ListParamsContext(context.Background(), queryParams)
+}
+
+func (d *NodeService) ListParamsContext(ctx context.Context, queryParams map[string]string) (*NodesResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "NodeService.ListParams")
+	response := new(NodesResponse)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", buildListUrl(baseNodeUrl, Params(queryParams)), []byte{}, response)
+	return response, res, err
+}
+
+// NodeFilter typed filter of nodes, zero fields are not sent
+type NodeFilter struct {
+	ListOptions
+	Status      []string
+	VerboseName string
+	Cluster     string
+	Datacenter  string
+	Tags        []string
+}
+
+// Values encodes the filter to query parameters
+func (f NodeFilter) Values() url.Values {
+	params := f.ListOptions.Values()
+	addStrings(params, "status", f.Status)
+	setString(params, "verbose_name", f.VerboseName)
+	setString(params, "cluster", f.Cluster)
+	setString(params, "datacenter", f.Datacenter)
+	addStrings(params, "tags", f.Tags)
+	return params
+}
+
+// ListFiltered returns the first page of nodes matching filter
+func (d *NodeService) ListFiltered(filter NodeFilter) (*NodesResponse, *http.Response, error) {
+	return d.ListFilteredContext(context.Background(), filter)
+}
+
+// ListFilteredContext returns the first page of nodes matching filter
+func (d *NodeService) ListFilteredContext(ctx context.Context, filter NodeFilter) (*NodesResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "NodeService.ListFiltered")
 	response := new(NodesResponse)
-	res, err := d.client.ExecuteRequest("GET", listUrl, []byte{}, response)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", buildListUrl(baseNodeUrl, filter), []byte{}, response)
 	return response, res, err
 }
 
+// NodeIterator streams nodes page by page
+type NodeIterator struct {
+	*Iterator
+	item NodeObjectsList
+}
+
+// Next advances to the next node
+func (it *NodeIterator) Next() bool {
+	it.item = NodeObjectsList{}
+	return it.Iterator.Next() && it.decode(&it.item)
+}
+
+// Item returns the current node
+func (it *NodeIterator) Item() NodeObjectsList {
+	return it.item
+}
+
+// Iter returns an iterator over all nodes matching filter, filter and opts may be nil
+func (d *NodeService) Iter(ctx context.Context, filter ListFilter, opts *PageOptions) *NodeIterator {
+	ctx = withOperation(ctx, "NodeService.Iter")
+	return &NodeIterator{Iterator: newIterator(ctx, d.client, baseNodeUrl, filter, opts)}
+}
+
+// ListAll returns all nodes matching filter following the pages until exhausted
+func (d *NodeService) ListAll(ctx context.Context, filter ListFilter) ([]NodeObjectsList, error) {
+	ctx = withOperation(ctx, "NodeService.ListAll")
+	it := d.Iter(ctx, filter, nil)
+	defer it.Close()
+	var items []NodeObjectsList
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
+
 func (d *NodeService) Get(Id string) (*NodeObject, *http.Response, error) {
+	return d.GetContext(context.Background(), Id)
+}
+
+func (d *NodeService) GetContext(ctx context.Context, Id string) (*NodeObject, *http.Response, error) {
+	ctx = withOperation(ctx, "NodeService.Get")
 
 	entity := new(NodeObject)
 
-	res, err := d.client.ExecuteRequest("GET", fmt.Sprint(baseNodeUrl, Id, "/"), []byte{}, entity)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", fmt.Sprint(baseNodeUrl, Id, "/"), []byte{}, entity)
 
 	return entity, res, err
 }
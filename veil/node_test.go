@@ -6,7 +6,7 @@ import (
 )
 
 func Test_NodeListGet(t *testing.T) {
-	client := NewClient("", "", false)
+	_, client := newTestServerClient(t)
 	response, _, err := client.Node.List()
 	assert.Nil(t, err)
 	for _, v := range response.Results {
@@ -0,0 +1,157 @@
+package veil
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Outcomes of TaskService.Wait reported to TaskObserver
+const (
+	TaskOutcomeSuccess  = "success"
+	TaskOutcomeFailed   = "failed"
+	TaskOutcomeTimeout  = "timeout"
+	TaskOutcomeCanceled = "canceled"
+	TaskOutcomeError    = "error"
+)
+
+// TaskObserver is notified about task waits of TaskService.Wait, e.g. for metrics or tracing
+type TaskObserver interface {
+	// TaskWaitStarted is called before the first poll, returned context is used for the rest of the wait
+	TaskWaitStarted(ctx context.Context, taskId string) context.Context
+	// TaskPolled is called after every successful poll with the context returned by TaskWaitStarted
+	TaskPolled(ctx context.Context, task *TaskObject)
+	// TaskWaitFinished is called once with the context returned by TaskWaitStarted, task may be nil if the first poll failed
+	TaskWaitFinished(ctx context.Context, taskId string, task *TaskObject, elapsed time.Duration, err error)
+}
+
+// OperationObserver is notified about service operations consisting of several requests,
+// e.g. MultiCreate posts the request, waits the task and fetches the domain
+type OperationObserver interface {
+	// OperationStarted is called before the first request, returned context is used for requests of the operation
+	// and finish is called once the operation is done
+	OperationStarted(ctx context.Context, name string, entityId string) (context.Context, func(err error))
+}
+
+// startOperation notifies operation observers of c, finish must be called once the operation is done
+func startOperation(ctx context.Context, c Client, name string, entityId string) (context.Context, func(err error)) {
+	o, ok := c.(interface{ operationObservers() []OperationObserver })
+	if !ok {
+		return ctx, func(err error) {}
+	}
+	var finishers []func(err error)
+	for _, observer := range o.operationObservers() {
+		var finish func(err error)
+		ctx, finish = observer.OperationStarted(ctx, name, entityId)
+		finishers = append(finishers, finish)
+	}
+	return ctx, func(err error) {
+		for i := len(finishers) - 1; i >= 0; i-- {
+			finishers[i](err)
+		}
+	}
+}
+
+type operationNameKey struct{}
+
+// withOperation names API calls executed with ctx after the service method, e.g. DomainService.Get.
+// The name of the method called by the user is kept if it calls other service methods
+func withOperation(ctx context.Context, name string) context.Context {
+	if OperationName(ctx) != "" {
+		return ctx
+	}
+	return context.WithValue(ctx, operationNameKey{}, name)
+}
+
+// OperationName returns the name of the service method which executes API calls with ctx, e.g. DomainService.Get.
+// It is empty for calls made by ExecuteRequest directly
+func OperationName(ctx context.Context) string {
+	name, _ := ctx.Value(operationNameKey{}).(string)
+	return name
+}
+
+func (client *WebClient) operationObservers() []OperationObserver {
+	return client.OperationObservers
+}
+
+// TaskWaitOutcome classifies the error returned from TaskService.Wait
+func TaskWaitOutcome(err error) string {
+	switch {
+	case err == nil:
+		return TaskOutcomeSuccess
+	case IsTaskFailed(err):
+		return TaskOutcomeFailed
+	case errors.Is(err, ErrTaskTimeout), errors.Is(err, context.DeadlineExceeded):
+		return TaskOutcomeTimeout
+	case errors.Is(err, context.Canceled):
+		return TaskOutcomeCanceled
+	default:
+		return TaskOutcomeError
+	}
+}
+
+// observersOf returns task observers of c, mocks have none
+func observersOf(c Client) []TaskObserver {
+	if o, ok := c.(interface{ taskObservers() []TaskObserver }); ok {
+		return o.taskObservers()
+	}
+	return nil
+}
+
+func (client *WebClient) taskObservers() []TaskObserver {
+	return client.TaskObservers
+}
+
+// endpointCollections are paths of VeiL collections, the segment following one of them is an entity id
+// or a token (e.g. of upload_url) unless it is a collection action
+var endpointCollections = map[string]bool{
+	baseClusterUrl:                    true,
+	baseDataCenterUrl:                 true,
+	baseDataPoolUrl:                   true,
+	baseDomainUrl:                     true,
+	baseDomainUrl + "{id}/snapshots/": true,
+	baseEventUrl:                      true,
+	baseIsoUrl:                        true,
+	baseLibraryUrl:                    true,
+	baseNodeUrl:                       true,
+	baseTaskUrl:                       true,
+	baseUserUrl:                       true,
+	baseVdiskUrl:                      true,
+	baseVMachineInfUrl:                true,
+	baseVnetUrl:                       true,
+	"/upload/iso/":                    true,
+	"/upload/library/":                true,
+	"/download/iso/":                  true,
+	"/download/library/":              true,
+}
+
+// collectionActions are endpoints of collections which are not entities, e.g. /api/domains/multi-create-domain/
+var collectionActions = map[string]bool{
+	"multi-create-domain": true,
+}
+
+// EndpointTemplate replaces ids and tokens in path of API url with {id} and drops query and host,
+// e.g. /api/domains/{id}/start/ for /api/domains/7e5a.../start/?async=1. Segments following known collections
+// and UUID or numeric segments of other paths are replaced, so label cardinality of metrics stays low
+func EndpointTemplate(rawUrl string) string {
+	path := rawUrl
+	if u, err := url.Parse(rawUrl); err == nil {
+		path = u.Path
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if segment == "" {
+			continue
+		}
+		collection := endpointCollections[strings.Join(segments[:i], "/")+"/"] && !collectionActions[segment]
+		if collection || isUUID(strings.ToLower(segment)) {
+			segments[i] = "{id}"
+		} else if _, err := strconv.ParseInt(segment, 10, 64); err == nil {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,96 @@
+package veil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_EndpointTemplate(t *testing.T) {
+	id := "7e5a1c3b-2b1e-4f7a-9d2c-5b8f0a6e4d21"
+	assert.Equal(t, "/api/domains/{id}/start/", EndpointTemplate("/api/domains/"+id+"/start/?async=1"))
+	assert.Equal(t, "/api/events/{id}/", EndpointTemplate("http://veil/api/events/42/"))
+	assert.Equal(t, "/api/domains/", EndpointTemplate(baseDomainUrl))
+	assert.Equal(t, "/api/domains/multi-create-domain/", EndpointTemplate(baseDomainUrl+"multi-create-domain/?async=1"))
+	assert.Equal(t, "/api/domains/{id}/snapshots/{id}/", EndpointTemplate("/api/domains/"+id+"/snapshots/base-1/"))
+	assert.Equal(t, "/api/users/{id}/", EndpointTemplate("/api/users/admin/"))
+	// Tokens of upload_url are not ids, but they are replaced too
+	assert.Equal(t, "/upload/iso/{id}/", EndpointTemplate("https://veil/upload/iso/eyJ0eXAiOiJKV1Qi.f3a9/?chunk=1"))
+	return
+}
+
+func Test_TaskWaitOutcome(t *testing.T) {
+	assert.Equal(t, TaskOutcomeSuccess, TaskWaitOutcome(nil))
+	assert.Equal(t, TaskOutcomeFailed, TaskWaitOutcome(&TaskFailedError{TaskId: "task-1"}))
+	assert.Equal(t, TaskOutcomeTimeout, TaskWaitOutcome(fmt.Errorf("%w: task-1", ErrTaskTimeout)))
+	assert.Equal(t, TaskOutcomeCanceled, TaskWaitOutcome(context.Canceled))
+	assert.Equal(t, TaskOutcomeError, TaskWaitOutcome(errors.New("connection refused")))
+	return
+}
+
+type testTaskObserver struct {
+	events []string
+}
+
+type observerKey struct{}
+
+func (o *testTaskObserver) TaskWaitStarted(ctx context.Context, taskId string) context.Context {
+	o.events = append(o.events, "started "+taskId)
+	return context.WithValue(ctx, observerKey{}, taskId)
+}
+
+func (o *testTaskObserver) TaskPolled(ctx context.Context, task *TaskObject) {
+	o.events = append(o.events, fmt.Sprintf("polled %s %d", ctx.Value(observerKey{}), task.Progress))
+}
+
+func (o *testTaskObserver) TaskWaitFinished(ctx context.Context, taskId string, task *TaskObject, elapsed time.Duration, err error) {
+	o.events = append(o.events, "finished "+taskId+" "+TaskWaitOutcome(err))
+}
+
+func Test_TaskObserver(t *testing.T) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		progress := atomic.AddInt32(&polls, 1) * 50
+		status := TaskStatus.InProgress
+		if progress >= 100 {
+			status = TaskStatus.Success
+		}
+		fmt.Fprintf(w, `{"id": "task-1", "status": "%s", "progress": %d}`, status, progress)
+	}))
+	defer server.Close()
+	observer := &testTaskObserver{}
+	client, err := NewClientWithOptions(WithBaseURL(server.URL), WithTaskObserver(observer))
+	require.NoError(t, err)
+
+	_, err = client.Task.Wait(context.Background(), "task-1", &TaskWaitOptions{PollInterval: time.Millisecond})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"started task-1", "polled task-1 50", "polled task-1 100", "finished task-1 success"}, observer.events)
+	return
+}
+
+func Test_OperationName(t *testing.T) {
+	server, client := newTestServerClient(t)
+	var names []string
+	client.Use(func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) error {
+			names = append(names, OperationName(ctx))
+			return next(ctx, call)
+		}
+	})
+	domain, _, err := client.Domain.Create(DomainCreateConfig{VerboseName: "vm"})
+	require.NoError(t, err)
+	_, err = client.Domain.ListVdisks(context.Background(), domain.Id)
+	require.NoError(t, err)
+	_, err = client.ExecuteRequest("GET", baseDomainUrl, []byte{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DomainService.Create", "DomainService.ListVdisks", ""}, names)
+	assert.Equal(t, 3, len(server.Requests()))
+	return
+}
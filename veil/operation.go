@@ -0,0 +1,333 @@
+package veil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// ErrOperationInProgress is returned by Result of an operation which task is not finished yet
+var ErrOperationInProgress = errors.New("operation is in progress")
+
+type OperationKindStruct struct {
+	DomainClone, DomainMultiCreate, VdiskCreate, LibraryImport       string
+	SnapshotCreate, SnapshotRevert, SnapshotRemove, VdiskConsolidate string
+	DomainMigrate, VdiskCreateAttach                                 string
+}
+
+// OperationKind identifies the call which started an operation, it defines how the result is loaded
+var OperationKind = OperationKindStruct{
+	DomainClone:       "domain_clone",
+	DomainMultiCreate: "domain_multi_create",
+	VdiskCreate:       "vdisk_create",
+	LibraryImport:     "library_import",
+	SnapshotCreate:    "snapshot_create",
+	SnapshotRevert:    "snapshot_revert",
+	SnapshotRemove:    "snapshot_remove",
+	VdiskConsolidate:  "vdisk_consolidate",
+	DomainMigrate:     "domain_migrate",
+	VdiskCreateAttach: "vdisk_create_attach",
+}
+
+// OperationState is enough to resume waiting for an operation after restart, it can be stored as JSON
+type OperationState struct {
+	Kind     string `json:"kind"`
+	TaskId   string `json:"task_id"`
+	EntityId string `json:"entity_id,omitempty"`
+}
+
+// Operation handle of a long-running VeiL task started asynchronously
+type Operation struct {
+	client Client
+	state  OperationState
+	// fetch loads a new copy of the result of successfully finished task
+	fetch func(ctx context.Context) (interface{}, *http.Response, error)
+
+	mu     sync.Mutex
+	task   *TaskObject
+	res    *http.Response
+	result interface{}
+	done   bool
+	err    error
+}
+
+func newOperation(client Client, state OperationState, fetch func(ctx context.Context) (interface{}, *http.Response, error)) *Operation {
+	return &Operation{client: client, state: state, fetch: fetch}
+}
+
+// TaskId id of VeiL task performing the operation
+func (o *Operation) TaskId() string {
+	return o.state.TaskId
+}
+
+// EntityId id of the entity returned by VeiL when the operation was started
+func (o *Operation) EntityId() string {
+	return o.state.EntityId
+}
+
+// State returns data for resuming the operation later
+func (o *Operation) State() OperationState {
+	return o.state
+}
+
+// Task returns the last polled task state, nil if the task was not polled yet
+func (o *Operation) Task() *TaskObject {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.task
+}
+
+// Done reports whether the operation is finished and its result is loaded
+func (o *Operation) Done() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.done
+}
+
+// Err returns the error of finished operation, ErrOperationInProgress if it is not finished
+func (o *Operation) Err() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if !o.done {
+		return ErrOperationInProgress
+	}
+	return o.err
+}
+
+// Poll checks the task state once and loads the result if the task is finished
+func (o *Operation) Poll(ctx context.Context) (bool, error) {
+	if o.Done() {
+		return true, o.Err()
+	}
+	task, _, err := (&TaskService{o.client}).GetContext(ctx, o.state.TaskId)
+	if err != nil {
+		return false, err
+	}
+	o.setTask(task)
+	if task.Status == TaskStatus.InProgress {
+		return false, nil
+	}
+	return true, o.complete(ctx, task)
+}
+
+// Wait blocks until the task is finished and loads the result, see TaskService.Wait for errors
+func (o *Operation) Wait(ctx context.Context) error {
+	if o.Done() {
+		return o.Err()
+	}
+	task, err := (&TaskService{o.client}).Wait(ctx, o.state.TaskId, &TaskWaitOptions{OnProgress: o.setTask})
+	if err != nil && !IsTaskFailed(err) {
+		return err
+	}
+	return o.complete(ctx, task)
+}
+
+// Cancel asks VeiL to cancel the task, the operation then finishes with TaskFailedError
+func (o *Operation) Cancel(ctx context.Context) error {
+	_, _, err := (&TaskService{o.client}).CancelContext(ctx, o.state.TaskId)
+	return err
+}
+
+// response returns the response of result loading or fallback if the result was not loaded
+func (o *Operation) response(fallback *http.Response) *http.Response {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.res != nil {
+		return o.res
+	}
+	return fallback
+}
+
+// value returns the result loaded by the operation, nil if it is not loaded
+func (o *Operation) value() interface{} {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.result
+}
+
+func (o *Operation) setTask(task *TaskObject) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.task = task
+}
+
+// complete stores the result of finished task, failed result loading may be repeated. Every call loads
+// its own copy of the result, the first stored one is kept if Wait and Poll finish concurrently
+func (o *Operation) complete(ctx context.Context, task *TaskObject) error {
+	o.setTask(task)
+	var err error
+	var res *http.Response
+	var result interface{}
+	if task.Status == TaskStatus.Success {
+		result, res, err = o.fetch(ctx)
+		if err != nil {
+			return err
+		}
+	} else {
+		err = newTaskFailedError(task)
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if !o.done {
+		o.done, o.err, o.res, o.result = true, err, res, result
+	}
+	return o.err
+}
+
+// WaitAll waits all operations concurrently and returns the first error, errors of
+// every operation are available through its Err method
+func WaitAll(ctx context.Context, ops ...*Operation) error {
+	errs := make([]error, len(ops))
+	var wg sync.WaitGroup
+	for i, op := range ops {
+		wg.Add(1)
+		go func(i int, op *Operation) {
+			defer wg.Done()
+			errs[i] = op.Wait(ctx)
+		}(i, op)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DomainOperation operation which result is a domain
+type DomainOperation struct {
+	*Operation
+}
+
+// Wait blocks until the task is finished and returns the domain, empty one if it is not loaded
+func (o *DomainOperation) Wait(ctx context.Context) (*DomainObject, error) {
+	err := o.Operation.Wait(ctx)
+	return o.domain(), err
+}
+
+// Result returns the domain of finished operation or ErrOperationInProgress
+func (o *DomainOperation) Result() (*DomainObject, error) {
+	if err := o.Err(); err != nil {
+		return nil, err
+	}
+	return o.domain(), nil
+}
+
+func (o *DomainOperation) domain() *DomainObject {
+	if domain, ok := o.value().(*DomainObject); ok {
+		return domain
+	}
+	return new(DomainObject)
+}
+
+// VdiskOperation operation which result is a virtual disk
+type VdiskOperation struct {
+	*Operation
+}
+
+// Wait blocks until the task is finished and returns the virtual disk, empty one if it is not loaded
+func (o *VdiskOperation) Wait(ctx context.Context) (*VdiskObject, error) {
+	err := o.Operation.Wait(ctx)
+	return o.vdisk(), err
+}
+
+// Result returns the virtual disk of finished operation or ErrOperationInProgress
+func (o *VdiskOperation) Result() (*VdiskObject, error) {
+	if err := o.Err(); err != nil {
+		return nil, err
+	}
+	return o.vdisk(), nil
+}
+
+func (o *VdiskOperation) vdisk() *VdiskObject {
+	if vdisk, ok := o.value().(*VdiskObject); ok {
+		return vdisk
+	}
+	return new(VdiskObject)
+}
+
+// SnapshotOperation operation which result is a domain snapshot
+type SnapshotOperation struct {
+	*Operation
+}
+
+// Wait blocks until the task is finished and returns the snapshot, empty one if it is not loaded
+func (o *SnapshotOperation) Wait(ctx context.Context) (*DomainSnapshot, error) {
+	err := o.Operation.Wait(ctx)
+	return o.snapshot(), err
+}
+
+// Result returns the snapshot of finished operation or ErrOperationInProgress
+func (o *SnapshotOperation) Result() (*DomainSnapshot, error) {
+	if err := o.Err(); err != nil {
+		return nil, err
+	}
+	return o.snapshot(), nil
+}
+
+func (o *SnapshotOperation) snapshot() *DomainSnapshot {
+	if snapshot, ok := o.value().(*DomainSnapshot); ok {
+		return snapshot
+	}
+	return new(DomainSnapshot)
+}
+
+func newDomainOperation(client Client, state OperationState) (*DomainOperation, error) {
+	var fetch func(ctx context.Context) (interface{}, *http.Response, error)
+	switch state.Kind {
+	case OperationKind.DomainClone:
+		fetch = func(ctx context.Context) (interface{}, *http.Response, error) {
+			domain := new(DomainObject)
+			res, err := (&TaskService{client}).ResponseContext(ctx, state.TaskId, domain)
+			return domain, res, err
+		}
+	case OperationKind.DomainMultiCreate, OperationKind.SnapshotRevert, OperationKind.SnapshotRemove,
+		OperationKind.DomainMigrate:
+		fetch = func(ctx context.Context) (interface{}, *http.Response, error) {
+			ctx = withOperation(ctx, "DomainService.Get")
+			domain := new(DomainObject)
+			res, err := client.ExecuteRequestContext(ctx, "GET", fmt.Sprint(baseDomainUrl, state.EntityId, "/"), []byte{}, domain)
+			return domain, res, err
+		}
+	default:
+		return nil, fmt.Errorf("operation kind %q does not return a domain", state.Kind)
+	}
+	return &DomainOperation{newOperation(client, state, fetch)}, nil
+}
+
+func newVdiskOperation(client Client, state OperationState) (*VdiskOperation, error) {
+	var fetch func(ctx context.Context) (interface{}, *http.Response, error)
+	switch state.Kind {
+	case OperationKind.LibraryImport:
+		fetch = func(ctx context.Context) (interface{}, *http.Response, error) {
+			vdisk := new(VdiskObject)
+			res, err := (&TaskService{client}).ResponseContext(ctx, state.TaskId, vdisk)
+			return vdisk, res, err
+		}
+	case OperationKind.VdiskCreate, OperationKind.VdiskConsolidate, OperationKind.VdiskCreateAttach:
+		fetch = func(ctx context.Context) (interface{}, *http.Response, error) {
+			ctx = withOperation(ctx, "VdiskService.Get")
+			vdisk := new(VdiskObject)
+			res, err := client.ExecuteRequestContext(ctx, "GET", fmt.Sprint(baseVdiskUrl, state.EntityId, "/"), []byte{}, vdisk)
+			return vdisk, res, err
+		}
+	default:
+		return nil, fmt.Errorf("operation kind %q does not return a vdisk", state.Kind)
+	}
+	return &VdiskOperation{newOperation(client, state, fetch)}, nil
+}
+
+func newSnapshotOperation(client Client, state OperationState) (*SnapshotOperation, error) {
+	if state.Kind != OperationKind.SnapshotCreate {
+		return nil, fmt.Errorf("operation kind %q does not return a snapshot", state.Kind)
+	}
+	fetch := func(ctx context.Context) (interface{}, *http.Response, error) {
+		snapshot := new(DomainSnapshot)
+		res, err := (&TaskService{client}).ResponseContext(ctx, state.TaskId, snapshot)
+		return snapshot, res, err
+	}
+	return &SnapshotOperation{newOperation(client, state, fetch)}, nil
+}
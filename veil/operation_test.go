@@ -0,0 +1,107 @@
+package veil
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func Test_DomainCloneOperation(t *testing.T) {
+	var mu sync.Mutex
+	tasks := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/api/domains/template/clone/":
+			taskId := fmt.Sprint("task-", len(tasks))
+			tasks[taskId] = TaskStatus.InProgress
+			w.WriteHeader(http.StatusAccepted)
+			fmt.Fprintf(w, `{"_task": {"id": "%s", "status": "%s"}, "entity": "template"}`, taskId, TaskStatus.InProgress)
+		case "/api/tasks/task-0/", "/api/tasks/task-1/":
+			taskId := r.URL.Path[len(baseTaskUrl) : len(r.URL.Path)-1]
+			status := tasks[taskId]
+			if status == TaskStatus.InProgress && taskId == "task-0" {
+				tasks[taskId] = TaskStatus.Success
+			}
+			fmt.Fprintf(w, `{"id": "%s", "status": "%s"}`, taskId, status)
+		case "/api/tasks/task-0/response/":
+			fmt.Fprint(w, `{"id": "clone", "verbose_name": "clone"}`)
+		case "/api/tasks/task-1/cancel/":
+			tasks["task-1"] = TaskStatus.Canceled
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "token", false)
+	ctx := context.Background()
+
+	op, _, err := client.Domain.StartClone(ctx, "template", DomainCloneConfig{VerboseName: "clone"})
+	require.Nil(t, err)
+	assert.Equal(t, "task-0", op.TaskId())
+	assert.Equal(t, "template", op.EntityId())
+	_, err = op.Result()
+	assert.ErrorIs(t, err, ErrOperationInProgress)
+
+	done, err := op.Poll(ctx)
+	require.Nil(t, err)
+	assert.False(t, done)
+
+	canceled, _, err := client.Domain.StartClone(ctx, "template", DomainCloneConfig{VerboseName: "clone"})
+	require.Nil(t, err)
+	require.Nil(t, canceled.Cancel(ctx))
+
+	// Resumed handle behaves like the original one
+	resumed, err := client.Domain.ResumeOperation(op.State())
+	require.Nil(t, err)
+	assert.Error(t, WaitAll(ctx, resumed.Operation, canceled.Operation))
+
+	domain, err := resumed.Result()
+	require.Nil(t, err)
+	assert.Equal(t, "clone", domain.Id)
+	assert.True(t, IsTaskFailed(canceled.Err()))
+
+	_, err = client.Vdisk.ResumeOperation(op.State())
+	assert.Error(t, err)
+
+	return
+}
+
+func Test_DomainOperationConcurrentWait(t *testing.T) {
+	_, client := newTestServerClient(t)
+	ctx := context.Background()
+	op, _, err := client.Domain.StartMultiCreate(ctx, DomainMultiCreateConfig{DomainCreateConfig: DomainCreateConfig{VerboseName: "vm"}})
+	require.Nil(t, err)
+
+	// Every waiter gets the same domain, the result is loaded by each of them without sharing the object
+	domains := make([]*DomainObject, 4)
+	var wg sync.WaitGroup
+	for i := range domains {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				domain, err := op.Wait(ctx)
+				assert.Nil(t, err)
+				domains[i] = domain
+				return
+			}
+			for done := false; !done; {
+				done, _ = op.Poll(ctx)
+			}
+			domains[i], _ = op.Result()
+		}(i)
+	}
+	wg.Wait()
+	for _, domain := range domains {
+		assert.Same(t, domains[0], domain)
+		assert.Equal(t, "vm", domain.VerboseName)
+	}
+	return
+}
@@ -0,0 +1,372 @@
+package veil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DefaultAcceptLanguage language of API messages if not set by WithAcceptLanguage
+const DefaultAcceptLanguage = "en"
+
+// ClientOption configures a client created by NewClientWithOptions
+type ClientOption func(*clientOptions) error
+
+type clientOptions struct {
+	baseURL        string
+	endpoints      []string
+	failover       *FailoverOptions
+	token          string
+	tokenSource    TokenSource
+	credentials    *LoginCredentials
+	userAgent      string
+	acceptLanguage string
+	retryPolicy    *RetryPolicy
+	rateLimiter    *RateLimiter
+	logger         Logger
+	middleware     []Middleware
+	taskObservers  []TaskObserver
+	opObservers    []OperationObserver
+
+	timeout             time.Duration
+	dialTimeout         time.Duration
+	tlsHandshakeTimeout time.Duration
+
+	insecure     bool
+	rootCAs      *x509.CertPool
+	certificates []tls.Certificate
+
+	proxy       func(*http.Request) (*url.URL, error)
+	compression bool
+
+	maxIdleConns        int
+	maxIdleConnsPerHost int
+	maxConnsPerHost     int
+	idleConnTimeout     time.Duration
+
+	transport http.RoundTripper
+}
+
+// WithBaseURL address of VeiL controller, e.g. https://192.168.11.105
+func WithBaseURL(apiUrl string) ClientOption {
+	return func(o *clientOptions) error {
+		o.baseURL = apiUrl
+		return nil
+	}
+}
+
+// WithEndpoints controllers of HA installation, requests fail over to the next controller on connection errors
+// and 5xx responses. The first one is active initially
+func WithEndpoints(urls ...string) ClientOption {
+	return func(o *clientOptions) error {
+		o.endpoints = append(o.endpoints, urls...)
+		return nil
+	}
+}
+
+// WithFailoverOptions settings of health checks of WithEndpoints
+func WithFailoverOptions(opts FailoverOptions) ClientOption {
+	return func(o *clientOptions) error {
+		o.failover = &opts
+		return nil
+	}
+}
+
+// WithToken JWT token of API user
+func WithToken(token string) ClientOption {
+	return func(o *clientOptions) error {
+		o.token = token
+		return nil
+	}
+}
+
+// WithTokenSource source of JWT tokens instead of static token
+func WithTokenSource(source TokenSource) ClientOption {
+	return func(o *clientOptions) error {
+		o.tokenSource = source
+		return nil
+	}
+}
+
+// WithCredentials logs in with username and password, the token is renewed before expiration and after 401 response
+func WithCredentials(credentials LoginCredentials) ClientOption {
+	return func(o *clientOptions) error {
+		if credentials.Username == "" || credentials.Password == "" {
+			return errors.New("username and password are required")
+		}
+		o.credentials = &credentials
+		return nil
+	}
+}
+
+// WithUserAgent User-Agent header of every request
+func WithUserAgent(userAgent string) ClientOption {
+	return func(o *clientOptions) error {
+		o.userAgent = userAgent
+		return nil
+	}
+}
+
+// WithAcceptLanguage language of API messages and errors, e.g. "ru"
+func WithAcceptLanguage(lang string) ClientOption {
+	return func(o *clientOptions) error {
+		if lang == "" {
+			return errors.New("accept language is empty")
+		}
+		o.acceptLanguage = lang
+		return nil
+	}
+}
+
+// WithRetryPolicy retry policy of transient failures, nil disables retries
+func WithRetryPolicy(policy *RetryPolicy) ClientOption {
+	return func(o *clientOptions) error {
+		o.retryPolicy = policy
+		return nil
+	}
+}
+
+// WithLogger structured logger of requests and tasks
+func WithLogger(logger Logger) ClientOption {
+	return func(o *clientOptions) error {
+		o.logger = logger
+		return nil
+	}
+}
+
+// WithMiddleware appends middleware to the chain wrapping every call, the first one is the outermost
+func WithMiddleware(middleware ...Middleware) ClientOption {
+	return func(o *clientOptions) error {
+		o.middleware = append(o.middleware, middleware...)
+		return nil
+	}
+}
+
+// WithTaskObserver adds observer of task waits, e.g. metrics or tracing
+func WithTaskObserver(observers ...TaskObserver) ClientOption {
+	return func(o *clientOptions) error {
+		o.taskObservers = append(o.taskObservers, observers...)
+		return nil
+	}
+}
+
+// WithOperationObserver adds observer of operations consisting of several requests, e.g. tracing
+func WithOperationObserver(observers ...OperationObserver) ClientOption {
+	return func(o *clientOptions) error {
+		o.opObservers = append(o.opObservers, observers...)
+		return nil
+	}
+}
+
+// WithRateLimits limits rate and concurrency of requests globally and by request class
+func WithRateLimits(limits RateLimits) ClientOption {
+	return func(o *clientOptions) error {
+		o.rateLimiter = NewRateLimiter(limits)
+		return nil
+	}
+}
+
+// WithRateLimiter shares rate limiter between clients of the same controller
+func WithRateLimiter(limiter *RateLimiter) ClientOption {
+	return func(o *clientOptions) error {
+		o.rateLimiter = limiter
+		return nil
+	}
+}
+
+// WithTimeout limits the whole request including reading of the response body, zero means no limit
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(o *clientOptions) error {
+		o.timeout = timeout
+		return nil
+	}
+}
+
+// WithDialTimeout limits establishing of TCP connection
+func WithDialTimeout(timeout time.Duration) ClientOption {
+	return func(o *clientOptions) error {
+		o.dialTimeout = timeout
+		return nil
+	}
+}
+
+// WithTLSHandshakeTimeout limits TLS handshake
+func WithTLSHandshakeTimeout(timeout time.Duration) ClientOption {
+	return func(o *clientOptions) error {
+		o.tlsHandshakeTimeout = timeout
+		return nil
+	}
+}
+
+// WithInsecure disables verification of controller certificate
+func WithInsecure(insecure bool) ClientOption {
+	return func(o *clientOptions) error {
+		o.insecure = insecure
+		return nil
+	}
+}
+
+// WithRootCAs pool of CA certificates used to verify the controller instead of system pool
+func WithRootCAs(pool *x509.CertPool) ClientOption {
+	return func(o *clientOptions) error {
+		o.rootCAs = pool
+		return nil
+	}
+}
+
+// WithCAFile reads PEM encoded CA certificates from file, they are added to the pool of WithRootCAs if any
+func WithCAFile(path string) ClientOption {
+	return func(o *clientOptions) error {
+		pem, err := ioutil.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read CA file: %w", err)
+		}
+		return WithCAPEM(pem)(o)
+	}
+}
+
+// WithCAPEM adds PEM encoded CA certificates to the pool of trusted CAs
+func WithCAPEM(pem []byte) ClientOption {
+	return func(o *clientOptions) error {
+		if o.rootCAs == nil {
+			o.rootCAs = x509.NewCertPool()
+		}
+		if !o.rootCAs.AppendCertsFromPEM(pem) {
+			return errors.New("no CA certificates found in PEM")
+		}
+		return nil
+	}
+}
+
+// WithClientCertificate client certificate presented to the controller (mutual TLS)
+func WithClientCertificate(cert tls.Certificate) ClientOption {
+	return func(o *clientOptions) error {
+		o.certificates = append(o.certificates, cert)
+		return nil
+	}
+}
+
+// WithClientCertificateFile loads client certificate and key from PEM files (mutual TLS)
+func WithClientCertificateFile(certFile, keyFile string) ClientOption {
+	return func(o *clientOptions) error {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return fmt.Errorf("load client certificate: %w", err)
+		}
+		o.certificates = append(o.certificates, cert)
+		return nil
+	}
+}
+
+// WithProxyFromEnvironment uses proxy from HTTP_PROXY, HTTPS_PROXY and NO_PROXY variables
+func WithProxyFromEnvironment() ClientOption {
+	return func(o *clientOptions) error {
+		o.proxy = http.ProxyFromEnvironment
+		return nil
+	}
+}
+
+// WithProxyURL sends all requests through proxy
+func WithProxyURL(proxyUrl string) ClientOption {
+	return func(o *clientOptions) error {
+		u, err := url.Parse(proxyUrl)
+		if err != nil {
+			return fmt.Errorf("parse proxy url: %w", err)
+		}
+		o.proxy = http.ProxyURL(u)
+		return nil
+	}
+}
+
+// WithCompression enables gzip compression of responses
+func WithCompression(enabled bool) ClientOption {
+	return func(o *clientOptions) error {
+		o.compression = enabled
+		return nil
+	}
+}
+
+// WithConnectionPool sizes of idle connection pool and limit of connections per host, zero values mean defaults of net/http
+func WithConnectionPool(maxIdleConns, maxIdleConnsPerHost, maxConnsPerHost int, idleConnTimeout time.Duration) ClientOption {
+	return func(o *clientOptions) error {
+		o.maxIdleConns = maxIdleConns
+		o.maxIdleConnsPerHost = maxIdleConnsPerHost
+		o.maxConnsPerHost = maxConnsPerHost
+		o.idleConnTimeout = idleConnTimeout
+		return nil
+	}
+}
+
+// WithTransport fully custom RoundTripper, TLS, proxy, compression and pool options are ignored then
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(o *clientOptions) error {
+		if transport == nil {
+			return errors.New("transport is nil")
+		}
+		o.transport = transport
+		return nil
+	}
+}
+
+// NewClientWithOptions Web client creating with options, an error is returned if any option is invalid
+func NewClientWithOptions(opts ...ClientOption) (*WebClient, error) {
+	o := &clientOptions{acceptLanguage: DefaultAcceptLanguage}
+	for _, opt := range opts {
+		if err := opt(o); err != nil {
+			return nil, err
+		}
+	}
+	transport := o.transport
+	if transport == nil {
+		transport = o.newTransport()
+	}
+	baseURL := o.baseURL
+	var pool *EndpointPool
+	if len(o.endpoints) != 0 {
+		var err error
+		if pool, err = NewEndpointPool(o.endpoints, o.failover); err != nil {
+			return nil, err
+		}
+		baseURL = o.endpoints[0]
+	}
+	client := newWebClient(baseURL, o.token, &http.Client{Transport: transport, Timeout: o.timeout})
+	client.Endpoints = pool
+	client.UserAgent = o.userAgent
+	client.AcceptLanguage = o.acceptLanguage
+	client.RetryPolicy = o.retryPolicy
+	client.RateLimiter = o.rateLimiter
+	client.TokenSource = o.tokenSource
+	client.Logger = o.logger
+	client.Middleware = o.middleware
+	client.TaskObservers = o.taskObservers
+	client.OperationObservers = o.opObservers
+	if o.credentials != nil {
+		client.TokenSource = NewRefreshingTokenSource(NewPasswordTokenSource(client, *o.credentials), 0)
+	}
+	return client, nil
+}
+
+func (o *clientOptions) newTransport() *http.Transport {
+	dialer := &net.Dialer{Timeout: o.dialTimeout, KeepAlive: 30 * time.Second}
+	return &http.Transport{
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: o.insecure,
+			RootCAs:            o.rootCAs,
+			Certificates:       o.certificates,
+		},
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: o.tlsHandshakeTimeout,
+		DisableCompression:  !o.compression,
+		Proxy:               o.proxy,
+		MaxIdleConns:        o.maxIdleConns,
+		MaxIdleConnsPerHost: o.maxIdleConnsPerHost,
+		MaxConnsPerHost:     o.maxConnsPerHost,
+		IdleConnTimeout:     o.idleConnTimeout,
+	}
+}
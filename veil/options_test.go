@@ -0,0 +1,107 @@
+package veil
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_ClientOptionsHeadersAndCA(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "ru", r.Header.Get("Accept-Language"))
+		assert.Equal(t, "veil-test/1.0", r.Header.Get("User-Agent"))
+		assert.Equal(t, "jwt token", r.Header.Get("Authorization"))
+		w.Write([]byte(`{"id": "node-1"}`))
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, ioutil.WriteFile(caFile, caPEM, 0600))
+
+	client, err := NewClientWithOptions(
+		WithBaseURL(server.URL),
+		WithToken("token"),
+		WithCAFile(caFile),
+		WithUserAgent("veil-test/1.0"),
+		WithAcceptLanguage("ru"),
+		WithTimeout(5*time.Second),
+		WithDialTimeout(time.Second),
+		WithConnectionPool(10, 2, 4, time.Minute),
+	)
+	require.NoError(t, err)
+	node, _, err := client.Node.Get("node-1")
+	require.NoError(t, err)
+	assert.Equal(t, "node-1", node.Id)
+
+	// Without the CA the controller certificate is not trusted
+	client, err = NewClientWithOptions(WithBaseURL(server.URL))
+	require.NoError(t, err)
+	_, _, err = client.Node.Get("node-1")
+	assert.Error(t, err)
+	return
+}
+
+func Test_ClientOptionsMutualTLS(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Len(t, r.TLS.PeerCertificates, 1)
+		w.Write([]byte(`{"id": "node-1"}`))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	client, err := NewClientWithOptions(WithBaseURL(server.URL), WithInsecure(true))
+	require.NoError(t, err)
+	_, _, err = client.Node.Get("node-1")
+	assert.Error(t, err)
+
+	// The server certificate is good enough as client certificate for RequireAnyClientCert
+	client, err = NewClientWithOptions(WithBaseURL(server.URL), WithInsecure(true),
+		WithClientCertificate(server.TLS.Certificates[0]))
+	require.NoError(t, err)
+	_, _, err = client.Node.Get("node-1")
+	assert.NoError(t, err)
+	return
+}
+
+type recordingTransport struct {
+	requests []*http.Request
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.requests = append(rt.requests, req)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       ioutil.NopCloser(strings.NewReader(`{"id": "node-1"}`)),
+		Request:    req,
+	}, nil
+}
+
+func Test_ClientOptionsTransport(t *testing.T) {
+	transport := &recordingTransport{}
+	client, err := NewClientWithOptions(WithBaseURL("http://veil"), WithTransport(transport))
+	require.NoError(t, err)
+	_, _, err = client.Node.Get("node-1")
+	require.NoError(t, err)
+	require.Len(t, transport.requests, 1)
+	assert.Equal(t, "http://veil/api/nodes/node-1/", transport.requests[0].URL.String())
+	assert.Equal(t, "en", transport.requests[0].Header.Get("Accept-Language"))
+
+	_, err = NewClientWithOptions(WithCAFile(filepath.Join(t.TempDir(), "missing.pem")))
+	assert.Error(t, err)
+	_, err = NewClientWithOptions(WithCAPEM([]byte("not a certificate")))
+	assert.Error(t, err)
+	_, err = NewClientWithOptions(WithTransport(nil))
+	assert.Error(t, err)
+	return
+}
@@ -0,0 +1,211 @@
+package veil
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"sync"
+)
+
+// DefaultPageSize number of items requested per page by list iterators
+const DefaultPageSize = 100
+
+// ListFilter is implemented by typed filters of list endpoints
+type ListFilter interface {
+	Values() url.Values
+}
+
+// Params untyped filter with the same meaning as queryParams of ListParams methods
+type Params map[string]string
+
+// Values encodes params to query parameters
+func (p Params) Values() url.Values {
+	params := url.Values{}
+	for k, v := range p {
+		params.Add(k, v)
+	}
+	return params
+}
+
+// PageOptions tunes list iterators, nil means defaults
+type PageOptions struct {
+	// PageSize number of items requested per page, DefaultPageSize if zero
+	PageSize int
+	// Prefetch number of pages loaded concurrently ahead of the consumer.
+	// Zero loads pages one by one following Next, otherwise pages are addressed by offset
+	// after the first one, so items created or removed during iteration may be skipped or repeated.
+	Prefetch int
+}
+
+type rawPage struct {
+	BaseListResponse
+	Results []json.RawMessage `json:"results,omitempty"`
+}
+
+type pageResult struct {
+	page *rawPage
+	err  error
+}
+
+// Iterator streams items of a list endpoint page by page. Typed iterators of services embed it.
+type Iterator struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	client  Client
+	listUrl string
+	params  url.Values
+	opts    PageOptions
+
+	started bool
+	count   int
+	next    string
+	pending chan chan pageResult
+	items   []json.RawMessage
+	current json.RawMessage
+	err     error
+}
+
+func newIterator(ctx context.Context, client Client, listUrl string, filter ListFilter, opts *PageOptions) *Iterator {
+	params := url.Values{}
+	if filter != nil {
+		for k, v := range filter.Values() {
+			params[k] = append([]string{}, v...)
+		}
+	}
+	it := &Iterator{client: client, listUrl: listUrl, params: params}
+	if opts != nil {
+		it.opts = *opts
+	}
+	if it.opts.PageSize <= 0 {
+		it.opts.PageSize = DefaultPageSize
+	}
+	it.ctx, it.cancel = context.WithCancel(ctx)
+	return it
+}
+
+// Next advances to the next item, it returns false when the list is exhausted or an error occurred
+func (it *Iterator) Next() bool {
+	for len(it.items) == 0 {
+		if it.err != nil || !it.loadPage() {
+			it.current = nil
+			return false
+		}
+	}
+	it.current, it.items = it.items[0], it.items[1:]
+	return true
+}
+
+// Err returns the error which stopped the iteration
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// Count total number of items reported by the first page
+func (it *Iterator) Count() int {
+	return it.count
+}
+
+// Close stops prefetching, it is not needed if the iterator was exhausted
+func (it *Iterator) Close() {
+	it.cancel()
+}
+
+// decode unmarshals the current item into v
+func (it *Iterator) decode(v interface{}) bool {
+	if err := json.Unmarshal(it.current, v); err != nil {
+		it.err = err
+		it.cancel()
+		return false
+	}
+	return true
+}
+
+// loadPage loads the next page into it.items, it returns false if there are no more pages
+func (it *Iterator) loadPage() bool {
+	var page *rawPage
+	var err error
+	switch {
+	case !it.started:
+		it.started = true
+		params := cloneValues(it.params)
+		params.Set("limit", strconv.Itoa(it.opts.PageSize))
+		page, err = it.fetch(params)
+		if err == nil {
+			it.count = page.Count
+			if it.opts.Prefetch > 0 && page.Next != "" {
+				it.prefetch(len(page.Results))
+			}
+		}
+	case it.pending != nil:
+		result, ok := <-it.pending
+		if !ok {
+			return false
+		}
+		r := <-result
+		page, err = r.page, r.err
+	case it.next != "":
+		var next *url.URL
+		next, err = url.Parse(it.next)
+		if err == nil {
+			page, err = it.fetch(next.Query())
+		}
+	default:
+		return false
+	}
+	if err != nil {
+		it.err = err
+		it.cancel()
+		return false
+	}
+	it.items = page.Results
+	it.next = page.Next
+	return true
+}
+
+func (it *Iterator) fetch(params url.Values) (*rawPage, error) {
+	page := new(rawPage)
+	_, err := it.client.ExecuteRequestContext(it.ctx, "GET", it.listUrl+"?"+params.Encode(), []byte{}, page)
+	return page, err
+}
+
+// prefetch starts loading of all remaining pages by offset, at most opts.Prefetch at once.
+// Offsets continue from the offset of the filter if it has one
+func (it *Iterator) prefetch(loaded int) {
+	if loaded == 0 {
+		return
+	}
+	start, _ := strconv.Atoi(it.params.Get("offset"))
+	pending := make(chan chan pageResult, it.opts.Prefetch)
+	it.pending = pending
+	go func() {
+		defer close(pending)
+		var wg sync.WaitGroup
+		defer wg.Wait()
+		for offset := start + loaded; offset < it.count; offset += loaded {
+			params := cloneValues(it.params)
+			params.Set("limit", strconv.Itoa(it.opts.PageSize))
+			params.Set("offset", strconv.Itoa(offset))
+			result := make(chan pageResult, 1)
+			select {
+			case pending <- result:
+			case <-it.ctx.Done():
+				return
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				page, err := it.fetch(params)
+				result <- pageResult{page, err}
+			}()
+		}
+	}()
+}
+
+func cloneValues(values url.Values) url.Values {
+	clone := url.Values{}
+	for k, v := range values {
+		clone[k] = append([]string{}, v...)
+	}
+	return clone
+}
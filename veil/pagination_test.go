@@ -0,0 +1,100 @@
+package veil
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// newPagedServer serves count domains with limit/offset pagination
+func newPagedServer(t *testing.T, count int) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Equal(t, "ACTIVE", query.Get("status"))
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		offset, _ := strconv.Atoi(query.Get("offset"))
+		var results []string
+		for i := offset; i < offset+limit && i < count; i++ {
+			results = append(results, fmt.Sprintf(`{"id": "domain-%d"}`, i))
+		}
+		next := ""
+		if offset+limit < count {
+			query.Set("offset", strconv.Itoa(offset+limit))
+			next = fmt.Sprint(server.URL, r.URL.Path, "?", query.Encode())
+		}
+		fmt.Fprintf(w, `{"count": %d, "next": "%s", "results": [%s]}`, count, next, strings.Join(results, ","))
+	}))
+	return server
+}
+
+func Test_PaginationListAll(t *testing.T) {
+	server := newPagedServer(t, 250)
+	defer server.Close()
+	client := NewClient(server.URL, "token", false)
+
+	domains, err := client.Domain.ListAll(context.Background(), Params{"status": "ACTIVE"})
+	require.Nil(t, err)
+	require.Len(t, domains, 250)
+	assert.Equal(t, "domain-249", domains[249].Id)
+
+	return
+}
+
+func Test_PaginationIterPrefetch(t *testing.T) {
+	server := newPagedServer(t, 95)
+	defer server.Close()
+	client := NewClient(server.URL, "token", false)
+
+	it := client.Domain.Iter(context.Background(), Params{"status": "ACTIVE"}, &PageOptions{PageSize: 10, Prefetch: 3})
+	defer it.Close()
+	var i int
+	for it.Next() {
+		assert.Equal(t, fmt.Sprint("domain-", i), it.Item().Id)
+		i++
+	}
+	require.Nil(t, it.Err())
+	assert.Equal(t, 95, i)
+	assert.Equal(t, 95, it.Count())
+
+	return
+}
+
+func Test_PaginationPrefetchFilterOffset(t *testing.T) {
+	server := newPagedServer(t, 95)
+	defer server.Close()
+	client := NewClient(server.URL, "token", false)
+
+	it := client.Domain.Iter(context.Background(), Params{"status": "ACTIVE", "offset": "40"},
+		&PageOptions{PageSize: 10, Prefetch: 3})
+	defer it.Close()
+	i := 40
+	for it.Next() {
+		assert.Equal(t, fmt.Sprint("domain-", i), it.Item().Id)
+		i++
+	}
+	require.Nil(t, it.Err())
+	assert.Equal(t, 95, i)
+
+	return
+}
+
+func Test_PaginationError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "token", false)
+
+	it := client.Event.Iter(context.Background(), nil, nil)
+	assert.False(t, it.Next())
+	assert.True(t, IsPermissionDenied(it.Err()))
+
+	return
+}
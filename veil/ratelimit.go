@@ -0,0 +1,246 @@
+package veil
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultMaxPollInterval upper bound of adaptive task poll interval
+const DefaultMaxPollInterval = 30 * time.Second
+
+// RequestClass class of API request for rate limits
+type RequestClass string
+
+const (
+	// ClassRead GET, HEAD and OPTIONS requests
+	ClassRead RequestClass = "read"
+	// ClassWrite requests changing the state of the controller
+	ClassWrite RequestClass = "write"
+	// ClassTaskPoll polls of TaskService.Wait
+	ClassTaskPoll RequestClass = "task_poll"
+)
+
+type requestClassKey struct{}
+
+// WithRequestClass overrides class of requests made with ctx
+func WithRequestClass(ctx context.Context, class RequestClass) context.Context {
+	return context.WithValue(ctx, requestClassKey{}, class)
+}
+
+// requestClass returns class set by WithRequestClass or derived from the method
+func requestClass(ctx context.Context, method string) RequestClass {
+	if class, ok := ctx.Value(requestClassKey{}).(RequestClass); ok {
+		return class
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return ClassRead
+	default:
+		return ClassWrite
+	}
+}
+
+// Limit of requests, zero values mean no limit
+type Limit struct {
+	// Rate requests per second
+	Rate float64
+	// Burst max number of requests sent at once above Rate, 1 if not set
+	Burst int
+	// MaxInFlight max number of concurrent requests
+	MaxInFlight int
+}
+
+// RateLimits client side limits of requests, every request takes the global limit and the limit of its class
+type RateLimits struct {
+	Global   Limit
+	Read     Limit
+	Write    Limit
+	TaskPoll Limit
+
+	// PollWaitsThreshold number of concurrent task waits after which the poll interval grows proportionally,
+	// e.g. with threshold 20 and 60 waits every task is polled 3 times less often. Zero disables adaptive polling
+	PollWaitsThreshold int
+	// MaxPollInterval upper bound of adaptive poll interval, DefaultMaxPollInterval if not set
+	MaxPollInterval time.Duration
+}
+
+// RateLimiter limits rate and concurrency of requests of the client, it is safe for concurrent use
+type RateLimiter struct {
+	limits  RateLimits
+	global  *limiter
+	classes map[RequestClass]*limiter
+	waits   int32
+}
+
+// NewRateLimiter creates limiter, it may be shared by several clients of the same controller
+func NewRateLimiter(limits RateLimits) *RateLimiter {
+	if limits.MaxPollInterval <= 0 {
+		limits.MaxPollInterval = DefaultMaxPollInterval
+	}
+	return &RateLimiter{
+		limits: limits,
+		global: newLimiter(limits.Global),
+		classes: map[RequestClass]*limiter{
+			ClassRead:     newLimiter(limits.Read),
+			ClassWrite:    newLimiter(limits.Write),
+			ClassTaskPoll: newLimiter(limits.TaskPoll),
+		},
+	}
+}
+
+// ActiveTaskWaits number of task waits in progress
+func (l *RateLimiter) ActiveTaskWaits() int {
+	return int(atomic.LoadInt32(&l.waits))
+}
+
+// acquire waits for the rate limits and free slots, release must be called once the request is done
+func (l *RateLimiter) acquire(ctx context.Context, class RequestClass) (release func(), err error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	classLimiter := l.classes[class]
+	if classLimiter == nil {
+		classLimiter = l.classes[ClassRead]
+	}
+	if err := classLimiter.wait(ctx); err != nil {
+		return nil, err
+	}
+	if err := l.global.wait(ctx); err != nil {
+		return nil, err
+	}
+	if err := l.global.acquire(ctx); err != nil {
+		return nil, err
+	}
+	if err := classLimiter.acquire(ctx); err != nil {
+		l.global.release()
+		return nil, err
+	}
+	return func() {
+		classLimiter.release()
+		l.global.release()
+	}, nil
+}
+
+// startTaskWait registers task wait for adaptive polling, the returned function unregisters it
+func (l *RateLimiter) startTaskWait() func() {
+	if l == nil {
+		return func() {}
+	}
+	atomic.AddInt32(&l.waits, 1)
+	return func() {
+		atomic.AddInt32(&l.waits, -1)
+	}
+}
+
+// pollInterval grows interval proportionally to the number of concurrent waits above the threshold
+func (l *RateLimiter) pollInterval(interval time.Duration) time.Duration {
+	if l == nil || l.limits.PollWaitsThreshold <= 0 {
+		return interval
+	}
+	waits := l.ActiveTaskWaits()
+	if waits <= l.limits.PollWaitsThreshold {
+		return interval
+	}
+	adapted := time.Duration(float64(interval) * float64(waits) / float64(l.limits.PollWaitsThreshold))
+	if adapted > l.limits.MaxPollInterval {
+		adapted = l.limits.MaxPollInterval
+	}
+	if adapted < interval {
+		return interval
+	}
+	return adapted
+}
+
+// limiter token bucket with semaphore, nil fields mean no limit
+type limiter struct {
+	bucket *tokenBucket
+	slots  chan struct{}
+}
+
+func newLimiter(limit Limit) *limiter {
+	l := &limiter{}
+	if limit.Rate > 0 {
+		burst := limit.Burst
+		if burst <= 0 {
+			burst = 1
+		}
+		l.bucket = &tokenBucket{rate: limit.Rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+	}
+	if limit.MaxInFlight > 0 {
+		l.slots = make(chan struct{}, limit.MaxInFlight)
+	}
+	return l
+}
+
+func (l *limiter) wait(ctx context.Context) error {
+	if l.bucket == nil {
+		return nil
+	}
+	return l.bucket.wait(ctx)
+}
+
+func (l *limiter) acquire(ctx context.Context) error {
+	if l.slots == nil {
+		return nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *limiter) release() {
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// wait takes a token, waiting for it if the bucket is empty. Tokens may go negative, which reserves future tokens in order
+func (b *tokenBucket) wait(ctx context.Context) error {
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+	b.tokens--
+	delay := time.Duration(-b.tokens / b.rate * float64(time.Second))
+	b.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+	if err := sleepContext(ctx, delay); err != nil {
+		// Canceled request gives its reservation back
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// rateLimiterOf returns rate limiter of c, nil if c has none
+func rateLimiterOf(c Client) *RateLimiter {
+	if l, ok := c.(interface{ rateLimiter() *RateLimiter }); ok {
+		return l.rateLimiter()
+	}
+	return nil
+}
+
+func (client *WebClient) rateLimiter() *RateLimiter {
+	return client.RateLimiter
+}
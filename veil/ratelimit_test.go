@@ -0,0 +1,118 @@
+package veil
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_RateLimiterMaxInFlight(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			observed := atomic.LoadInt32(&maxInFlight)
+			if current <= observed || atomic.CompareAndSwapInt32(&maxInFlight, observed, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte(`{"id": "node-1"}`))
+	}))
+	defer server.Close()
+	client, err := NewClientWithOptions(WithBaseURL(server.URL), WithRateLimits(RateLimits{Global: Limit{MaxInFlight: 2}}))
+	require.NoError(t, err)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := client.Node.Get("node-1")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(2), atomic.LoadInt32(&maxInFlight))
+	return
+}
+
+func Test_RateLimiterRateByClass(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client, err := NewClientWithOptions(WithBaseURL(server.URL), WithRateLimits(RateLimits{Write: Limit{Rate: 20}}))
+	require.NoError(t, err)
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		_, err := client.ExecuteRequest("GET", baseNodeUrl, []byte{}, nil)
+		require.NoError(t, err)
+	}
+	assert.Less(t, int64(time.Since(start)), int64(100*time.Millisecond), "reads are not limited")
+
+	start = time.Now()
+	for i := 0; i < 5; i++ {
+		_, err := client.ExecuteRequest("POST", baseNodeUrl, []byte{}, nil)
+		require.NoError(t, err)
+	}
+	// The first write takes the burst token, 4 more are 50ms apart
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(190*time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	// The bucket is empty after the writes above, the next token is later than the deadline
+	_, err = client.ExecuteRequestContext(ctx, "POST", baseNodeUrl, []byte{}, nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	return
+}
+
+func Test_RateLimiterAdaptivePolling(t *testing.T) {
+	limiter := NewRateLimiter(RateLimits{PollWaitsThreshold: 2, MaxPollInterval: 5 * time.Second})
+	var finish []func()
+	for i := 0; i < 2; i++ {
+		finish = append(finish, limiter.startTaskWait())
+	}
+	assert.Equal(t, time.Second, limiter.pollInterval(time.Second))
+	for i := 0; i < 4; i++ {
+		finish = append(finish, limiter.startTaskWait())
+	}
+	assert.Equal(t, 6, limiter.ActiveTaskWaits())
+	assert.Equal(t, 3*time.Second, limiter.pollInterval(time.Second))
+	assert.Equal(t, 5*time.Second, limiter.pollInterval(2*time.Second))
+	for _, f := range finish {
+		f()
+	}
+	assert.Equal(t, 0, limiter.ActiveTaskWaits())
+	var nilLimiter *RateLimiter
+	assert.Equal(t, time.Second, nilLimiter.pollInterval(time.Second))
+	return
+}
+
+func Test_RateLimiterTaskPollClass(t *testing.T) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&polls, 1) < 3 {
+			w.Write([]byte(`{"id": "task-1", "status": "IN_PROGRESS"}`))
+			return
+		}
+		w.Write([]byte(`{"id": "task-1", "status": "SUCCESS"}`))
+	}))
+	defer server.Close()
+	client, err := NewClientWithOptions(WithBaseURL(server.URL), WithRateLimits(RateLimits{TaskPoll: Limit{Rate: 10}}))
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = client.Task.Wait(context.Background(), "task-1", &TaskWaitOptions{PollInterval: time.Millisecond})
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(190*time.Millisecond))
+	return
+}
@@ -0,0 +1,179 @@
+package veil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// DomainCpuConfig CPU settings of DomainService.UpdateCpu, zero fields are not changed
+type DomainCpuConfig struct {
+	CpuTopology
+	Machine string `json:"machine,omitempty"`
+}
+
+// DomainMemoryConfig memory settings in MiB of DomainService.UpdateMemory, zero fields are not changed
+type DomainMemoryConfig struct {
+	MemoryCount        int `json:"memory_count,omitempty"`
+	MemoryMinGuarantee int `json:"memory_min_guarantee,omitempty"`
+	MemoryShares       int `json:"memory_shares,omitempty"`
+	MemoryLimit        int `json:"memory_limit,omitempty"`
+}
+
+// ResourcePlan how a CPU or memory change takes effect, fields are named as in JSON
+type ResourcePlan struct {
+	// Applied fields which take effect at once, on running domain they are hot-plugged
+	Applied []string
+	// PendingReboot fields which take effect after the running domain is rebooted
+	PendingReboot []string
+}
+
+// RebootRequired reports whether the domain has to be rebooted for the whole change to take effect
+func (p ResourcePlan) RebootRequired() bool {
+	return len(p.PendingReboot) > 0
+}
+
+func (p *ResourcePlan) add(field string, set bool, hotPlug bool) {
+	switch {
+	case !set:
+	case hotPlug:
+		p.Applied = append(p.Applied, field)
+	default:
+		p.PendingReboot = append(p.PendingReboot, field)
+	}
+}
+
+// isLive reports whether the domain is known to be running or suspended, domain of unknown power state
+// (e.g. not refreshed) is planned as powered off
+func (domain *DomainObject) isLive() bool {
+	return domain.UserPowerState == PowerState.On || domain.UserPowerState == PowerState.Suspended
+}
+
+// Validate checks the config without sending it, errors wrap ErrInvalidConfig
+func (config DomainCpuConfig) Validate() error {
+	if err := validatePattern("cpu_mode", CpuModes, config.CpuMode); err != nil {
+		return err
+	}
+	if err := validatePattern("machine", MachineTypes, config.Machine); err != nil {
+		return err
+	}
+	if config.CpuModel != "" && config.CpuMode != "" && config.CpuMode != "custom" {
+		return fmt.Errorf("%w: cpu_model requires custom cpu_mode, got %q", ErrInvalidConfig, config.CpuMode)
+	}
+	for _, v := range []int{config.CpuCount, config.CpuCountMax, config.CpuSockets, config.CpuCores, config.CpuThreads,
+		config.CpuPriority, config.CpuShares, config.CpuMinGuarantee} {
+		if v < 0 {
+			return fmt.Errorf("%w: negative cpu value %d", ErrInvalidConfig, v)
+		}
+	}
+	if config.CpuCountMax > 0 && config.CpuCount > config.CpuCountMax {
+		return fmt.Errorf("%w: cpu_count %d exceeds cpu_count_max %d", ErrInvalidConfig, config.CpuCount, config.CpuCountMax)
+	}
+	if config.CpuSockets > 0 || config.CpuCores > 0 || config.CpuThreads > 0 {
+		if config.CpuSockets == 0 || config.CpuCores == 0 || config.CpuThreads == 0 {
+			return fmt.Errorf("%w: cpu topology requires sockets, cores and threads", ErrInvalidConfig)
+		}
+		vcpus := config.CpuCountMax
+		if vcpus == 0 {
+			vcpus = config.CpuCount
+		}
+		if total := config.CpuSockets * config.CpuCores * config.CpuThreads; vcpus > 0 && total != vcpus {
+			return fmt.Errorf("%w: cpu topology gives %d vcpus instead of %d", ErrInvalidConfig, total, vcpus)
+		}
+	}
+	for vcpu := range config.CpuMap {
+		if n, err := strconv.Atoi(vcpu); err != nil || n < 0 {
+			return fmt.Errorf("%w: cpu_map key %q is not a vcpu number", ErrInvalidConfig, vcpu)
+		}
+	}
+	return nil
+}
+
+// Plan tells which fields of the config are hot-plugged into the domain. Adding vcpus up to domain.CpuCountMax,
+// pinning and scheduling settings are applied to running domain. Removing vcpus, adding them over the maximum
+// or together with a new maximum, topology, mode, model and machine type need a reboot.
+// Everything is applied at once to powered off domain
+func (config DomainCpuConfig) Plan(domain *DomainObject) ResourcePlan {
+	live := domain.isLive()
+	hotPlugVcpus := config.CpuCount >= domain.CpuCount && config.CpuCount <= domain.CpuCountMax && config.CpuCountMax == 0
+	var plan ResourcePlan
+	plan.add("cpu_count", config.CpuCount > 0, !live || hotPlugVcpus)
+	plan.add("cpu_count_max", config.CpuCountMax > 0, !live)
+	plan.add("cpu_topology", config.CpuSockets > 0, !live)
+	plan.add("cpu_map", len(config.CpuMap) > 0, true)
+	plan.add("cpu_mode", config.CpuMode != "", !live)
+	plan.add("cpu_model", config.CpuModel != "", !live)
+	plan.add("cpu_features_required", len(config.CpuFeaturesRequired) > 0, !live)
+	plan.add("machine", config.Machine != "", !live)
+	plan.add("cpu_priority", config.CpuPriority > 0, true)
+	plan.add("cpu_shares", config.CpuShares > 0, true)
+	plan.add("cpu_min_guarantee", config.CpuMinGuarantee > 0, true)
+	return plan
+}
+
+// Validate checks the config without sending it, errors wrap ErrInvalidConfig
+func (config DomainMemoryConfig) Validate() error {
+	for _, v := range []int{config.MemoryCount, config.MemoryMinGuarantee, config.MemoryShares, config.MemoryLimit} {
+		if v < 0 {
+			return fmt.Errorf("%w: negative memory value %d", ErrInvalidConfig, v)
+		}
+	}
+	if config.MemoryCount > 0 && config.MemoryMinGuarantee > config.MemoryCount {
+		return fmt.Errorf("%w: memory_min_guarantee %d exceeds memory_count %d", ErrInvalidConfig,
+			config.MemoryMinGuarantee, config.MemoryCount)
+	}
+	if config.MemoryLimit > 0 && config.MemoryCount > config.MemoryLimit {
+		return fmt.Errorf("%w: memory_count %d exceeds memory_limit %d", ErrInvalidConfig, config.MemoryCount, config.MemoryLimit)
+	}
+	return nil
+}
+
+// Plan tells which fields of the config are hot-plugged into the domain. Adding memory up to
+// domain.MemoryCountMax, guarantee, shares and limit are applied to running domain. Removing memory or adding it
+// over the maximum, also unknown one, needs a reboot. Everything is applied at once to powered off domain
+func (config DomainMemoryConfig) Plan(domain *DomainObject) ResourcePlan {
+	live := domain.isLive()
+	hotPlugMemory := config.MemoryCount >= domain.MemoryCount && config.MemoryCount <= domain.MemoryCountMax
+	var plan ResourcePlan
+	plan.add("memory_count", config.MemoryCount > 0, !live || hotPlugMemory)
+	plan.add("memory_min_guarantee", config.MemoryMinGuarantee > 0, true)
+	plan.add("memory_shares", config.MemoryShares > 0, true)
+	plan.add("memory_limit", config.MemoryLimit > 0, true)
+	return plan
+}
+
+// UpdateCpu changes CPU settings of the domain, the domain is updated in place. The plan is made from
+// the domain state before the request
+func (d *DomainService) UpdateCpu(domain *DomainObject, config DomainCpuConfig) (*ResourcePlan, *http.Response, error) {
+	return d.UpdateCpuContext(context.Background(), domain, config)
+}
+
+func (d *DomainService) UpdateCpuContext(ctx context.Context, domain *DomainObject, config DomainCpuConfig) (*ResourcePlan, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.UpdateCpu")
+	if err := config.Validate(); err != nil {
+		return nil, nil, err
+	}
+	plan := config.Plan(domain)
+	b, _ := json.Marshal(config)
+	res, err := d.client.ExecuteRequestContext(ctx, "PUT", fmt.Sprint(baseDomainUrl, domain.Id, "/cpu/"), b, domain)
+	return &plan, res, err
+}
+
+// UpdateMemory changes memory settings of the domain, the domain is updated in place. The plan is made from
+// the domain state before the request
+func (d *DomainService) UpdateMemory(domain *DomainObject, config DomainMemoryConfig) (*ResourcePlan, *http.Response, error) {
+	return d.UpdateMemoryContext(context.Background(), domain, config)
+}
+
+func (d *DomainService) UpdateMemoryContext(ctx context.Context, domain *DomainObject, config DomainMemoryConfig) (*ResourcePlan, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.UpdateMemory")
+	if err := config.Validate(); err != nil {
+		return nil, nil, err
+	}
+	plan := config.Plan(domain)
+	b, _ := json.Marshal(config)
+	res, err := d.client.ExecuteRequestContext(ctx, "PUT", fmt.Sprint(baseDomainUrl, domain.Id, "/memory/"), b, domain)
+	return &plan, res, err
+}
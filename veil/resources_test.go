@@ -0,0 +1,137 @@
+package veil
+
+import (
+	"github.com/jsc-masshtab/veil-api-client-go/veil/veiltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func Test_DomainCpuConfigValidate(t *testing.T) {
+	valid := []DomainCpuConfig{
+		{},
+		{CpuTopology: CpuTopology{CpuCount: 4, CpuSockets: 1, CpuCores: 2, CpuThreads: 2}},
+		{CpuTopology: CpuTopology{CpuCount: 2, CpuCountMax: 8, CpuSockets: 2, CpuCores: 4, CpuThreads: 1}},
+		{CpuTopology: CpuTopology{CpuMode: "custom", CpuModel: "Skylake-Server"}, Machine: "q35"},
+		{CpuTopology: CpuTopology{CpuMap: map[string]string{"0": "0-3", "1": "4"}}},
+	}
+	for _, config := range valid {
+		assert.NoError(t, config.Validate(), "%+v", config)
+	}
+	invalid := []DomainCpuConfig{
+		{CpuTopology: CpuTopology{CpuMode: "passthrough"}},
+		{Machine: "isapc"},
+		{CpuTopology: CpuTopology{CpuMode: "host-model", CpuModel: "Skylake-Server"}},
+		{CpuTopology: CpuTopology{CpuCount: -1}},
+		{CpuTopology: CpuTopology{CpuCount: 8, CpuCountMax: 4}},
+		{CpuTopology: CpuTopology{CpuCount: 4, CpuSockets: 2}},
+		{CpuTopology: CpuTopology{CpuCount: 4, CpuSockets: 2, CpuCores: 2, CpuThreads: 2}},
+		{CpuTopology: CpuTopology{CpuMap: map[string]string{"all": "0-3"}}},
+	}
+	for _, config := range invalid {
+		assert.ErrorIs(t, config.Validate(), ErrInvalidConfig, "%+v", config)
+	}
+
+	assert.NoError(t, DomainMemoryConfig{MemoryCount: 2048, MemoryMinGuarantee: 1024, MemoryLimit: 4096}.Validate())
+	assert.ErrorIs(t, DomainMemoryConfig{MemoryCount: 2048, MemoryMinGuarantee: 4096}.Validate(), ErrInvalidConfig)
+	assert.ErrorIs(t, DomainMemoryConfig{MemoryCount: 2048, MemoryLimit: 1024}.Validate(), ErrInvalidConfig)
+	assert.ErrorIs(t, DomainMemoryConfig{MemoryShares: -1}.Validate(), ErrInvalidConfig)
+	return
+}
+
+func Test_ResourcePlan(t *testing.T) {
+	running := &DomainObject{UserPowerState: PowerState.On, CpuCount: 2, CpuCountMax: 8, MemoryCount: 2048, MemoryCountMax: 8192}
+	stopped := &DomainObject{UserPowerState: PowerState.Off, CpuCount: 2, CpuCountMax: 2, MemoryCount: 2048}
+
+	plan := DomainCpuConfig{CpuTopology: CpuTopology{CpuCount: 4, CpuMap: map[string]string{"0": "1"}, CpuShares: 512}}.Plan(running)
+	assert.Equal(t, []string{"cpu_count", "cpu_map", "cpu_shares"}, plan.Applied)
+	assert.False(t, plan.RebootRequired())
+
+	// Vcpus over the maximum of running domain are not hot-plugged, neither together with a new maximum
+	plan = DomainCpuConfig{CpuTopology: CpuTopology{CpuCount: 10}}.Plan(running)
+	assert.Equal(t, []string{"cpu_count"}, plan.PendingReboot)
+	plan = DomainCpuConfig{CpuTopology: CpuTopology{CpuCount: 4, CpuCountMax: 8}}.Plan(running)
+	assert.Empty(t, plan.Applied)
+	assert.Equal(t, []string{"cpu_count", "cpu_count_max"}, plan.PendingReboot)
+	plan = DomainCpuConfig{CpuTopology: CpuTopology{CpuCount: 4}}.Plan(&DomainObject{UserPowerState: PowerState.On, CpuCount: 2})
+	assert.Equal(t, []string{"cpu_count"}, plan.PendingReboot)
+	plan = DomainCpuConfig{CpuTopology: CpuTopology{CpuCount: 4, CpuCountMax: 8}}.Plan(stopped)
+	assert.Equal(t, []string{"cpu_count", "cpu_count_max"}, plan.Applied)
+
+	config := DomainCpuConfig{CpuTopology: CpuTopology{CpuCount: 1, CpuMode: "host-passthrough"}, Machine: "q35"}
+	plan = config.Plan(running)
+	assert.Equal(t, []string{"cpu_count", "cpu_mode", "machine"}, plan.PendingReboot)
+	assert.True(t, plan.RebootRequired())
+	plan = config.Plan(stopped)
+	assert.Equal(t, []string{"cpu_count", "cpu_mode", "machine"}, plan.Applied)
+	assert.False(t, plan.RebootRequired())
+
+	plan = DomainMemoryConfig{MemoryCount: 4096, MemoryLimit: 8192}.Plan(running)
+	assert.Equal(t, []string{"memory_count", "memory_limit"}, plan.Applied)
+	plan = DomainMemoryConfig{MemoryCount: 1024, MemoryMinGuarantee: 512}.Plan(running)
+	assert.Equal(t, []string{"memory_min_guarantee"}, plan.Applied)
+	assert.Equal(t, []string{"memory_count"}, plan.PendingReboot)
+
+	// Memory over the maximum, also unknown one, is not hot-plugged
+	plan = DomainMemoryConfig{MemoryCount: 16384}.Plan(running)
+	assert.Equal(t, []string{"memory_count"}, plan.PendingReboot)
+	plan = DomainMemoryConfig{MemoryCount: 4096}.Plan(&DomainObject{UserPowerState: PowerState.Suspended, MemoryCount: 2048})
+	assert.Equal(t, []string{"memory_count"}, plan.PendingReboot)
+
+	// Domain of unknown power state is not planned as running
+	plan = DomainCpuConfig{CpuTopology: CpuTopology{CpuMode: "host-passthrough"}}.Plan(&DomainObject{})
+	assert.Equal(t, []string{"cpu_mode"}, plan.Applied)
+	return
+}
+
+func Test_DomainUpdateCpuMemory(t *testing.T) {
+	server, client := newTestServerClient(t)
+	domain, _, err := client.Domain.MultiCreate(DomainMultiCreateConfig{
+		DomainCreateConfig: DomainCreateConfig{VerboseName: "vm", CpuCount: 2, CpuCountMax: 4, MemoryCount: 2048},
+		StartOn:            true,
+	})
+	require.NoError(t, err)
+
+	plan, _, err := client.Domain.UpdateCpu(domain, DomainCpuConfig{CpuTopology: CpuTopology{CpuCount: 4, CpuSockets: 1,
+		CpuCores: 4, CpuThreads: 1}})
+	require.NoError(t, err)
+	assert.Equal(t, 4, domain.CpuCount)
+	assert.Equal(t, 4, domain.CpuCountMax)
+	assert.Equal(t, []string{"cpu_count"}, plan.Applied)
+	assert.Equal(t, []string{"cpu_topology"}, plan.PendingReboot)
+	stored, _ := server.Get(veiltest.Domains, domain.Id)
+	assert.EqualValues(t, 4, stored["cpu_cores"])
+
+	plan, _, err = client.Domain.UpdateMemory(domain, DomainMemoryConfig{MemoryCount: 4096, MemoryShares: 2000})
+	require.NoError(t, err)
+	assert.Equal(t, 4096, domain.MemoryCount)
+	assert.Equal(t, []string{"memory_shares"}, plan.Applied)
+	assert.Equal(t, []string{"memory_count"}, plan.PendingReboot)
+
+	_, _, err = client.Domain.UpdateCpu(domain, DomainCpuConfig{CpuTopology: CpuTopology{CpuMode: "max"}})
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	_, _, err = client.Domain.UpdateMemory(domain, DomainMemoryConfig{MemoryCount: 1024, MemoryLimit: 512})
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	assert.Equal(t, 1, server.CountRequests("PUT", "/api/domains/*/cpu/"))
+	assert.Equal(t, 1, server.CountRequests("PUT", "/api/domains/*/memory/"))
+	return
+}
+
+func Test_DomainUpdateCpuMemoryLive(t *testing.T) {
+	client := liveClient(t)
+	liveEndpoints(t, client, "/api/domains/{id}/cpu/", "/api/domains/{id}/memory/")
+	domain := liveDomain(t, client)
+
+	// The domain is updated in place from the response
+	_, _, err := client.Domain.UpdateCpu(domain, DomainCpuConfig{CpuTopology: CpuTopology{CpuCount: 2}})
+	require.Nil(t, err)
+	assert.Equal(t, 2, domain.CpuCount)
+	_, _, err = client.Domain.UpdateMemory(domain, DomainMemoryConfig{MemoryCount: 512})
+	require.Nil(t, err)
+	assert.Equal(t, 512, domain.MemoryCount)
+	refreshed, _, err := client.Domain.Get(domain.Id)
+	require.Nil(t, err)
+	assert.Equal(t, 2, refreshed.CpuCount)
+	assert.Equal(t, 512, refreshed.MemoryCount)
+	return
+}
@@ -0,0 +1,151 @@
+package veil
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy describes how WebClient repeats requests failed with transient errors.
+// Safe methods (GET, HEAD, OPTIONS) are always retryable, other methods are retried
+// only when the request carries an idempotency key, see WithIdempotencyKey.
+type RetryPolicy struct {
+	// MaxAttempts total number of attempts including the first one, values below 2 disable retries
+	MaxAttempts int
+	// MinBackoff delay before the first retry, it is doubled on every next attempt
+	MinBackoff time.Duration
+	// MaxBackoff upper bound of the delay between attempts
+	MaxBackoff time.Duration
+	// Jitter fraction (0..1) of the delay which is randomized to spread retries of concurrent clients
+	Jitter float64
+	// RetryStatusCodes response status codes which are considered transient
+	RetryStatusCodes []int
+	// RetryNetworkErrors retry on connection resets, refused connections and timeouts
+	RetryNetworkErrors bool
+	// RespectRetryAfter wait for Retry-After response header instead of the computed delay
+	RespectRetryAfter bool
+}
+
+// DefaultRetryPolicy policy suitable for controller failover: 4 attempts within ~10 seconds
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:        4,
+		MinBackoff:         500 * time.Millisecond,
+		MaxBackoff:         10 * time.Second,
+		Jitter:             0.5,
+		RetryStatusCodes:   []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+		RetryNetworkErrors: true,
+		RespectRetryAfter:  true,
+	}
+}
+
+type idempotencyKeyCtx struct{}
+
+// WithIdempotencyKey marks requests executed with ctx as safe to repeat.
+// The key must also be sent to VeiL (see IdempotencyKeyBase) so that a repeated request does not create a duplicate.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtx{}, key)
+}
+
+// IdempotencyKeyFromContext returns the key set by WithIdempotencyKey
+func IdempotencyKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyCtx{}).(string)
+	return key
+}
+
+// canRetry reports whether the failed attempt number attempt may be repeated
+func (p *RetryPolicy) canRetry(ctx context.Context, method string, attempt int, res *http.Response, err error) bool {
+	if p == nil || attempt >= p.MaxAttempts || ctx.Err() != nil || !isIdempotent(ctx, method) {
+		return false
+	}
+	if err != nil {
+		return p.RetryNetworkErrors && isTransientNetworkError(err)
+	}
+	for _, code := range p.RetryStatusCodes {
+		if res.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// isIdempotent reports whether the request may be repeated: safe methods or requests with idempotency key
+func isIdempotent(ctx context.Context, method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return IdempotencyKeyFromContext(ctx) != ""
+	}
+}
+
+// backoff returns the delay before the next attempt after the failed attempt number attempt
+func (p *RetryPolicy) backoff(attempt int, res *http.Response) time.Duration {
+	if p.RespectRetryAfter && res != nil {
+		if delay, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+			return delay
+		}
+	}
+	delay := float64(p.MinBackoff) * math.Pow(2, float64(attempt-1))
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		delay -= delay * math.Min(p.Jitter, 1) * jitterFloat64()
+	}
+	return time.Duration(delay)
+}
+
+// parseRetryAfter parses Retry-After header value in delay-seconds or HTTP-date form
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+// isTransientNetworkError reports whether err is a connection level failure worth repeating
+func isTransientNetworkError(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNABORTED) || errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+var (
+	jitterMu   sync.Mutex
+	jitterRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+func jitterFloat64() float64 {
+	jitterMu.Lock()
+	defer jitterMu.Unlock()
+	return jitterRand.Float64()
+}
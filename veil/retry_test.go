@@ -0,0 +1,140 @@
+package veil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/jsc-masshtab/veil-api-client-go/veil/veiltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testRetryPolicy() *RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.MinBackoff = time.Millisecond
+	policy.MaxBackoff = 5 * time.Millisecond
+	return policy
+}
+
+func Test_RetryTransientStatus(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"id": "node"}`)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "token", false)
+	client.RetryPolicy = testRetryPolicy()
+
+	node, _, err := client.Node.Get("node")
+	require.Nil(t, err)
+	assert.Equal(t, "node", node.Id)
+	assert.EqualValues(t, 3, atomic.LoadInt32(&attempts))
+
+	return
+}
+
+func Test_RetryPostRequiresIdempotencyKey(t *testing.T) {
+	var attempts int32
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := new(DomainCreateConfig)
+		_ = json.NewDecoder(r.Body).Decode(body)
+		keys = append(keys, body.IdempotencyKey)
+		if atomic.AddInt32(&attempts, 1)%2 == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		fmt.Fprint(w, `{"id": "domain"}`)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "token", false)
+	client.RetryPolicy = testRetryPolicy()
+
+	_, _, err := client.Vdisk.Extend("vdisk", 1)
+	assert.True(t, IsServerError(err))
+	assert.EqualValues(t, 1, atomic.LoadInt32(&attempts))
+
+	keys = nil
+	atomic.StoreInt32(&attempts, 0)
+	domain, _, err := client.Domain.Create(DomainCreateConfig{VerboseName: "domain"})
+	require.Nil(t, err)
+	assert.Equal(t, "domain", domain.Id)
+	require.Len(t, keys, 2)
+	assert.NotEmpty(t, keys[0])
+	assert.Equal(t, keys[0], keys[1])
+
+	return
+}
+
+func Test_RetryStartOperations(t *testing.T) {
+	server, client := newTestServerClient(t)
+	client.RetryPolicy = testRetryPolicy()
+	keys := func(pattern string) []string {
+		var keys []string
+		for _, req := range server.Requests() {
+			if ok, _ := path.Match(pattern, req.Path); ok && req.Method == "POST" {
+				body := new(IdempotencyKeyBase)
+				_ = json.Unmarshal(req.Body, body)
+				keys = append(keys, body.IdempotencyKey)
+			}
+		}
+		return keys
+	}
+	source, _, err := client.Domain.Create(DomainCreateConfig{VerboseName: "source"})
+	require.Nil(t, err)
+
+	server.Inject(veiltest.Fault{Method: "POST", Path: "/api/domains/*/clone/", Status: http.StatusBadGateway, Times: 1})
+	clone, _, err := client.Domain.Clone(source.Id, DomainCloneConfig{VerboseName: "clone"})
+	require.Nil(t, err)
+	assert.Equal(t, "clone", clone.VerboseName)
+	cloneKeys := keys("/api/domains/*/clone/")
+	require.Len(t, cloneKeys, 2)
+	assert.NotEmpty(t, cloneKeys[0])
+	assert.Equal(t, cloneKeys[0], cloneKeys[1])
+
+	server.Inject(veiltest.Fault{Method: "POST", Path: "/api/vdisks/", Status: http.StatusServiceUnavailable, Times: 1})
+	vdisk, _, err := client.Vdisk.Create(&VdiskCreate{VerboseName: "data", Size: 1}, true)
+	require.Nil(t, err)
+	assert.Equal(t, Status.Active, vdisk.Status)
+	vdiskKeys := keys("/api/vdisks/")
+	require.Len(t, vdiskKeys, 2)
+	assert.NotEmpty(t, vdiskKeys[0])
+	assert.Equal(t, vdiskKeys[0], vdiskKeys[1])
+	assert.Len(t, server.List(veiltest.Vdisks), 1)
+
+	return
+}
+
+func Test_RetryAfter(t *testing.T) {
+	delay, ok := parseRetryAfter("3")
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Second, delay)
+
+	delay, ok = parseRetryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+	assert.InDelta(t, float64(time.Hour), float64(delay), float64(2*time.Second))
+
+	_, ok = parseRetryAfter("soon")
+	assert.False(t, ok)
+
+	policy := testRetryPolicy()
+	res := &http.Response{Header: http.Header{"Retry-After": []string{"2"}}}
+	assert.Equal(t, 2*time.Second, policy.backoff(1, res))
+	assert.LessOrEqual(t, policy.backoff(10, nil), policy.MaxBackoff)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.False(t, policy.canRetry(ctx, "GET", 1, &http.Response{StatusCode: 503}, nil))
+
+	return
+}
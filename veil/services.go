@@ -0,0 +1,391 @@
+package veil
+
+import (
+	"context"
+	"net/http"
+)
+
+// DomainAPI operations with domains implemented by DomainService. Code depending on service interfaces
+// instead of WebClient fields can be tested with stubs, see package veilmock
+type DomainAPI interface {
+	List() (*DomainsResponse, *http.Response, error)
+	ListContext(ctx context.Context) (*DomainsResponse, *http.Response, error)
+	ListParams(queryParams map[string]string) (*DomainsResponse, *http.Response, error)
+	ListParamsContext(ctx context.Context, queryParams map[string]string) (*DomainsResponse, *http.Response, error)
+	Create(config DomainCreateConfig) (*DomainObject, *http.Response, error)
+	CreateContext(ctx context.Context, config DomainCreateConfig) (*DomainObject, *http.Response, error)
+	MultiCreate(config DomainMultiCreateConfig) (*DomainObject, *http.Response, error)
+	MultiCreateContext(ctx context.Context, config DomainMultiCreateConfig) (*DomainObject, *http.Response, error)
+	StartMultiCreate(ctx context.Context, config DomainMultiCreateConfig) (*DomainOperation, *http.Response, error)
+	ListFiltered(filter DomainFilter) (*DomainsResponse, *http.Response, error)
+	ListFilteredContext(ctx context.Context, filter DomainFilter) (*DomainsResponse, *http.Response, error)
+	Iter(ctx context.Context, filter ListFilter, opts *PageOptions) *DomainIterator
+	ListAll(ctx context.Context, filter ListFilter) ([]DomainObjectsList, error)
+	Get(Id string) (*DomainObject, *http.Response, error)
+	GetContext(ctx context.Context, Id string) (*DomainObject, *http.Response, error)
+	Update(Id string, config DomainUpdateConfig) (*DomainObject, *http.Response, error)
+	UpdateContext(ctx context.Context, Id string, config DomainUpdateConfig) (*DomainObject, *http.Response, error)
+	Start(domain *DomainObject) (*DomainObject, *http.Response, error)
+	StartContext(ctx context.Context, domain *DomainObject) (*DomainObject, *http.Response, error)
+	Suspend(domain *DomainObject) (*DomainObject, *http.Response, error)
+	SuspendContext(ctx context.Context, domain *DomainObject) (*DomainObject, *http.Response, error)
+	Resume(domain *DomainObject) (*DomainObject, *http.Response, error)
+	ResumeContext(ctx context.Context, domain *DomainObject) (*DomainObject, *http.Response, error)
+	Shutdown(domain *DomainObject, force bool) (*DomainObject, *http.Response, error)
+	ShutdownContext(ctx context.Context, domain *DomainObject, force bool) (*DomainObject, *http.Response, error)
+	Reboot(domain *DomainObject, force bool) (*DomainObject, *http.Response, error)
+	RebootContext(ctx context.Context, domain *DomainObject, force bool) (*DomainObject, *http.Response, error)
+	Template(domain *DomainObject, template bool) (*DomainObject, *http.Response, error)
+	TemplateContext(ctx context.Context, domain *DomainObject, template bool) (*DomainObject, *http.Response, error)
+	Clone(Id string, config DomainCloneConfig) (*DomainObject, *http.Response, error)
+	CloneContext(ctx context.Context, Id string, config DomainCloneConfig) (*DomainObject, *http.Response, error)
+	StartClone(ctx context.Context, Id string, config DomainCloneConfig) (*DomainOperation, *http.Response, error)
+	ResumeOperation(state OperationState) (*DomainOperation, error)
+	CloudInit(domain *DomainObject, config CloudInitConf) (*DomainObject, *http.Response, error)
+	CloudInitContext(ctx context.Context, domain *DomainObject, config CloudInitConf) (*DomainObject, *http.Response, error)
+	Remove(domainID string, full bool, force bool) (bool, *http.Response, error)
+	RemoveContext(ctx context.Context, domainID string, full bool, force bool) (bool, *http.Response, error)
+	ListSnapshots(Id string) (*DomainSnapshotsResponse, *http.Response, error)
+	ListSnapshotsContext(ctx context.Context, Id string) (*DomainSnapshotsResponse, *http.Response, error)
+	GetSnapshot(Id string, snapshotId string) (*DomainSnapshot, *http.Response, error)
+	GetSnapshotContext(ctx context.Context, Id string, snapshotId string) (*DomainSnapshot, *http.Response, error)
+	CreateSnapshot(Id string, config SnapshotCreateConfig) (*DomainSnapshot, *http.Response, error)
+	CreateSnapshotContext(ctx context.Context, Id string, config SnapshotCreateConfig) (*DomainSnapshot, *http.Response, error)
+	StartCreateSnapshot(ctx context.Context, Id string, config SnapshotCreateConfig) (*SnapshotOperation, *http.Response, error)
+	ResumeSnapshotOperation(state OperationState) (*SnapshotOperation, error)
+	RevertSnapshot(Id string, snapshotId string) (*DomainObject, *http.Response, error)
+	RevertSnapshotContext(ctx context.Context, Id string, snapshotId string) (*DomainObject, *http.Response, error)
+	StartRevertSnapshot(ctx context.Context, Id string, snapshotId string) (*DomainOperation, *http.Response, error)
+	RemoveSnapshot(Id string, snapshotId string) (*DomainObject, *http.Response, error)
+	RemoveSnapshotContext(ctx context.Context, Id string, snapshotId string) (*DomainObject, *http.Response, error)
+	StartRemoveSnapshot(ctx context.Context, Id string, snapshotId string) (*DomainOperation, *http.Response, error)
+	Migrate(Id string, config DomainMigrateConfig) (*DomainObject, *http.Response, error)
+	MigrateContext(ctx context.Context, Id string, config DomainMigrateConfig) (*DomainObject, *http.Response, error)
+	StartMigrate(ctx context.Context, Id string, config DomainMigrateConfig) (*DomainOperation, *http.Response, error)
+	AttachVdisk(Id string, config VdiskAttach) (*DomainObject, *http.Response, error)
+	AttachVdiskContext(ctx context.Context, Id string, config VdiskAttach) (*DomainObject, *http.Response, error)
+	DetachVdisk(Id string, vdiskId string) (*DomainObject, *http.Response, error)
+	DetachVdiskContext(ctx context.Context, Id string, vdiskId string) (*DomainObject, *http.Response, error)
+	CreateAttachVdisk(Id string, config VdiskCreateAttach) (*VdiskObject, *http.Response, error)
+	CreateAttachVdiskContext(ctx context.Context, Id string, config VdiskCreateAttach) (*VdiskObject, *http.Response, error)
+	StartCreateAttachVdisk(ctx context.Context, Id string, config VdiskCreateAttach) (*VdiskOperation, *http.Response, error)
+	InsertIso(Id string, config IsoAttach) (*DomainObject, *http.Response, error)
+	InsertIsoContext(ctx context.Context, Id string, config IsoAttach) (*DomainObject, *http.Response, error)
+	EjectIso(Id string, cdromId string) (*DomainObject, *http.Response, error)
+	EjectIsoContext(ctx context.Context, Id string, cdromId string) (*DomainObject, *http.Response, error)
+	AddInterface(Id string, config VMachineInfSoftCreate) (*VMachineInfObject, *http.Response, error)
+	AddInterfaceContext(ctx context.Context, Id string, config VMachineInfSoftCreate) (*VMachineInfObject, *http.Response, error)
+	RemoveInterface(Id string, vmachineInfId string) (*DomainObject, *http.Response, error)
+	RemoveInterfaceContext(ctx context.Context, Id string, vmachineInfId string) (*DomainObject, *http.Response, error)
+	RelinkInterface(Id string, vmachineInfId string, vnetworkId string) (*VMachineInfObject, *http.Response, error)
+	RelinkInterfaceContext(ctx context.Context, Id string, vmachineInfId string, vnetworkId string) (*VMachineInfObject, *http.Response, error)
+	ListVdisks(ctx context.Context, Id string) ([]VdiskObjectsList, error)
+	ListCdroms(ctx context.Context, Id string) ([]CdromObject, error)
+	ListInterfaces(ctx context.Context, Id string) ([]VMachineInfObjectsList, error)
+	UpdateCpu(domain *DomainObject, config DomainCpuConfig) (*ResourcePlan, *http.Response, error)
+	UpdateCpuContext(ctx context.Context, domain *DomainObject, config DomainCpuConfig) (*ResourcePlan, *http.Response, error)
+	UpdateMemory(domain *DomainObject, config DomainMemoryConfig) (*ResourcePlan, *http.Response, error)
+	UpdateMemoryContext(ctx context.Context, domain *DomainObject, config DomainMemoryConfig) (*ResourcePlan, *http.Response, error)
+	RemoteAccess(domain *DomainObject, enabled bool) (*DomainObject, *http.Response, error)
+	RemoteAccessContext(ctx context.Context, domain *DomainObject, enabled bool) (*DomainObject, *http.Response, error)
+	Console(Id string, protocol string) (*ConsoleConnection, *http.Response, error)
+	ConsoleContext(ctx context.Context, Id string, protocol string) (*ConsoleConnection, *http.Response, error)
+}
+
+// NodeAPI operations with nodes implemented by NodeService
+type NodeAPI interface {
+	List() (*NodesResponse, *http.Response, error)
+	ListContext(ctx context.Context) (*NodesResponse, *http.Response, error)
+	ListParams(queryParams map[string]string) (*NodesResponse, *http.Response, error)
+	ListParamsContext(ctx context.Context, queryParams map[string]string) (*NodesResponse, *http.Response, error)
+	ListFiltered(filter NodeFilter) (*NodesResponse, *http.Response, error)
+	ListFilteredContext(ctx context.Context, filter NodeFilter) (*NodesResponse, *http.Response, error)
+	Iter(ctx context.Context, filter ListFilter, opts *PageOptions) *NodeIterator
+	ListAll(ctx context.Context, filter ListFilter) ([]NodeObjectsList, error)
+	Get(Id string) (*NodeObject, *http.Response, error)
+	GetContext(ctx context.Context, Id string) (*NodeObject, *http.Response, error)
+}
+
+// ClusterAPI operations with clusters implemented by ClusterService
+type ClusterAPI interface {
+	List() (*ClustersResponse, *http.Response, error)
+	ListContext(ctx context.Context) (*ClustersResponse, *http.Response, error)
+	ListParams(queryParams map[string]string) (*ClustersResponse, *http.Response, error)
+	ListParamsContext(ctx context.Context, queryParams map[string]string) (*ClustersResponse, *http.Response, error)
+	ListFiltered(filter ClusterFilter) (*ClustersResponse, *http.Response, error)
+	ListFilteredContext(ctx context.Context, filter ClusterFilter) (*ClustersResponse, *http.Response, error)
+	Iter(ctx context.Context, filter ListFilter, opts *PageOptions) *ClusterIterator
+	ListAll(ctx context.Context, filter ListFilter) ([]ClusterObjectsList, error)
+	Get(Id string) (*ClusterObject, *http.Response, error)
+	GetContext(ctx context.Context, Id string) (*ClusterObject, *http.Response, error)
+}
+
+// DataCenterAPI operations with datacenters implemented by DataCenterService
+type DataCenterAPI interface {
+	List() (*DataCentersResponse, *http.Response, error)
+	ListContext(ctx context.Context) (*DataCentersResponse, *http.Response, error)
+	ListParams(queryParams map[string]string) (*DataCentersResponse, *http.Response, error)
+	ListParamsContext(ctx context.Context, queryParams map[string]string) (*DataCentersResponse, *http.Response, error)
+	ListFiltered(filter DataCenterFilter) (*DataCentersResponse, *http.Response, error)
+	ListFilteredContext(ctx context.Context, filter DataCenterFilter) (*DataCentersResponse, *http.Response, error)
+	Iter(ctx context.Context, filter ListFilter, opts *PageOptions) *DataCenterIterator
+	ListAll(ctx context.Context, filter ListFilter) ([]DataCenterObjectsList, error)
+	Get(Id string) (*DataCenterObject, *http.Response, error)
+	GetContext(ctx context.Context, Id string) (*DataCenterObject, *http.Response, error)
+}
+
+// DataPoolAPI operations with datapools implemented by DataPoolService
+type DataPoolAPI interface {
+	List() (*DataPoolsResponse, *http.Response, error)
+	ListContext(ctx context.Context) (*DataPoolsResponse, *http.Response, error)
+	ListParams(queryParams map[string]string) (*DataPoolsResponse, *http.Response, error)
+	ListParamsContext(ctx context.Context, queryParams map[string]string) (*DataPoolsResponse, *http.Response, error)
+	ListFiltered(filter DataPoolFilter) (*DataPoolsResponse, *http.Response, error)
+	ListFilteredContext(ctx context.Context, filter DataPoolFilter) (*DataPoolsResponse, *http.Response, error)
+	Iter(ctx context.Context, filter ListFilter, opts *PageOptions) *DataPoolIterator
+	ListAll(ctx context.Context, filter ListFilter) ([]DataPoolObjectsList, error)
+	Get(Id string) (*DataPoolObject, *http.Response, error)
+	GetContext(ctx context.Context, Id string) (*DataPoolObject, *http.Response, error)
+}
+
+// VdiskAPI operations with virtual disks implemented by VdiskService
+type VdiskAPI interface {
+	List() (*VdisksResponse, *http.Response, error)
+	ListContext(ctx context.Context) (*VdisksResponse, *http.Response, error)
+	ListParams(queryParams map[string]string) (*VdisksResponse, *http.Response, error)
+	ListParamsContext(ctx context.Context, queryParams map[string]string) (*VdisksResponse, *http.Response, error)
+	ListFiltered(filter VdiskFilter) (*VdisksResponse, *http.Response, error)
+	ListFilteredContext(ctx context.Context, filter VdiskFilter) (*VdisksResponse, *http.Response, error)
+	Iter(ctx context.Context, filter ListFilter, opts *PageOptions) *VdiskIterator
+	ListAll(ctx context.Context, filter ListFilter) ([]VdiskObjectsList, error)
+	Get(Id string) (*VdiskObject, *http.Response, error)
+	GetContext(ctx context.Context, Id string) (*VdiskObject, *http.Response, error)
+	Create(config *VdiskCreate, asynced bool) (*VdiskObject, *http.Response, error)
+	CreateContext(ctx context.Context, config *VdiskCreate, asynced bool) (*VdiskObject, *http.Response, error)
+	StartCreate(ctx context.Context, config *VdiskCreate) (*VdiskOperation, *http.Response, error)
+	ResumeOperation(state OperationState) (*VdiskOperation, error)
+	Update(Id string, description string) (*VdiskObject, *http.Response, error)
+	UpdateContext(ctx context.Context, Id string, description string) (*VdiskObject, *http.Response, error)
+	Extend(Id string, size float64) (*VdiskObject, *http.Response, error)
+	ExtendContext(ctx context.Context, Id string, size float64) (*VdiskObject, *http.Response, error)
+	Consolidate(Id string) (*VdiskObject, *http.Response, error)
+	ConsolidateContext(ctx context.Context, Id string) (*VdiskObject, *http.Response, error)
+	StartConsolidate(ctx context.Context, Id string) (*VdiskOperation, *http.Response, error)
+	Remove(Id string) (bool, *http.Response, error)
+	RemoveContext(ctx context.Context, Id string) (bool, *http.Response, error)
+}
+
+// IsoAPI operations with ISO images implemented by IsoService
+type IsoAPI interface {
+	List() (*IsosResponse, *http.Response, error)
+	ListContext(ctx context.Context) (*IsosResponse, *http.Response, error)
+	ListParams(queryParams map[string]string) (*IsosResponse, *http.Response, error)
+	ListParamsContext(ctx context.Context, queryParams map[string]string) (*IsosResponse, *http.Response, error)
+	ListFiltered(filter IsoFilter) (*IsosResponse, *http.Response, error)
+	ListFilteredContext(ctx context.Context, filter IsoFilter) (*IsosResponse, *http.Response, error)
+	Iter(ctx context.Context, filter ListFilter, opts *PageOptions) *IsoIterator
+	ListAll(ctx context.Context, filter ListFilter) ([]IsoObjectsList, error)
+	Get(Id string) (*IsoObject, *http.Response, error)
+	GetContext(ctx context.Context, Id string) (*IsoObject, *http.Response, error)
+	Create(DataPoolId string, FilenameUrl string, timeout int64) (*IsoObject, error)
+	CreateContext(ctx context.Context, DataPoolId string, FilenameUrl string, timeout int64) (*IsoObject, error)
+	Download(entity *IsoObject) (*IsoObject, *http.Response, error)
+	DownloadContext(ctx context.Context, entity *IsoObject) (*IsoObject, *http.Response, error)
+	Remove(Id string) (bool, *http.Response, error)
+	RemoveContext(ctx context.Context, Id string) (bool, *http.Response, error)
+}
+
+// LibraryAPI operations with library files implemented by LibraryService
+type LibraryAPI interface {
+	List() (*LibraryResponse, *http.Response, error)
+	ListContext(ctx context.Context) (*LibraryResponse, *http.Response, error)
+	ListParams(queryParams map[string]string) (*LibraryResponse, *http.Response, error)
+	ListParamsContext(ctx context.Context, queryParams map[string]string) (*LibraryResponse, *http.Response, error)
+	ListFiltered(filter LibraryFilter) (*LibraryResponse, *http.Response, error)
+	ListFilteredContext(ctx context.Context, filter LibraryFilter) (*LibraryResponse, *http.Response, error)
+	Iter(ctx context.Context, filter ListFilter, opts *PageOptions) *LibraryIterator
+	ListAll(ctx context.Context, filter ListFilter) ([]LibraryObjectsList, error)
+	Get(Id string) (*LibraryObject, *http.Response, error)
+	GetContext(ctx context.Context, Id string) (*LibraryObject, *http.Response, error)
+	Import(Id string, config FileImportConfig) (*VdiskObject, *http.Response, error)
+	ImportContext(ctx context.Context, Id string, config FileImportConfig) (*VdiskObject, *http.Response, error)
+	StartImport(ctx context.Context, Id string, config FileImportConfig) (*VdiskOperation, *http.Response, error)
+	ResumeOperation(state OperationState) (*VdiskOperation, error)
+	Create(DataPoolId string, FilenameUrl string, timeout int64) (*LibraryObject, error)
+	CreateContext(ctx context.Context, DataPoolId string, FilenameUrl string, timeout int64) (*LibraryObject, error)
+	Download(entity *LibraryObject) (*LibraryObject, *http.Response, error)
+	DownloadContext(ctx context.Context, entity *LibraryObject) (*LibraryObject, *http.Response, error)
+	Remove(Id string) (bool, *http.Response, error)
+	RemoveContext(ctx context.Context, Id string) (bool, *http.Response, error)
+}
+
+// TaskAPI operations with tasks implemented by TaskService
+type TaskAPI interface {
+	List() (*TasksResponse, *http.Response, error)
+	ListContext(ctx context.Context) (*TasksResponse, *http.Response, error)
+	ListParams(queryParams map[string]string) (*TasksResponse, *http.Response, error)
+	ListParamsContext(ctx context.Context, queryParams map[string]string) (*TasksResponse, *http.Response, error)
+	ListFiltered(filter TaskFilter) (*TasksResponse, *http.Response, error)
+	ListFilteredContext(ctx context.Context, filter TaskFilter) (*TasksResponse, *http.Response, error)
+	Subtasks(Id string) (*TasksResponse, *http.Response, error)
+	SubtasksContext(ctx context.Context, Id string) (*TasksResponse, *http.Response, error)
+	Cancel(Id string) (bool, *http.Response, error)
+	CancelContext(ctx context.Context, Id string) (bool, *http.Response, error)
+	Summary(Id string) (*TaskSummary, *http.Response, error)
+	SummaryContext(ctx context.Context, Id string) (*TaskSummary, *http.Response, error)
+	Iter(ctx context.Context, filter ListFilter, opts *PageOptions) *TaskIterator
+	ListAll(ctx context.Context, filter ListFilter) ([]TaskObjectsList, error)
+	Get(Id string) (*TaskObject, *http.Response, error)
+	GetContext(ctx context.Context, Id string) (*TaskObject, *http.Response, error)
+	Response(Id string, object interface{}) (*http.Response, error)
+	ResponseContext(ctx context.Context, Id string, object interface{}) (*http.Response, error)
+	Wait(ctx context.Context, Id string, opts *TaskWaitOptions) (*TaskObject, error)
+}
+
+// EventAPI operations with events implemented by EventService
+type EventAPI interface {
+	List() (*EventsResponse, *http.Response, error)
+	ListContext(ctx context.Context) (*EventsResponse, *http.Response, error)
+	ListFiltered(filter EventFilter) (*EventsResponse, *http.Response, error)
+	ListFilteredContext(ctx context.Context, filter EventFilter) (*EventsResponse, *http.Response, error)
+	Iter(ctx context.Context, filter ListFilter, opts *PageOptions) *EventIterator
+	ListAll(ctx context.Context, filter ListFilter) ([]EventObjectsList, error)
+	Get(Id string) (*EventObject, *http.Response, error)
+	GetContext(ctx context.Context, Id string) (*EventObject, *http.Response, error)
+}
+
+// UserAPI operations with users implemented by UserService
+type UserAPI interface {
+	List() (*UsersResponse, *http.Response, error)
+	ListContext(ctx context.Context) (*UsersResponse, *http.Response, error)
+	ListParams(queryParams map[string]string) (*UsersResponse, *http.Response, error)
+	ListParamsContext(ctx context.Context, queryParams map[string]string) (*UsersResponse, *http.Response, error)
+	ListFiltered(filter UserFilter) (*UsersResponse, *http.Response, error)
+	ListFilteredContext(ctx context.Context, filter UserFilter) (*UsersResponse, *http.Response, error)
+	Iter(ctx context.Context, filter ListFilter, opts *PageOptions) *UserIterator
+	ListAll(ctx context.Context, filter ListFilter) ([]UserObjectsList, error)
+	Get(Id int) (*UserObject, *http.Response, error)
+	GetContext(ctx context.Context, Id int) (*UserObject, *http.Response, error)
+}
+
+// VnetAPI operations with virtual networks implemented by VnetService
+type VnetAPI interface {
+	List() (*VnetsResponse, *http.Response, error)
+	ListContext(ctx context.Context) (*VnetsResponse, *http.Response, error)
+	ListParams(queryParams map[string]string) (*VnetsResponse, *http.Response, error)
+	ListParamsContext(ctx context.Context, queryParams map[string]string) (*VnetsResponse, *http.Response, error)
+	ListFiltered(filter VnetFilter) (*VnetsResponse, *http.Response, error)
+	ListFilteredContext(ctx context.Context, filter VnetFilter) (*VnetsResponse, *http.Response, error)
+	Iter(ctx context.Context, filter ListFilter, opts *PageOptions) *VnetIterator
+	ListAll(ctx context.Context, filter ListFilter) ([]VnetObjectsList, error)
+	Get(Id string) (*VnetObject, *http.Response, error)
+	GetContext(ctx context.Context, Id string) (*VnetObject, *http.Response, error)
+}
+
+// VMachineInfAPI operations with virtual machine interfaces implemented by VMachineInfService
+type VMachineInfAPI interface {
+	List() (*VMachinesResponse, *http.Response, error)
+	ListContext(ctx context.Context) (*VMachinesResponse, *http.Response, error)
+	ListParams(queryParams map[string]string) (*VMachinesResponse, *http.Response, error)
+	ListParamsContext(ctx context.Context, queryParams map[string]string) (*VMachinesResponse, *http.Response, error)
+	ListFiltered(filter VMachineInfFilter) (*VMachinesResponse, *http.Response, error)
+	ListFilteredContext(ctx context.Context, filter VMachineInfFilter) (*VMachinesResponse, *http.Response, error)
+	Iter(ctx context.Context, filter ListFilter, opts *PageOptions) *VMachineInfIterator
+	ListAll(ctx context.Context, filter ListFilter) ([]VMachineInfObjectsList, error)
+	Get(Id string) (*VMachineInfObject, *http.Response, error)
+	GetContext(ctx context.Context, Id string) (*VMachineInfObject, *http.Response, error)
+}
+
+// SwaggerAPI operations with API schema implemented by SwaggerService
+type SwaggerAPI interface {
+	Get() (*Swagger, *http.Response, error)
+	GetContext(ctx context.Context) (*Swagger, *http.Response, error)
+}
+
+var (
+	_ DomainAPI      = (*DomainService)(nil)
+	_ NodeAPI        = (*NodeService)(nil)
+	_ ClusterAPI     = (*ClusterService)(nil)
+	_ DataCenterAPI  = (*DataCenterService)(nil)
+	_ DataPoolAPI    = (*DataPoolService)(nil)
+	_ VdiskAPI       = (*VdiskService)(nil)
+	_ IsoAPI         = (*IsoService)(nil)
+	_ LibraryAPI     = (*LibraryService)(nil)
+	_ TaskAPI        = (*TaskService)(nil)
+	_ EventAPI       = (*EventService)(nil)
+	_ UserAPI        = (*UserService)(nil)
+	_ VnetAPI        = (*VnetService)(nil)
+	_ VMachineInfAPI = (*VMachineInfService)(nil)
+	_ SwaggerAPI     = (*SwaggerService)(nil)
+)
+
+// NewDomainService creates DomainService over client, services over a stub of Client run
+// the real logic of async operations on stubbed responses
+func NewDomainService(client Client) *DomainService {
+	return &DomainService{client}
+}
+
+// NewNodeService creates NodeService over client
+func NewNodeService(client Client) *NodeService {
+	return &NodeService{client}
+}
+
+// NewClusterService creates ClusterService over client
+func NewClusterService(client Client) *ClusterService {
+	return &ClusterService{client}
+}
+
+// NewDataCenterService creates DataCenterService over client
+func NewDataCenterService(client Client) *DataCenterService {
+	return &DataCenterService{client}
+}
+
+// NewDataPoolService creates DataPoolService over client
+func NewDataPoolService(client Client) *DataPoolService {
+	return &DataPoolService{client}
+}
+
+// NewVdiskService creates VdiskService over client
+func NewVdiskService(client Client) *VdiskService {
+	return &VdiskService{client}
+}
+
+// NewIsoService creates IsoService over client
+func NewIsoService(client Client) *IsoService {
+	return &IsoService{client}
+}
+
+// NewLibraryService creates LibraryService over client
+func NewLibraryService(client Client) *LibraryService {
+	return &LibraryService{client}
+}
+
+// NewTaskService creates TaskService over client
+func NewTaskService(client Client) *TaskService {
+	return &TaskService{client}
+}
+
+// NewEventService creates EventService over client
+func NewEventService(client Client) *EventService {
+	return &EventService{client}
+}
+
+// NewUserService creates UserService over client
+func NewUserService(client Client) *UserService {
+	return &UserService{client}
+}
+
+// NewVnetService creates VnetService over client
+func NewVnetService(client Client) *VnetService {
+	return &VnetService{client}
+}
+
+// NewVMachineInfService creates VMachineInfService over client
+func NewVMachineInfService(client Client) *VMachineInfService {
+	return &VMachineInfService{client}
+}
+
+// NewSwaggerService creates SwaggerService over client
+func NewSwaggerService(client Client) *SwaggerService {
+	return &SwaggerService{client}
+}
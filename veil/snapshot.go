@@ -0,0 +1,152 @@
+package veil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// DomainSnapshot saved state of domain disks and, optionally, of its memory
+type DomainSnapshot struct {
+	Id          string          `json:"id,omitempty"`
+	VerboseName string          `json:"verbose_name,omitempty"`
+	Description string          `json:"description,omitempty"`
+	Domain      NameDomain      `json:"domain,omitempty"`
+	Parent      string          `json:"parent,omitempty"`
+	Current     bool            `json:"current,omitempty"`
+	Memory      bool            `json:"memory,omitempty"`
+	Status      string          `json:"status,omitempty"`
+	Vdisks      []VdiskSnapshot `json:"vdisks,omitempty"`
+	Created     string          `json:"created,omitempty"`
+}
+
+type DomainSnapshotsResponse struct {
+	BaseListResponse
+	Results []DomainSnapshot `json:"results,omitempty"`
+}
+
+// SnapshotCreateConfig parameters of DomainService.CreateSnapshot
+type SnapshotCreateConfig struct {
+	VerboseName string `json:"verbose_name"`
+	Description string `json:"description,omitempty"`
+	// Memory saves the state of running domain memory, revert then restores the domain running
+	Memory bool `json:"memory,omitempty"`
+}
+
+type snapshotRequest struct {
+	Snapshot string `json:"snapshot"`
+}
+
+func (d *DomainService) ListSnapshots(Id string) (*DomainSnapshotsResponse, *http.Response, error) {
+	return d.ListSnapshotsContext(context.Background(), Id)
+}
+
+func (d *DomainService) ListSnapshotsContext(ctx context.Context, Id string) (*DomainSnapshotsResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.ListSnapshots")
+	response := new(DomainSnapshotsResponse)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", fmt.Sprint(baseDomainUrl, Id, "/snapshots/"), []byte{}, response)
+	return response, res, err
+}
+
+func (d *DomainService) GetSnapshot(Id string, snapshotId string) (*DomainSnapshot, *http.Response, error) {
+	return d.GetSnapshotContext(context.Background(), Id, snapshotId)
+}
+
+func (d *DomainService) GetSnapshotContext(ctx context.Context, Id string, snapshotId string) (*DomainSnapshot, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.GetSnapshot")
+	snapshot := new(DomainSnapshot)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", fmt.Sprint(baseDomainUrl, Id, "/snapshots/", snapshotId, "/"), []byte{}, snapshot)
+	return snapshot, res, err
+}
+
+func (d *DomainService) CreateSnapshot(Id string, config SnapshotCreateConfig) (*DomainSnapshot, *http.Response, error) {
+	return d.CreateSnapshotContext(context.Background(), Id, config)
+}
+
+func (d *DomainService) CreateSnapshotContext(ctx context.Context, Id string, config SnapshotCreateConfig) (*DomainSnapshot, *http.Response, error) {
+	ctx, finish := startOperation(ctx, d.client, "DomainService.CreateSnapshot", Id)
+	op, res, err := d.StartCreateSnapshot(ctx, Id, config)
+	if err != nil {
+		finish(err)
+		return new(DomainSnapshot), res, err
+	}
+	snapshot, err := op.Wait(ctx)
+	finish(err)
+	return snapshot, op.response(res), err
+}
+
+// StartCreateSnapshot starts snapshot creation and returns without waiting for the task
+func (d *DomainService) StartCreateSnapshot(ctx context.Context, Id string, config SnapshotCreateConfig) (*SnapshotOperation, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.StartCreateSnapshot")
+	b, _ := json.Marshal(config)
+	asyncResp := new(AsyncEntityResponse)
+	res, err := d.client.ExecuteRequestContext(ctx, "POST", fmt.Sprint(baseDomainUrl, Id, "/create-snapshot/?async=1"), b, asyncResp)
+	if err != nil {
+		return nil, res, err
+	}
+	op, err := newSnapshotOperation(d.client, OperationState{OperationKind.SnapshotCreate, asyncResp.Task.Id, Id})
+	return op, res, err
+}
+
+// ResumeSnapshotOperation restores the handle of an operation started by StartCreateSnapshot
+func (d *DomainService) ResumeSnapshotOperation(state OperationState) (*SnapshotOperation, error) {
+	return newSnapshotOperation(d.client, state)
+}
+
+// RevertSnapshot returns the domain to the snapshot, the domain is powered off unless the snapshot has memory
+func (d *DomainService) RevertSnapshot(Id string, snapshotId string) (*DomainObject, *http.Response, error) {
+	return d.RevertSnapshotContext(context.Background(), Id, snapshotId)
+}
+
+func (d *DomainService) RevertSnapshotContext(ctx context.Context, Id string, snapshotId string) (*DomainObject, *http.Response, error) {
+	ctx, finish := startOperation(ctx, d.client, "DomainService.RevertSnapshot", Id)
+	op, res, err := d.StartRevertSnapshot(ctx, Id, snapshotId)
+	if err != nil {
+		finish(err)
+		return new(DomainObject), res, err
+	}
+	domain, err := op.Wait(ctx)
+	finish(err)
+	return domain, op.response(res), err
+}
+
+// StartRevertSnapshot starts reverting to the snapshot and returns without waiting for the task
+func (d *DomainService) StartRevertSnapshot(ctx context.Context, Id string, snapshotId string) (*DomainOperation, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.StartRevertSnapshot")
+	return d.startSnapshotAction(ctx, Id, "/revert-snapshot/?async=1", snapshotId, OperationKind.SnapshotRevert)
+}
+
+// RemoveSnapshot deletes the snapshot merging its changes, the domain keeps its current state
+func (d *DomainService) RemoveSnapshot(Id string, snapshotId string) (*DomainObject, *http.Response, error) {
+	return d.RemoveSnapshotContext(context.Background(), Id, snapshotId)
+}
+
+func (d *DomainService) RemoveSnapshotContext(ctx context.Context, Id string, snapshotId string) (*DomainObject, *http.Response, error) {
+	ctx, finish := startOperation(ctx, d.client, "DomainService.RemoveSnapshot", Id)
+	op, res, err := d.StartRemoveSnapshot(ctx, Id, snapshotId)
+	if err != nil {
+		finish(err)
+		return new(DomainObject), res, err
+	}
+	domain, err := op.Wait(ctx)
+	finish(err)
+	return domain, op.response(res), err
+}
+
+// StartRemoveSnapshot starts snapshot deletion and returns without waiting for the task
+func (d *DomainService) StartRemoveSnapshot(ctx context.Context, Id string, snapshotId string) (*DomainOperation, *http.Response, error) {
+	ctx = withOperation(ctx, "DomainService.StartRemoveSnapshot")
+	return d.startSnapshotAction(ctx, Id, "/remove-snapshot/?async=1", snapshotId, OperationKind.SnapshotRemove)
+}
+
+func (d *DomainService) startSnapshotAction(ctx context.Context, Id string, action string, snapshotId string, kind string) (*DomainOperation, *http.Response, error) {
+	b, _ := json.Marshal(snapshotRequest{snapshotId})
+	asyncResp := new(AsyncEntityResponse)
+	res, err := d.client.ExecuteRequestContext(ctx, "POST", fmt.Sprint(baseDomainUrl, Id, action), b, asyncResp)
+	if err != nil {
+		return nil, res, err
+	}
+	op, err := newDomainOperation(d.client, OperationState{kind, asyncResp.Task.Id, Id})
+	return op, res, err
+}
@@ -0,0 +1,124 @@
+package veil
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func Test_DomainSnapshots(t *testing.T) {
+	_, client := newTestServerClient(t)
+	domain, _, err := client.Domain.MultiCreate(DomainMultiCreateConfig{
+		DomainCreateConfig: DomainCreateConfig{VerboseName: "lab", MemoryCount: 1024},
+		StartOn:            true,
+	})
+	require.NoError(t, err)
+
+	base, _, err := client.Domain.CreateSnapshot(domain.Id, SnapshotCreateConfig{VerboseName: "base", Memory: true})
+	require.NoError(t, err)
+	assert.Equal(t, "base", base.VerboseName)
+	assert.Equal(t, Status.Active, base.Status)
+	assert.True(t, base.Current)
+	assert.True(t, base.Memory)
+
+	_, _, err = client.Domain.Update(domain.Id, DomainUpdateConfig{Description: "dirty"})
+	require.NoError(t, err)
+	_, _, err = client.Domain.Shutdown(domain, false)
+	require.NoError(t, err)
+	_, _, err = client.Domain.CreateSnapshot(domain.Id, SnapshotCreateConfig{VerboseName: "memory", Memory: true})
+	assert.True(t, IsBadRequest(err))
+	second, _, err := client.Domain.CreateSnapshot(domain.Id, SnapshotCreateConfig{VerboseName: "second"})
+	require.NoError(t, err)
+	assert.Equal(t, base.Id, second.Parent)
+
+	reverted, _, err := client.Domain.RevertSnapshot(domain.Id, base.Id)
+	require.NoError(t, err)
+	assert.Equal(t, 3, reverted.UserPowerState)
+	assert.Equal(t, "", reverted.Description)
+
+	snapshots, _, err := client.Domain.ListSnapshots(domain.Id)
+	require.NoError(t, err)
+	require.Equal(t, 2, snapshots.Count)
+	snapshot, _, err := client.Domain.GetSnapshot(domain.Id, base.Id)
+	require.NoError(t, err)
+	assert.True(t, snapshot.Current)
+	_, _, err = client.Domain.GetSnapshot(domain.Id, "missing")
+	assert.True(t, IsNotFound(err))
+
+	clone, _, err := client.Domain.Clone(domain.Id, DomainCloneConfig{VerboseName: "lab-2", Snapshot: second.Id})
+	require.NoError(t, err)
+	assert.Equal(t, "dirty", clone.Description)
+	_, _, err = client.Domain.Clone(domain.Id, DomainCloneConfig{VerboseName: "lab-3", Snapshot: "missing"})
+	assert.True(t, IsNotFound(err))
+
+	_, _, err = client.Domain.RemoveSnapshot(domain.Id, base.Id)
+	require.NoError(t, err)
+	snapshots, _, err = client.Domain.ListSnapshots(domain.Id)
+	require.NoError(t, err)
+	require.Equal(t, 1, snapshots.Count)
+	assert.Equal(t, "", snapshots.Results[0].Parent)
+	return
+}
+
+func Test_SnapshotOperations(t *testing.T) {
+	server, client := newTestServerClient(t)
+	ctx := context.Background()
+	domain, _, err := client.Domain.Create(DomainCreateConfig{VerboseName: "vm"})
+	require.NoError(t, err)
+
+	op, _, err := client.Domain.StartCreateSnapshot(ctx, domain.Id, SnapshotCreateConfig{VerboseName: "before"})
+	require.NoError(t, err)
+	assert.Equal(t, domain.Id, op.EntityId())
+	resumed, err := client.Domain.ResumeSnapshotOperation(op.State())
+	require.NoError(t, err)
+	_, err = resumed.Result()
+	assert.ErrorIs(t, err, ErrOperationInProgress)
+	assert.True(t, server.FinishTask(op.TaskId(), ""))
+	snapshot, err := resumed.Wait(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "before", snapshot.VerboseName)
+
+	_, err = client.Domain.ResumeSnapshotOperation(OperationState{Kind: OperationKind.DomainClone, TaskId: op.TaskId()})
+	assert.Error(t, err)
+	revert, _, err := client.Domain.StartRevertSnapshot(ctx, domain.Id, snapshot.Id)
+	require.NoError(t, err)
+	assert.Equal(t, OperationKind.SnapshotRevert, revert.State().Kind)
+	assert.True(t, server.FinishTask(revert.TaskId(), "snapshot is corrupted"))
+	_, err = revert.Wait(ctx)
+	assert.True(t, IsTaskFailed(err))
+
+	vdisk, _, err := client.Vdisk.Create(&VdiskCreate{VerboseName: "disk", Size: 1}, false)
+	require.NoError(t, err)
+	consolidated, _, err := client.Vdisk.Consolidate(vdisk.Id)
+	require.NoError(t, err)
+	assert.Equal(t, vdisk.Id, consolidated.Id)
+	assert.Empty(t, consolidated.Snapshots)
+	assert.Equal(t, 1, server.CountRequests("POST", "/api/vdisks/*/consolidate/"))
+	return
+}
+
+func Test_DomainSnapshotsLive(t *testing.T) {
+	client := liveClient(t)
+	liveEndpoints(t, client, "/api/domains/{id}/snapshots/", "/api/domains/{id}/snapshots/{snapshot_id}/",
+		"/api/domains/{id}/create-snapshot/", "/api/domains/{id}/revert-snapshot/", "/api/domains/{id}/remove-snapshot/")
+	domain := liveDomain(t, client)
+
+	snapshot, _, err := client.Domain.CreateSnapshot(domain.Id, SnapshotCreateConfig{VerboseName: "base"})
+	require.Nil(t, err)
+	assert.Equal(t, "base", snapshot.VerboseName)
+	snapshots, _, err := client.Domain.ListSnapshots(domain.Id)
+	require.Nil(t, err)
+	require.Len(t, snapshots.Results, 1)
+	assert.Equal(t, snapshot.Id, snapshots.Results[0].Id)
+
+	reverted, _, err := client.Domain.RevertSnapshot(domain.Id, snapshot.Id)
+	require.Nil(t, err)
+	assert.Equal(t, domain.Id, reverted.Id)
+	_, _, err = client.Domain.RemoveSnapshot(domain.Id, snapshot.Id)
+	require.Nil(t, err)
+	snapshots, _, err = client.Domain.ListSnapshots(domain.Id)
+	require.Nil(t, err)
+	assert.Empty(t, snapshots.Results)
+	return
+}
@@ -1,6 +1,7 @@
 package veil
 
 import (
+	"context"
 	"net/http"
 )
 
@@ -35,10 +36,15 @@ type Swagger struct {
 }
 
 func (d *SwaggerService) Get() (*Swagger, *http.Response, error) {
+	return d.GetContext(context.Background())
+}
+
+func (d *SwaggerService) GetContext(ctx context.Context) (*Swagger, *http.Response, error) {
+	ctx = withOperation(ctx, "SwaggerService.Get")
 
 	response := new(Swagger)
 
-	res, err := d.client.ExecuteRequest("GET", SwaggerUrl, []byte{}, response)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", SwaggerUrl, []byte{}, response)
 
 	return response, res, err
 }
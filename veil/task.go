@@ -1,9 +1,11 @@
 package veil
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
-	"strconv"
+	"net/url"
 	"time"
 )
 
@@ -84,49 +86,358 @@ type TasksResponse struct {
 }
 
 func (d *TaskService) List() (*TasksResponse, *http.Response, error) {
+	return d.ListContext(context.Background())
+}
+
+func (d *TaskService) ListContext(ctx context.Context) (*TasksResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "TaskService.List")
+	response := new(TasksResponse)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", baseTaskUrl, []byte{}, response)
+	return response, res, err
+}
+
+// TaskFilter typed filter of task list, zero fields are not sent
+type TaskFilter struct {
+	ListOptions
+	Status      []string
+	User        string
+	Parent      string
+	IsMultitask *bool
+	// CreatedAfter and CreatedBefore bound the task creation time
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+}
+
+// Values encodes the filter to query parameters
+func (f TaskFilter) Values() url.Values {
+	params := f.ListOptions.Values()
+	addStrings(params, "status", f.Status)
+	setString(params, "user", f.User)
+	setString(params, "parent", f.Parent)
+	setBool(params, "is_multitask", f.IsMultitask)
+	setTime(params, "created__gte", f.CreatedAfter)
+	setTime(params, "created__lte", f.CreatedBefore)
+	return params
+}
+
+func (d *TaskService) ListParams(queryParams map[string]string) (*TasksResponse, *http.Response, error) {
+	return d.ListParamsContext(context.Background(), queryParams)
+}
+
+func (d *TaskService) ListParamsContext(ctx context.Context, queryParams map[string]string) (*TasksResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "TaskService.ListParams")
 	response := new(TasksResponse)
-	res, err := d.client.ExecuteRequest("GET", baseTaskUrl, []byte{}, response)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", buildListUrl(baseTaskUrl, Params(queryParams)), []byte{}, response)
 	return response, res, err
 }
 
+func (d *TaskService) ListFiltered(filter TaskFilter) (*TasksResponse, *http.Response, error) {
+	return d.ListFilteredContext(context.Background(), filter)
+}
+
+func (d *TaskService) ListFilteredContext(ctx context.Context, filter TaskFilter) (*TasksResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "TaskService.ListFiltered")
+	response := new(TasksResponse)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", buildListUrl(baseTaskUrl, filter), []byte{}, response)
+	return response, res, err
+}
+
+// Subtasks returns children of the multitask
+func (d *TaskService) Subtasks(Id string) (*TasksResponse, *http.Response, error) {
+	return d.SubtasksContext(context.Background(), Id)
+}
+
+func (d *TaskService) SubtasksContext(ctx context.Context, Id string) (*TasksResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "TaskService.Subtasks")
+	return d.ListFilteredContext(ctx, TaskFilter{Parent: Id})
+}
+
+// Cancel asks VeiL to cancel the task, only tasks with IsCancellable can be canceled
+func (d *TaskService) Cancel(Id string) (bool, *http.Response, error) {
+	return d.CancelContext(context.Background(), Id)
+}
+
+func (d *TaskService) CancelContext(ctx context.Context, Id string) (bool, *http.Response, error) {
+	ctx = withOperation(ctx, "TaskService.Cancel")
+	res, err := d.client.ExecuteRequestContext(ctx, "POST", fmt.Sprint(baseTaskUrl, Id, "/cancel/"), []byte{}, nil)
+	if err != nil {
+		return false, res, err
+	}
+	return true, res, err
+}
+
+// TaskSummary aggregated view of a task and its subtasks
+type TaskSummary struct {
+	Task     *TaskObject
+	Subtasks []TaskObjectsList
+	// StatusCount number of subtasks by status
+	StatusCount map[string]int
+	// NodesUserResponses responses of the task and all subtasks
+	NodesUserResponses []NodesUserResponses
+	// Errors error messages of the task and failed subtasks
+	Errors []string
+}
+
+// Summary returns the task with the rolled up state of its subtasks, subtasks are loaded only for multitasks
+func (d *TaskService) Summary(Id string) (*TaskSummary, *http.Response, error) {
+	return d.SummaryContext(context.Background(), Id)
+}
+
+func (d *TaskService) SummaryContext(ctx context.Context, Id string) (*TaskSummary, *http.Response, error) {
+	ctx = withOperation(ctx, "TaskService.Summary")
+	task, res, err := d.GetContext(ctx, Id)
+	if err != nil {
+		return nil, res, err
+	}
+	summary := &TaskSummary{
+		Task:               task,
+		StatusCount:        map[string]int{},
+		NodesUserResponses: append([]NodesUserResponses{}, task.NodesUserResponses...),
+	}
+	if task.ErrorMessage != "" {
+		summary.Errors = append(summary.Errors, task.ErrorMessage)
+	}
+	if !task.IsMultitask {
+		return summary, res, nil
+	}
+	subtasks, err := d.ListAll(ctx, TaskFilter{Parent: Id})
+	if err != nil {
+		return nil, res, err
+	}
+	summary.Subtasks = subtasks
+	for _, v := range subtasks {
+		summary.StatusCount[v.Status]++
+		summary.NodesUserResponses = append(summary.NodesUserResponses, v.NodesUserResponses...)
+		if v.ErrorMessage != "" {
+			summary.Errors = append(summary.Errors, v.ErrorMessage)
+		}
+	}
+	return summary, res, nil
+}
+
+// TaskIterator streams tasks page by page
+type TaskIterator struct {
+	*Iterator
+	item TaskObjectsList
+}
+
+// Next advances to the next task
+func (it *TaskIterator) Next() bool {
+	it.item = TaskObjectsList{}
+	return it.Iterator.Next() && it.decode(&it.item)
+}
+
+// Item returns the current task
+func (it *TaskIterator) Item() TaskObjectsList {
+	return it.item
+}
+
+// Iter returns an iterator over all tasks matching filter, filter and opts may be nil
+func (d *TaskService) Iter(ctx context.Context, filter ListFilter, opts *PageOptions) *TaskIterator {
+	ctx = withOperation(ctx, "TaskService.Iter")
+	return &TaskIterator{Iterator: newIterator(ctx, d.client, baseTaskUrl, filter, opts)}
+}
+
+// ListAll returns all tasks matching filter following the pages until exhausted
+func (d *TaskService) ListAll(ctx context.Context, filter ListFilter) ([]TaskObjectsList, error) {
+	ctx = withOperation(ctx, "TaskService.ListAll")
+	it := d.Iter(ctx, filter, nil)
+	defer it.Close()
+	var items []TaskObjectsList
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
+
 func (d *TaskService) Get(Id string) (*TaskObject, *http.Response, error) {
+	return d.GetContext(context.Background(), Id)
+}
+
+func (d *TaskService) GetContext(ctx context.Context, Id string) (*TaskObject, *http.Response, error) {
+	ctx = withOperation(ctx, "TaskService.Get")
 	entity := new(TaskObject)
-	res, err := d.client.ExecuteRequest("GET", fmt.Sprint(baseTaskUrl, Id, "/"), []byte{}, entity)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", fmt.Sprint(baseTaskUrl, Id, "/"), []byte{}, entity)
 	return entity, res, err
 }
 
 func (d *TaskService) Response(Id string, object interface{}) (*http.Response, error) {
-	res, err := d.client.ExecuteRequest("GET", fmt.Sprint(baseTaskUrl, Id, "/response/"), []byte{}, object)
+	return d.ResponseContext(context.Background(), Id, object)
+}
+
+func (d *TaskService) ResponseContext(ctx context.Context, Id string, object interface{}) (*http.Response, error) {
+	ctx = withOperation(ctx, "TaskService.Response")
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", fmt.Sprint(baseTaskUrl, Id, "/response/"), []byte{}, object)
 	return res, err
 }
 
-func WaitTaskReady(client *WebClient, uuid string, blocked bool, timeout int64, panicTimeout bool) *TaskObject {
+// TaskWaitOptions tunes TaskService.Wait, zero values mean defaults
+type TaskWaitOptions struct {
+	// PollInterval time between task status checks, StatusCheckInterval seconds by default
+	PollInterval time.Duration
+	// Timeout of waiting, TaskAsyncTimeout seconds by default, negative value disables it
+	Timeout time.Duration
+	// OnProgress is called with the polled task every time its progress changes
+	OnProgress func(task *TaskObject)
+}
+
+// ErrTaskTimeout is wrapped by the error returned from TaskService.Wait when TaskWaitOptions.Timeout expires
+var ErrTaskTimeout = errors.New("task wait timeout")
+
+// TaskFailedError is returned by TaskService.Wait when the task finishes with FAILED, CANCELED, LOST or PARTIAL status
+type TaskFailedError struct {
+	TaskId             string
+	Name               string
+	Status             string
+	ErrorMessage       string
+	NodesUserResponses []NodesUserResponses
+}
+
+func (e *TaskFailedError) Error() string {
+	errMsg := fmt.Sprintf("task %s (%s) finished with status %s", e.TaskId, e.Name, e.Status)
+	if e.ErrorMessage != "" {
+		errMsg += ": " + e.ErrorMessage
+	}
+	return errMsg
+}
+
+func newTaskFailedError(task *TaskObject) *TaskFailedError {
+	return &TaskFailedError{
+		TaskId:             task.Id,
+		Name:               task.Name,
+		Status:             task.Status,
+		ErrorMessage:       task.ErrorMessage,
+		NodesUserResponses: task.NodesUserResponses,
+	}
+}
+
+// IsTaskFailed reports whether err is a TaskFailedError
+func IsTaskFailed(err error) bool {
+	var taskErr *TaskFailedError
+	return errors.As(err, &taskErr)
+}
+
+// Wait polls the task until it leaves IN_PROGRESS status.
+// It returns TaskFailedError if the task was not successful, an error wrapping ErrTaskTimeout
+// if opts.Timeout expired and ctx.Err() once ctx is done. opts may be nil.
+func (d *TaskService) Wait(ctx context.Context, Id string, opts *TaskWaitOptions) (*TaskObject, error) {
+	logger := loggerOf(d.client)
+	observers := observersOf(d.client)
+	start := time.Now()
+	logger.Log(ctx, LevelDebug, "veil task waiting", "task_id", Id)
+	// Every observer gets the context it returned, e.g. its own span, polls run with the innermost one
+	contexts := make([]context.Context, len(observers))
+	for i, o := range observers {
+		ctx = o.TaskWaitStarted(ctx, Id)
+		contexts[i] = ctx
+	}
+	polled := func(task *TaskObject) {
+		for i, o := range observers {
+			o.TaskPolled(contexts[i], task)
+		}
+	}
+	task, err := d.wait(ctx, Id, opts, polled)
+	for i := len(observers) - 1; i >= 0; i-- {
+		observers[i].TaskWaitFinished(contexts[i], Id, task, time.Since(start), err)
+	}
+	if err != nil {
+		logger.Log(ctx, LevelWarn, "veil task wait failed", "task_id", Id, "duration", time.Since(start), "error", err)
+	} else {
+		logger.Log(ctx, LevelInfo, "veil task finished", "task_id", Id, "name", task.Name, "duration", time.Since(start))
+	}
+	return task, err
+}
+
+func (d *TaskService) wait(ctx context.Context, Id string, opts *TaskWaitOptions, polled func(task *TaskObject)) (*TaskObject, error) {
+	if opts == nil {
+		opts = new(TaskWaitOptions)
+	}
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = time.Second * StatusCheckInterval
+	}
+	timeout := opts.Timeout
 	if timeout == 0 {
-		timeout = TaskAsyncTimeout
-	}
-	task, _, _ := client.Task.Get(uuid)
-	if task.Status != TaskStatus.InProgress {
-		return task
-	} else if blocked {
-		timeoutTime := time.Now().Unix() + timeout
-		for true {
-			task, _, _ := client.Task.Get(uuid)
-			if task.Status != TaskStatus.InProgress {
-				task, _, _ := client.Task.Get(uuid)
-				return task
-			}
-			if time.Now().Unix() > timeoutTime {
-				if panicTimeout {
-					errMsg := fmt.Sprintf("Task: %s wait %d timeout error. is_multitask: %s. progress: %d. status: %s", task.Name, timeout, strconv.FormatBool(task.IsMultitask), task.Progress, task.Status)
-					panic(errMsg)
-				}
-			}
-			time.Sleep(time.Second * StatusCheckInterval)
+		timeout = time.Second * TaskAsyncTimeout
+	}
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	limiter := rateLimiterOf(d.client)
+	defer limiter.startTaskWait()()
+	pollCtx := WithRequestClass(ctx, ClassTaskPoll)
+	progress := -1
+	for {
+		task, _, err := d.GetContext(pollCtx, Id)
+		if err != nil {
+			return task, err
+		}
+		loggerOf(d.client).Log(ctx, LevelDebug, "veil task polled", "task_id", Id, "status", task.Status, "progress", task.Progress)
+		polled(task)
+		if opts.OnProgress != nil && task.Progress != progress {
+			progress = task.Progress
+			opts.OnProgress(task)
 		}
+		switch task.Status {
+		case TaskStatus.InProgress:
+		case TaskStatus.Success:
+			return task, nil
+		default:
+			return task, newTaskFailedError(task)
+		}
+
+		poll := time.NewTimer(limiter.pollInterval(interval))
+		select {
+		case <-ctx.Done():
+			poll.Stop()
+			return task, ctx.Err()
+		case <-deadline:
+			poll.Stop()
+			return task, fmt.Errorf("%w: task %s (%s) is still %s after %s, progress %d%%, is_multitask: %t",
+				ErrTaskTimeout, task.Id, task.Name, task.Status, timeout, task.Progress, task.IsMultitask)
+		case <-poll.C:
+		}
+	}
+}
+
+// WaitTaskReady returns the task state once it is finished or the timeout (in seconds) expires.
+// The state is returned immediately if blocked is false, panicTimeout false means no timeout.
+// It panics if the timeout expires and panicTimeout is true.
+//
+// Deprecated: use TaskService.Wait which reports errors of the task.
+func WaitTaskReady(client Client, uuid string, blocked bool, timeout int64, panicTimeout bool) *TaskObject {
+	task, err := WaitTaskReadyContext(context.Background(), client, uuid, blocked, timeout, panicTimeout)
+	if errors.Is(err, ErrTaskTimeout) {
+		panic(err.Error())
 	}
 	return task
 }
 
+// WaitTaskReadyContext is like WaitTaskReady but stops polling and returns ctx.Err() once ctx is done.
+// Instead of panic it returns an error wrapping ErrTaskTimeout if the timeout expires and panicTimeout is true.
+// Failed task is not an error, the caller checks its status
+//
+// Deprecated: use TaskService.Wait which reports errors of the task.
+func WaitTaskReadyContext(ctx context.Context, client Client, uuid string, blocked bool, timeout int64, panicTimeout bool) (*TaskObject, error) {
+	if !blocked {
+		task, _, err := NewTaskService(client).GetContext(ctx, uuid)
+		return task, err
+	}
+	opts := &TaskWaitOptions{Timeout: time.Duration(timeout) * time.Second}
+	if !panicTimeout {
+		opts.Timeout = -1
+	}
+	task, err := NewTaskService(client).Wait(ctx, uuid, opts)
+	if IsTaskFailed(err) {
+		return task, nil
+	}
+	return task, err
+}
+
 type AsyncResponse struct {
 	Task TaskObject `json:"_task,omitempty"`
 }
@@ -1,12 +1,22 @@
 package veil
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jsc-masshtab/veil-api-client-go/veil/veiltest"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func Test_Task(t *testing.T) {
-	client := NewClient("", "", false)
+	server, client := newTestServerClient(t)
+	server.Add(veiltest.Tasks, veiltest.Object{"name": "Start domain", "status": "SUCCESS", "progress": 100})
 	response, _, err := client.Task.List()
 	assert.Nil(t, err)
 	for _, v := range response.Results {
@@ -20,3 +30,124 @@ func Test_Task(t *testing.T) {
 	return
 
 }
+
+func Test_WaitTaskReadyContextCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, `{"id": "%s", "status": "%s"}`, "task", TaskStatus.InProgress)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "token", false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	task, err := WaitTaskReadyContext(ctx, client, "task", true, 0, true)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, TaskStatus.InProgress, task.Status)
+
+	return
+}
+
+func Test_WaitTaskReadyTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"id": "%s", "status": "%s"}`, "task", TaskStatus.InProgress)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "token", false)
+
+	task, err := WaitTaskReadyContext(context.Background(), client, "task", true, 1, true)
+	assert.ErrorIs(t, err, ErrTaskTimeout)
+	assert.Equal(t, TaskStatus.InProgress, task.Status)
+	assert.Panics(t, func() {
+		WaitTaskReady(client, "task", true, 1, true)
+	})
+
+	return
+}
+
+func Test_TaskWait(t *testing.T) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/tasks/success/":
+			progress := atomic.AddInt32(&polls, 1) * 50
+			status := TaskStatus.InProgress
+			if progress >= 100 {
+				status = TaskStatus.Success
+			}
+			fmt.Fprintf(w, `{"id": "success", "status": "%s", "progress": %d}`, status, progress)
+		case "/api/tasks/failed/":
+			fmt.Fprintf(w, `{"id": "failed", "name": "clone", "status": "%s", "error_message": "no space left",
+				"nodes_user_responses": [{"node_id": "node", "node_response": "disk full"}]}`, TaskStatus.Failed)
+		case "/api/tasks/stuck/":
+			fmt.Fprintf(w, `{"id": "stuck", "status": "%s", "progress": 10}`, TaskStatus.InProgress)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "token", false)
+	ctx := context.Background()
+
+	var progress []int
+	task, err := client.Task.Wait(ctx, "success", &TaskWaitOptions{
+		PollInterval: time.Millisecond,
+		OnProgress:   func(task *TaskObject) { progress = append(progress, task.Progress) },
+	})
+	require.Nil(t, err)
+	assert.Equal(t, TaskStatus.Success, task.Status)
+	assert.Equal(t, []int{50, 100}, progress)
+
+	_, err = client.Task.Wait(ctx, "failed", nil)
+	var taskErr *TaskFailedError
+	require.True(t, errors.As(err, &taskErr))
+	assert.Equal(t, "no space left", taskErr.ErrorMessage)
+	assert.Equal(t, "disk full", taskErr.NodesUserResponses[0].NodeResponse)
+
+	_, err = client.Task.Wait(ctx, "stuck", &TaskWaitOptions{PollInterval: time.Millisecond, Timeout: 20 * time.Millisecond})
+	assert.ErrorIs(t, err, ErrTaskTimeout)
+
+	_, err = client.Task.Wait(ctx, "missing", nil)
+	assert.True(t, IsNotFound(err))
+
+	return
+}
+
+func Test_TaskFilterCancelSummary(t *testing.T) {
+	created := time.Date(2022, 1, 20, 10, 0, 0, 0, time.UTC)
+	filter := TaskFilter{Status: []string{TaskStatus.Failed}, Parent: "multi", IsMultitask: Bool(true), CreatedAfter: created}
+	assert.Equal(t, "created__gte=2022-01-20T10%3A00%3A00Z&is_multitask=true&parent=multi&status=FAILED", filter.Values().Encode())
+
+	var canceled bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/tasks/multi/":
+			fmt.Fprint(w, `{"id": "multi", "is_multitask": true, "status": "PARTIAL",
+				"nodes_user_responses": [{"node_id": "controller", "node_response": "started"}]}`)
+		case r.URL.Path == "/api/tasks/" && r.URL.Query().Get("parent") == "multi":
+			fmt.Fprint(w, `{"count": 2, "results": [
+				{"id": "a", "status": "SUCCESS", "nodes_user_responses": [{"node_id": "node-a", "node_response": "ok"}]},
+				{"id": "b", "status": "FAILED", "error_message": "node-b is offline"}]}`)
+		case r.URL.Path == "/api/tasks/b/cancel/" && r.Method == "POST":
+			canceled = true
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "token", false)
+
+	summary, _, err := client.Task.Summary("multi")
+	require.Nil(t, err)
+	assert.Len(t, summary.Subtasks, 2)
+	assert.Equal(t, map[string]int{TaskStatus.Success: 1, TaskStatus.Failed: 1}, summary.StatusCount)
+	assert.Len(t, summary.NodesUserResponses, 2)
+	assert.Equal(t, []string{"node-b is offline"}, summary.Errors)
+
+	status, _, err := client.Task.Cancel("b")
+	assert.Nil(t, err)
+	assert.True(t, status)
+	assert.True(t, canceled)
+
+	return
+}
@@ -0,0 +1,182 @@
+// Package tracing provides OpenTelemetry spans of veil API client: a span per API call, spans of operations
+// consisting of several calls (e.g. DomainService.MultiCreate) and spans of task waits with poll events.
+//
+//	tracer := tracing.New(nil)
+//	tracer.Instrument(client)
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jsc-masshtab/veil-api-client-go/veil"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName name of the tracer
+const InstrumentationName = "github.com/jsc-masshtab/veil-api-client-go/veil"
+
+// Attributes of veil spans
+const (
+	EntityIdKey     = attribute.Key("veil.entity_id")
+	TaskIdKey       = attribute.Key("veil.task_id")
+	TaskStatusKey   = attribute.Key("veil.task.status")
+	TaskProgressKey = attribute.Key("veil.task.progress")
+	TaskNameKey     = attribute.Key("veil.task.name")
+	TaskOutcomeKey  = attribute.Key("veil.task.outcome")
+	EndpointKey     = attribute.Key("veil.endpoint")
+	AttemptsKey     = attribute.Key("veil.attempts")
+)
+
+// Tracer creates spans of API calls, operations and task waits. It implements veil.TaskObserver and veil.OperationObserver
+type Tracer struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+// New creates tracer, nil provider means global provider of otel
+func New(provider trace.TracerProvider) *Tracer {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return &Tracer{
+		tracer:     provider.Tracer(InstrumentationName),
+		propagator: otel.GetTextMapPropagator(),
+	}
+}
+
+// Instrument adds middleware and observers of the tracer to client
+func (t *Tracer) Instrument(client *veil.WebClient) {
+	client.Use(t.Middleware())
+	client.TaskObservers = append(client.TaskObservers, t)
+	client.OperationObservers = append(client.OperationObservers, t)
+}
+
+// Middleware creates span of every API call and propagates trace context in request headers.
+// The span is named after the service method, e.g. DomainService.Get, or after the endpoint for ExecuteRequest
+func (t *Tracer) Middleware() veil.Middleware {
+	return func(next veil.Handler) veil.Handler {
+		return func(ctx context.Context, call *veil.Call) error {
+			endpoint := veil.EndpointTemplate(call.URL)
+			name := veil.OperationName(ctx)
+			if name == "" {
+				name = "veil " + call.Method + " " + endpoint
+			}
+			ctx, span := t.tracer.Start(ctx, name,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					semconv.HTTPMethodKey.String(call.Method),
+					semconv.HTTPURLKey.String(stripQuery(call.URL)),
+					EndpointKey.String(endpoint),
+				))
+			defer span.End()
+			span.SetAttributes(idAttributes(call.URL)...)
+			t.propagator.Inject(ctx, propagation.HeaderCarrier(call.Header))
+
+			err := next(ctx, call)
+			span.SetAttributes(AttemptsKey.Int(call.Attempts))
+			if call.Response != nil {
+				span.SetAttributes(semconv.HTTPStatusCodeKey.Int(call.Response.StatusCode))
+			}
+			if taskId := asyncTaskId(call.ResponseBody); taskId != "" {
+				span.SetAttributes(TaskIdKey.String(taskId))
+			}
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			return err
+		}
+	}
+}
+
+// OperationStarted implements veil.OperationObserver, the span is named after the operation, e.g. DomainService.Clone
+func (t *Tracer) OperationStarted(ctx context.Context, name string, entityId string) (context.Context, func(err error)) {
+	ctx, span := t.tracer.Start(ctx, name)
+	if entityId != "" {
+		span.SetAttributes(EntityIdKey.String(entityId))
+	}
+	return ctx, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+// TaskWaitStarted implements veil.TaskObserver, requests of the polls become children of the wait span
+func (t *Tracer) TaskWaitStarted(ctx context.Context, taskId string) context.Context {
+	ctx, _ = t.tracer.Start(ctx, "TaskService.Wait", trace.WithAttributes(TaskIdKey.String(taskId)))
+	return ctx
+}
+
+// TaskPolled implements veil.TaskObserver, every poll is recorded as event of the wait span
+func (t *Tracer) TaskPolled(ctx context.Context, task *veil.TaskObject) {
+	trace.SpanFromContext(ctx).AddEvent("poll", trace.WithAttributes(
+		TaskStatusKey.String(task.Status),
+		TaskProgressKey.Int(task.Progress),
+	))
+}
+
+// TaskWaitFinished implements veil.TaskObserver
+func (t *Tracer) TaskWaitFinished(ctx context.Context, taskId string, task *veil.TaskObject, elapsed time.Duration, err error) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(TaskOutcomeKey.String(veil.TaskWaitOutcome(err)))
+	if task != nil && task.Name != "" {
+		span.SetAttributes(TaskNameKey.String(task.Name))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func stripQuery(rawUrl string) string {
+	if i := strings.IndexByte(rawUrl, '?'); i >= 0 {
+		return rawUrl[:i]
+	}
+	return rawUrl
+}
+
+// idAttributes returns task id for task endpoints and entity id for others, e.g. domain id of /api/domains/{id}/start/
+func idAttributes(rawUrl string) []attribute.KeyValue {
+	path := rawUrl
+	if u, err := url.Parse(rawUrl); err == nil {
+		path = u.Path
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	template := strings.Split(strings.Trim(veil.EndpointTemplate(path), "/"), "/")
+	for i, segment := range segments {
+		if i == 0 || i >= len(template) || template[i] != "{id}" {
+			continue
+		}
+		if segments[i-1] == "tasks" {
+			return []attribute.KeyValue{TaskIdKey.String(segment)}
+		}
+		return []attribute.KeyValue{EntityIdKey.String(segment)}
+	}
+	return nil
+}
+
+// asyncTaskId returns id of the task started by async call, e.g. ?async=1 requests
+func asyncTaskId(body []byte) string {
+	if !bytes.Contains(body, []byte(`"_task"`)) {
+		return ""
+	}
+	response := veil.AsyncResponse{}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return ""
+	}
+	return response.Task.Id
+}
@@ -0,0 +1,179 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"github.com/jsc-masshtab/veil-api-client-go/veil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+const (
+	domainId = "7e5a1c3b-2b1e-4f7a-9d2c-5b8f0a6e4d21"
+	taskId   = "0b1c2d3e-4f5a-4b6c-8d7e-9f0a1b2c3d4e"
+)
+
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	values := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		values[kv.Key] = kv.Value
+	}
+	return values
+}
+
+func Test_TracerMultiCreate(t *testing.T) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/domains/multi-create-domain/":
+			fmt.Fprintf(w, `{"_task": {"id": "%s"}, "entity": "%s"}`, taskId, domainId)
+		case "/api/tasks/" + taskId + "/":
+			if atomic.AddInt32(&polls, 1) == 1 {
+				fmt.Fprintf(w, `{"id": "%s", "status": "IN_PROGRESS", "progress": 40}`, taskId)
+				return
+			}
+			fmt.Fprintf(w, `{"id": "%s", "name": "Multi create", "status": "SUCCESS", "progress": 100}`, taskId)
+		case "/api/domains/" + domainId + "/":
+			fmt.Fprintf(w, `{"id": "%s", "verbose_name": "vm"}`, domainId)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	client, err := veil.NewClientWithOptions(veil.WithBaseURL(server.URL))
+	require.NoError(t, err)
+	New(provider).Instrument(client)
+
+	domain, _, err := client.Domain.MultiCreate(veil.DomainMultiCreateConfig{DomainCreateConfig: veil.DomainCreateConfig{VerboseName: "vm"}})
+	require.NoError(t, err)
+	assert.Equal(t, domainId, domain.Id)
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	operation := spans["DomainService.MultiCreate"]
+	require.NotNil(t, operation)
+
+	post := spans["DomainService.StartMultiCreate"]
+	require.NotNil(t, post)
+	assert.Equal(t, operation.SpanContext().SpanID(), post.Parent().SpanID())
+	assert.Equal(t, taskId, attributes(post)[TaskIdKey].AsString())
+	assert.Equal(t, int64(200), attributes(post)["http.status_code"].AsInt64())
+
+	wait := spans["TaskService.Wait"]
+	require.NotNil(t, wait)
+	assert.Equal(t, operation.SpanContext().SpanID(), wait.Parent().SpanID())
+	assert.Equal(t, veil.TaskOutcomeSuccess, attributes(wait)[TaskOutcomeKey].AsString())
+	assert.Len(t, wait.Events(), 2)
+
+	poll := spans["TaskService.Get"]
+	require.NotNil(t, poll)
+	assert.Equal(t, wait.SpanContext().SpanID(), poll.Parent().SpanID())
+	assert.Equal(t, taskId, attributes(poll)[TaskIdKey].AsString())
+
+	get := spans["DomainService.Get"]
+	require.NotNil(t, get)
+	assert.Equal(t, domainId, attributes(get)[EntityIdKey].AsString())
+	return
+}
+
+func Test_TracerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	client, err := veil.NewClientWithOptions(veil.WithBaseURL(server.URL))
+	require.NoError(t, err)
+	New(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))).Instrument(client)
+
+	_, err = client.ExecuteRequestContext(context.Background(), "POST", "/api/domains/"+domainId+"/start/", []byte{}, nil)
+	require.True(t, veil.IsNotFound(err))
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "veil POST /api/domains/{id}/start/", spans[0].Name())
+	assert.Equal(t, "Error", spans[0].Status().Code.String())
+	assert.Equal(t, int64(404), attributes(spans[0])["http.status_code"].AsInt64())
+	return
+}
+
+func Test_TracerServiceMethod(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/domains/" + domainId + "/":
+			fmt.Fprintf(w, `{"id": "%s", "verbose_name": "vm"}`, domainId)
+		case "/api/domains/" + domainId + "/start/":
+			fmt.Fprintf(w, `{"_task": {"id": "%s"}}`, taskId)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	client, err := veil.NewClientWithOptions(veil.WithBaseURL(server.URL))
+	require.NoError(t, err)
+	New(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))).Instrument(client)
+
+	domain, _, err := client.Domain.Get(domainId)
+	require.NoError(t, err)
+	_, _, err = client.Domain.Start(domain)
+	require.NoError(t, err)
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "DomainService.Get", spans[0].Name())
+	assert.Equal(t, domainId, attributes(spans[0])[EntityIdKey].AsString())
+	assert.Equal(t, int64(200), attributes(spans[0])["http.status_code"].AsInt64())
+	assert.Equal(t, "DomainService.Start", spans[1].Name())
+	assert.Equal(t, domainId, attributes(spans[1])[EntityIdKey].AsString())
+	assert.Equal(t, taskId, attributes(spans[1])[TaskIdKey].AsString())
+
+	_, _, err = client.Vdisk.Remove("missing")
+	require.True(t, veil.IsNotFound(err))
+	spans = recorder.Ended()
+	require.Len(t, spans, 3)
+	assert.Equal(t, "VdiskService.Remove", spans[2].Name())
+	assert.Equal(t, "Error", spans[2].Status().Code.String())
+	return
+}
+
+func Test_TracerNestedTaskWait(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"id": "%s", "name": "Start", "status": "SUCCESS", "progress": 100}`, taskId)
+	}))
+	defer server.Close()
+
+	outer, inner := tracetest.NewSpanRecorder(), tracetest.NewSpanRecorder()
+	client, err := veil.NewClientWithOptions(veil.WithBaseURL(server.URL))
+	require.NoError(t, err)
+	New(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(outer))).Instrument(client)
+	New(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(inner))).Instrument(client)
+
+	_, err = client.Task.Wait(context.Background(), taskId, nil)
+	require.NoError(t, err)
+	// Every tracer ends its own wait span with the outcome and polls of the task
+	for _, recorder := range []*tracetest.SpanRecorder{outer, inner} {
+		var wait sdktrace.ReadOnlySpan
+		for _, span := range recorder.Ended() {
+			if span.Name() == "TaskService.Wait" {
+				wait = span
+			}
+		}
+		require.NotNil(t, wait)
+		assert.Equal(t, veil.TaskOutcomeSuccess, attributes(wait)[TaskOutcomeKey].AsString())
+		assert.Len(t, wait.Events(), 1)
+	}
+	return
+}
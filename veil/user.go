@@ -1,6 +1,7 @@
 package veil
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -65,34 +66,105 @@ type UsersResponse struct {
 }
 
 func (d *UserService) List() (*UsersResponse, *http.Response, error) {
+	return d.ListContext(context.Background())
+}
+
+func (d *UserService) ListContext(ctx context.Context) (*UsersResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "UserService.List")
 
 	response := new(UsersResponse)
 
-	res, err := d.client.ExecuteRequest("GET", baseUserUrl, []byte{}, response)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", baseUserUrl, []byte{}, response)
 
 	return response, res, err
 }
 
 func (d *UserService) ListParams(queryParams map[string]string) (*UsersResponse, *http.Response, error) {
-	listUrl := baseUserUrl
-	if len(queryParams) != 0 {
-		params := url.Values{}
-		for k, v := range queryParams {
-			params.Add(k, v)
-		}
-		listUrl += "?"
-		listUrl += params.Encode()
-	}
+	return d.ListParamsContext(context.Background(), queryParams)
+}
+
+func (d *UserService) ListParamsContext(ctx context.Context, queryParams map[string]string) (*UsersResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "UserService.ListParams")
+	response := new(UsersResponse)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", buildListUrl(baseUserUrl, Params(queryParams)), []byte{}, response)
+	return response, res, err
+}
+
+// UserFilter typed filter of users, zero fields are not sent
+type UserFilter struct {
+	ListOptions
+	UserName string
+	IsActive *bool
+	Groups   []string
+}
+
+// Values encodes the filter to query parameters
+func (f UserFilter) Values() url.Values {
+	params := f.ListOptions.Values()
+	setString(params, "username", f.UserName)
+	setBool(params, "is_active", f.IsActive)
+	addStrings(params, "groups", f.Groups)
+	return params
+}
+
+// ListFiltered returns the first page of users matching filter
+func (d *UserService) ListFiltered(filter UserFilter) (*UsersResponse, *http.Response, error) {
+	return d.ListFilteredContext(context.Background(), filter)
+}
+
+// ListFilteredContext returns the first page of users matching filter
+func (d *UserService) ListFilteredContext(ctx context.Context, filter UserFilter) (*UsersResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "UserService.ListFiltered")
 	response := new(UsersResponse)
-	res, err := d.client.ExecuteRequest("GET", listUrl, []byte{}, response)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", buildListUrl(baseUserUrl, filter), []byte{}, response)
 	return response, res, err
 }
 
+// UserIterator streams users page by page
+type UserIterator struct {
+	*Iterator
+	item UserObjectsList
+}
+
+// Next advances to the next user
+func (it *UserIterator) Next() bool {
+	it.item = UserObjectsList{}
+	return it.Iterator.Next() && it.decode(&it.item)
+}
+
+// Item returns the current user
+func (it *UserIterator) Item() UserObjectsList {
+	return it.item
+}
+
+// Iter returns an iterator over all users matching filter, filter and opts may be nil
+func (d *UserService) Iter(ctx context.Context, filter ListFilter, opts *PageOptions) *UserIterator {
+	ctx = withOperation(ctx, "UserService.Iter")
+	return &UserIterator{Iterator: newIterator(ctx, d.client, baseUserUrl, filter, opts)}
+}
+
+// ListAll returns all users matching filter following the pages until exhausted
+func (d *UserService) ListAll(ctx context.Context, filter ListFilter) ([]UserObjectsList, error) {
+	ctx = withOperation(ctx, "UserService.ListAll")
+	it := d.Iter(ctx, filter, nil)
+	defer it.Close()
+	var items []UserObjectsList
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
+
 func (d *UserService) Get(Id int) (*UserObject, *http.Response, error) {
+	return d.GetContext(context.Background(), Id)
+}
+
+func (d *UserService) GetContext(ctx context.Context, Id int) (*UserObject, *http.Response, error) {
+	ctx = withOperation(ctx, "UserService.Get")
 
 	user := new(UserObject)
 
-	res, err := d.client.ExecuteRequest("GET", fmt.Sprint(baseUserUrl, Id, "/"), []byte{}, user)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", fmt.Sprint(baseUserUrl, Id, "/"), []byte{}, user)
 
 	return user, res, err
 }
@@ -6,7 +6,7 @@ import (
 )
 
 func Test_UserListGet(t *testing.T) {
-	client := NewClient("", "", false)
+	_, client := newTestServerClient(t)
 
 	response, _, err := client.User.List()
 	assert.Nil(t, err)
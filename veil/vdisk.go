@@ -1,6 +1,7 @@
 package veil
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -71,6 +72,7 @@ const CacheTypes = `(default|none|writethrough|writeback|directsync|unsafe)`
 const PreallocationTypes = `(falloc|full|metadata)`
 
 type VdiskCreate struct {
+	IdempotencyKeyBase
 	VerboseName       string  `json:"verbose_name,omitempty"`
 	Datapool          string  `json:"datapool,omitempty"`
 	Size              float64 `json:"size,omitempty"`
@@ -96,53 +98,167 @@ type VdiskCreateAttach struct {
 
 // List Эндпоинт получения списка виртуальных дисков
 func (d *VdiskService) List() (*VdisksResponse, *http.Response, error) {
+	return d.ListContext(context.Background())
+}
+
+// ListContext Эндпоинт получения списка виртуальных дисков
+func (d *VdiskService) ListContext(ctx context.Context) (*VdisksResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "VdiskService.List")
 	response := new(VdisksResponse)
-	res, err := d.client.ExecuteRequest("GET", baseVdiskUrl, []byte{}, response)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", baseVdiskUrl, []byte{}, response)
 	return response, res, err
 }
 
 // List Эндпоинт получения списка виртуальных дисков с параметрами
 func (d *VdiskService) ListParams(queryParams map[string]string) (*VdisksResponse, *http.Response, error) {
-	listUrl := baseVdiskUrl
-	if len(queryParams) != 0 {
-		params := url.Values{}
-		for k, v := range queryParams {
-			params.Add(k, v)
-		}
-		listUrl += "?"
-		listUrl += params.Encode()
-	}
+	return d.ListParamsContext(context.Background(), queryParams)
+}
+
+func (d *VdiskService) ListParamsContext(ctx context.Context, queryParams map[string]string) (*VdisksResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "VdiskService.ListParams")
+	response := new(VdisksResponse)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", buildListUrl(baseVdiskUrl, Params(queryParams)), []byte{}, response)
+	return response, res, err
+}
+
+// VdiskFilter типизированный фильтр списка виртуальных дисков, пустые поля не передаются
+type VdiskFilter struct {
+	ListOptions
+	Status      []string
+	VerboseName string
+	Datapool    string
+	Domain      string
+	Node        string
+	Cluster     string
+}
+
+// Values кодирует фильтр в параметры запроса
+func (f VdiskFilter) Values() url.Values {
+	params := f.ListOptions.Values()
+	addStrings(params, "status", f.Status)
+	setString(params, "verbose_name", f.VerboseName)
+	setString(params, "datapool", f.Datapool)
+	setString(params, "domain", f.Domain)
+	setString(params, "node", f.Node)
+	setString(params, "cluster", f.Cluster)
+	return params
+}
+
+// ListFiltered Эндпоинт получения списка виртуальных дисков с типизированным фильтром
+func (d *VdiskService) ListFiltered(filter VdiskFilter) (*VdisksResponse, *http.Response, error) {
+	return d.ListFilteredContext(context.Background(), filter)
+}
+
+// ListFilteredContext Эндпоинт получения списка виртуальных дисков с типизированным фильтром
+func (d *VdiskService) ListFilteredContext(ctx context.Context, filter VdiskFilter) (*VdisksResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "VdiskService.ListFiltered")
 	response := new(VdisksResponse)
-	res, err := d.client.ExecuteRequest("GET", listUrl, []byte{}, response)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", buildListUrl(baseVdiskUrl, filter), []byte{}, response)
 	return response, res, err
 }
 
+// VdiskIterator постраничный итератор по виртуальным дискам
+type VdiskIterator struct {
+	*Iterator
+	item VdiskObjectsList
+}
+
+// Next переходит к следующему виртуальному диску
+func (it *VdiskIterator) Next() bool {
+	it.item = VdiskObjectsList{}
+	return it.Iterator.Next() && it.decode(&it.item)
+}
+
+// Item возвращает текущий виртуальный диск
+func (it *VdiskIterator) Item() VdiskObjectsList {
+	return it.item
+}
+
+// Iter Итератор по всем виртуальным дискам, подходящим под filter. filter и opts могут быть nil
+func (d *VdiskService) Iter(ctx context.Context, filter ListFilter, opts *PageOptions) *VdiskIterator {
+	ctx = withOperation(ctx, "VdiskService.Iter")
+	return &VdiskIterator{Iterator: newIterator(ctx, d.client, baseVdiskUrl, filter, opts)}
+}
+
+// ListAll Эндпоинт получения всех страниц списка виртуальных дисков
+func (d *VdiskService) ListAll(ctx context.Context, filter ListFilter) ([]VdiskObjectsList, error) {
+	ctx = withOperation(ctx, "VdiskService.ListAll")
+	it := d.Iter(ctx, filter, nil)
+	defer it.Close()
+	var items []VdiskObjectsList
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
+
 // Get Эндпоинт получения информации по диску.
 func (d *VdiskService) Get(Id string) (*VdiskObject, *http.Response, error) {
+	return d.GetContext(context.Background(), Id)
+}
+
+// GetContext Эндпоинт получения информации по диску.
+func (d *VdiskService) GetContext(ctx context.Context, Id string) (*VdiskObject, *http.Response, error) {
+	ctx = withOperation(ctx, "VdiskService.Get")
 
 	vdisk := new(VdiskObject)
-	res, err := d.client.ExecuteRequest("GET", fmt.Sprint(baseVdiskUrl, Id, "/"), []byte{}, vdisk)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", fmt.Sprint(baseVdiskUrl, Id, "/"), []byte{}, vdisk)
 	return vdisk, res, err
 }
 
 // Create Эндпоинт создания виртуального диска
 func (d *VdiskService) Create(config *VdiskCreate, asynced bool) (*VdiskObject, *http.Response, error) {
+	return d.CreateContext(context.Background(), config, asynced)
+}
+
+// CreateContext Эндпоинт создания виртуального диска
+func (d *VdiskService) CreateContext(ctx context.Context, config *VdiskCreate, asynced bool) (*VdiskObject, *http.Response, error) {
 
 	vdisk := new(VdiskObject)
-	b, _ := json.Marshal(config)
 	if !asynced {
-		res, err := d.client.ExecuteRequest("POST", baseVdiskUrl, b, vdisk)
+		ctx = withOperation(config.IdempotencyKeyBase.apply(ctx), "VdiskService.Create")
+		b, _ := json.Marshal(config)
+		res, err := d.client.ExecuteRequestContext(ctx, "POST", baseVdiskUrl, b, vdisk)
 		return vdisk, res, err
 	}
+	ctx, finish := startOperation(ctx, d.client, "VdiskService.Create", "")
+	op, res, err := d.StartCreate(ctx, config)
+	if err != nil {
+		finish(err)
+		return vdisk, res, err
+	}
+	vdisk, err = op.Wait(ctx)
+	finish(err)
+	return vdisk, op.response(res), err
+}
+
+// StartCreate Эндпоинт асинхронного создания виртуального диска без ожидания задачи
+func (d *VdiskService) StartCreate(ctx context.Context, config *VdiskCreate) (*VdiskOperation, *http.Response, error) {
+	ctx = withOperation(ctx, "VdiskService.StartCreate")
+	ctx = config.IdempotencyKeyBase.apply(ctx)
+	b, _ := json.Marshal(config)
 	asyncResp := new(AsyncEntityResponse)
-	res, err := d.client.ExecuteRequest("POST", baseVdiskUrl+"?async=1", b, asyncResp)
-	WaitTaskReady(d.client.RetClient(), asyncResp.Task.Id, true, 0, true)
-	res, err = d.client.ExecuteRequest("GET", fmt.Sprint(baseVdiskUrl, asyncResp.Entity, "/"), []byte{}, vdisk)
-	return vdisk, res, err
+	res, err := d.client.ExecuteRequestContext(ctx, "POST", baseVdiskUrl+"?async=1", b, asyncResp)
+	if err != nil {
+		return nil, res, err
+	}
+	op, err := newVdiskOperation(d.client, OperationState{OperationKind.VdiskCreate, asyncResp.Task.Id, asyncResp.Entity})
+	return op, res, err
+}
+
+// ResumeOperation восстанавливает операцию, начатую StartCreate
+func (d *VdiskService) ResumeOperation(state OperationState) (*VdiskOperation, error) {
+	return newVdiskOperation(d.client, state)
 }
 
 // Update Эндпоинт редактирования информации по диску.
 func (d *VdiskService) Update(Id string, description string) (*VdiskObject, *http.Response, error) {
+	return d.UpdateContext(context.Background(), Id, description)
+}
+
+// UpdateContext Эндпоинт редактирования информации по диску.
+func (d *VdiskService) UpdateContext(ctx context.Context, Id string, description string) (*VdiskObject, *http.Response, error) {
+	ctx = withOperation(ctx, "VdiskService.Update")
 
 	vdisk := new(VdiskObject)
 
@@ -152,26 +268,68 @@ func (d *VdiskService) Update(Id string, description string) (*VdiskObject, *htt
 
 	b, _ := json.Marshal(body)
 
-	res, err := d.client.ExecuteRequest("PUT", fmt.Sprint(baseVdiskUrl, Id, "/"), b, vdisk)
+	res, err := d.client.ExecuteRequestContext(ctx, "PUT", fmt.Sprint(baseVdiskUrl, Id, "/"), b, vdisk)
 
 	return vdisk, res, err
 }
 
 // Extend Эндпоинт увеличения размера виртуального диска
 func (d *VdiskService) Extend(Id string, size float64) (*VdiskObject, *http.Response, error) {
+	return d.ExtendContext(context.Background(), Id, size)
+}
+
+// ExtendContext Эндпоинт увеличения размера виртуального диска
+func (d *VdiskService) ExtendContext(ctx context.Context, Id string, size float64) (*VdiskObject, *http.Response, error) {
+	ctx = withOperation(ctx, "VdiskService.Extend")
 	vdisk := new(VdiskObject)
 	body := struct {
 		Size float64 `json:"size,omitempty"`
 	}{size}
 	b, _ := json.Marshal(body)
-	res, err := d.client.ExecuteRequest("POST", fmt.Sprint(baseVdiskUrl, Id, "/extend/"), b, vdisk)
+	res, err := d.client.ExecuteRequestContext(ctx, "POST", fmt.Sprint(baseVdiskUrl, Id, "/extend/"), b, vdisk)
 	return vdisk, res, err
 }
 
+// Consolidate Эндпоинт слияния цепочки снимков виртуального диска в один образ
+func (d *VdiskService) Consolidate(Id string) (*VdiskObject, *http.Response, error) {
+	return d.ConsolidateContext(context.Background(), Id)
+}
+
+// ConsolidateContext Эндпоинт слияния цепочки снимков виртуального диска в один образ
+func (d *VdiskService) ConsolidateContext(ctx context.Context, Id string) (*VdiskObject, *http.Response, error) {
+	ctx, finish := startOperation(ctx, d.client, "VdiskService.Consolidate", Id)
+	op, res, err := d.StartConsolidate(ctx, Id)
+	if err != nil {
+		finish(err)
+		return new(VdiskObject), res, err
+	}
+	vdisk, err := op.Wait(ctx)
+	finish(err)
+	return vdisk, op.response(res), err
+}
+
+// StartConsolidate Эндпоинт асинхронного слияния снимков виртуального диска без ожидания задачи
+func (d *VdiskService) StartConsolidate(ctx context.Context, Id string) (*VdiskOperation, *http.Response, error) {
+	ctx = withOperation(ctx, "VdiskService.StartConsolidate")
+	asyncResp := new(AsyncEntityResponse)
+	res, err := d.client.ExecuteRequestContext(ctx, "POST", fmt.Sprint(baseVdiskUrl, Id, "/consolidate/?async=1"), []byte{}, asyncResp)
+	if err != nil {
+		return nil, res, err
+	}
+	op, err := newVdiskOperation(d.client, OperationState{OperationKind.VdiskConsolidate, asyncResp.Task.Id, Id})
+	return op, res, err
+}
+
 // Remove Эндпоинт удаления виртуального диска
 func (d *VdiskService) Remove(Id string) (bool, *http.Response, error) {
+	return d.RemoveContext(context.Background(), Id)
+}
 
-	res, err := d.client.ExecuteRequest("POST", fmt.Sprint(baseVdiskUrl, Id, "/remove/"), []byte{}, nil)
+// RemoveContext Эндпоинт удаления виртуального диска
+func (d *VdiskService) RemoveContext(ctx context.Context, Id string) (bool, *http.Response, error) {
+	ctx = withOperation(ctx, "VdiskService.Remove")
+
+	res, err := d.client.ExecuteRequestContext(ctx, "POST", fmt.Sprint(baseVdiskUrl, Id, "/remove/"), []byte{}, nil)
 
 	if err != nil {
 		return false, res, err
@@ -180,7 +338,11 @@ func (d *VdiskService) Remove(Id string) (bool, *http.Response, error) {
 	return true, res, err
 }
 
-func (entity *VdiskObject) Refresh(client *WebClient) (*VdiskObject, error) {
-	_, err := client.ExecuteRequest("GET", fmt.Sprint(baseVdiskUrl, entity.Id, "/"), []byte{}, entity)
+func (entity *VdiskObject) Refresh(client Client) (*VdiskObject, error) {
+	return entity.RefreshContext(context.Background(), client)
+}
+
+func (entity *VdiskObject) RefreshContext(ctx context.Context, client Client) (*VdiskObject, error) {
+	_, err := client.ExecuteRequestContext(ctx, "GET", fmt.Sprint(baseVdiskUrl, entity.Id, "/"), []byte{}, entity)
 	return entity, err
 }
@@ -6,7 +6,7 @@ import (
 )
 
 func Test_VdiskCreateSync(t *testing.T) {
-	client := NewClient("", "", false)
+	_, client := newTestServerClient(t)
 	dpResponse, _, err := client.DataPool.List()
 	assert.Nil(t, err)
 	if len(dpResponse.Results) == 0 {
@@ -48,7 +48,7 @@ func Test_VdiskCreateSync(t *testing.T) {
 }
 
 func Test_VdiskCreateAsync(t *testing.T) {
-	client := NewClient("", "", false)
+	_, client := newTestServerClient(t)
 	dpResponse, _, err := client.DataPool.List()
 	assert.Nil(t, err)
 	if len(dpResponse.Results) == 0 {
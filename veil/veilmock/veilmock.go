@@ -0,0 +1,2369 @@
+// Package veilmock provides mocks of veil service interfaces and veil.Client for unit tests
+// of code depending on a few operations of the client. A mock method calls the function field of the same name,
+// methods without context fall back to the function of their Context variant, and ErrNotMocked is returned
+// if no function is set.
+//
+//	domains := &veilmock.DomainAPI{
+//		GetContextFunc: func(ctx context.Context, Id string) (*veil.DomainObject, *http.Response, error) {
+//			return &veil.DomainObject{Id: Id, Status: veil.Status.Active}, nil, nil
+//		},
+//	}
+//	checkDomain(domains) // accepts veil.DomainAPI
+package veilmock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/jsc-masshtab/veil-api-client-go/veil"
+)
+
+// ErrNotMocked is wrapped by the error returned from a mock method which function is not set
+var ErrNotMocked = errors.New("method is not mocked")
+
+func notMocked(method string) error {
+	return fmt.Errorf("%s: %w", method, ErrNotMocked)
+}
+
+// Client mock of veil.Client, services created with veil.NewDomainService etc. over it run
+// the real logic including async operations on stubbed responses
+type Client struct {
+	ExecuteRequestContextFunc func(ctx context.Context, method, url string, body []byte, object interface{}) (*http.Response, error)
+	ExecuteFunc               func(req *http.Request) (*http.Response, error)
+}
+
+var _ veil.Client = (*Client)(nil)
+
+func (m *Client) ExecuteRequest(method, url string, body []byte, object interface{}) (*http.Response, error) {
+	return m.ExecuteRequestContext(context.Background(), method, url, body, object)
+}
+
+func (m *Client) ExecuteRequestContext(ctx context.Context, method, url string, body []byte, object interface{}) (*http.Response, error) {
+	if m.ExecuteRequestContextFunc != nil {
+		return m.ExecuteRequestContextFunc(ctx, method, url, body, object)
+	}
+	return nil, notMocked("Client.ExecuteRequestContext")
+}
+
+func (m *Client) Execute(req *http.Request) (*http.Response, error) {
+	if m.ExecuteFunc != nil {
+		return m.ExecuteFunc(req)
+	}
+	return nil, notMocked("Client.Execute")
+}
+
+// Respond fills object with value through JSON as the client decodes responses and returns response with status 200.
+// object may be nil
+func Respond(object interface{}, value interface{}) (*http.Response, error) {
+	if object != nil {
+		b, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, object); err != nil {
+			return nil, err
+		}
+	}
+	return &http.Response{StatusCode: http.StatusOK, Status: "200 OK", Header: http.Header{}}, nil
+}
+
+// DomainAPI mock of veil.DomainAPI
+type DomainAPI struct {
+	ListFunc                     func() (*veil.DomainsResponse, *http.Response, error)
+	ListContextFunc              func(ctx context.Context) (*veil.DomainsResponse, *http.Response, error)
+	ListParamsFunc               func(queryParams map[string]string) (*veil.DomainsResponse, *http.Response, error)
+	ListParamsContextFunc        func(ctx context.Context, queryParams map[string]string) (*veil.DomainsResponse, *http.Response, error)
+	CreateFunc                   func(config veil.DomainCreateConfig) (*veil.DomainObject, *http.Response, error)
+	CreateContextFunc            func(ctx context.Context, config veil.DomainCreateConfig) (*veil.DomainObject, *http.Response, error)
+	MultiCreateFunc              func(config veil.DomainMultiCreateConfig) (*veil.DomainObject, *http.Response, error)
+	MultiCreateContextFunc       func(ctx context.Context, config veil.DomainMultiCreateConfig) (*veil.DomainObject, *http.Response, error)
+	StartMultiCreateFunc         func(ctx context.Context, config veil.DomainMultiCreateConfig) (*veil.DomainOperation, *http.Response, error)
+	ListFilteredFunc             func(filter veil.DomainFilter) (*veil.DomainsResponse, *http.Response, error)
+	ListFilteredContextFunc      func(ctx context.Context, filter veil.DomainFilter) (*veil.DomainsResponse, *http.Response, error)
+	IterFunc                     func(ctx context.Context, filter veil.ListFilter, opts *veil.PageOptions) *veil.DomainIterator
+	ListAllFunc                  func(ctx context.Context, filter veil.ListFilter) ([]veil.DomainObjectsList, error)
+	GetFunc                      func(Id string) (*veil.DomainObject, *http.Response, error)
+	GetContextFunc               func(ctx context.Context, Id string) (*veil.DomainObject, *http.Response, error)
+	UpdateFunc                   func(Id string, config veil.DomainUpdateConfig) (*veil.DomainObject, *http.Response, error)
+	UpdateContextFunc            func(ctx context.Context, Id string, config veil.DomainUpdateConfig) (*veil.DomainObject, *http.Response, error)
+	StartFunc                    func(domain *veil.DomainObject) (*veil.DomainObject, *http.Response, error)
+	StartContextFunc             func(ctx context.Context, domain *veil.DomainObject) (*veil.DomainObject, *http.Response, error)
+	SuspendFunc                  func(domain *veil.DomainObject) (*veil.DomainObject, *http.Response, error)
+	SuspendContextFunc           func(ctx context.Context, domain *veil.DomainObject) (*veil.DomainObject, *http.Response, error)
+	ResumeFunc                   func(domain *veil.DomainObject) (*veil.DomainObject, *http.Response, error)
+	ResumeContextFunc            func(ctx context.Context, domain *veil.DomainObject) (*veil.DomainObject, *http.Response, error)
+	ShutdownFunc                 func(domain *veil.DomainObject, force bool) (*veil.DomainObject, *http.Response, error)
+	ShutdownContextFunc          func(ctx context.Context, domain *veil.DomainObject, force bool) (*veil.DomainObject, *http.Response, error)
+	RebootFunc                   func(domain *veil.DomainObject, force bool) (*veil.DomainObject, *http.Response, error)
+	RebootContextFunc            func(ctx context.Context, domain *veil.DomainObject, force bool) (*veil.DomainObject, *http.Response, error)
+	TemplateFunc                 func(domain *veil.DomainObject, template bool) (*veil.DomainObject, *http.Response, error)
+	TemplateContextFunc          func(ctx context.Context, domain *veil.DomainObject, template bool) (*veil.DomainObject, *http.Response, error)
+	CloneFunc                    func(Id string, config veil.DomainCloneConfig) (*veil.DomainObject, *http.Response, error)
+	CloneContextFunc             func(ctx context.Context, Id string, config veil.DomainCloneConfig) (*veil.DomainObject, *http.Response, error)
+	StartCloneFunc               func(ctx context.Context, Id string, config veil.DomainCloneConfig) (*veil.DomainOperation, *http.Response, error)
+	ResumeOperationFunc          func(state veil.OperationState) (*veil.DomainOperation, error)
+	CloudInitFunc                func(domain *veil.DomainObject, config veil.CloudInitConf) (*veil.DomainObject, *http.Response, error)
+	CloudInitContextFunc         func(ctx context.Context, domain *veil.DomainObject, config veil.CloudInitConf) (*veil.DomainObject, *http.Response, error)
+	RemoveFunc                   func(domainID string, full bool, force bool) (bool, *http.Response, error)
+	RemoveContextFunc            func(ctx context.Context, domainID string, full bool, force bool) (bool, *http.Response, error)
+	ListSnapshotsFunc            func(Id string) (*veil.DomainSnapshotsResponse, *http.Response, error)
+	ListSnapshotsContextFunc     func(ctx context.Context, Id string) (*veil.DomainSnapshotsResponse, *http.Response, error)
+	GetSnapshotFunc              func(Id string, snapshotId string) (*veil.DomainSnapshot, *http.Response, error)
+	GetSnapshotContextFunc       func(ctx context.Context, Id string, snapshotId string) (*veil.DomainSnapshot, *http.Response, error)
+	CreateSnapshotFunc           func(Id string, config veil.SnapshotCreateConfig) (*veil.DomainSnapshot, *http.Response, error)
+	CreateSnapshotContextFunc    func(ctx context.Context, Id string, config veil.SnapshotCreateConfig) (*veil.DomainSnapshot, *http.Response, error)
+	StartCreateSnapshotFunc      func(ctx context.Context, Id string, config veil.SnapshotCreateConfig) (*veil.SnapshotOperation, *http.Response, error)
+	ResumeSnapshotOperationFunc  func(state veil.OperationState) (*veil.SnapshotOperation, error)
+	RevertSnapshotFunc           func(Id string, snapshotId string) (*veil.DomainObject, *http.Response, error)
+	RevertSnapshotContextFunc    func(ctx context.Context, Id string, snapshotId string) (*veil.DomainObject, *http.Response, error)
+	StartRevertSnapshotFunc      func(ctx context.Context, Id string, snapshotId string) (*veil.DomainOperation, *http.Response, error)
+	RemoveSnapshotFunc           func(Id string, snapshotId string) (*veil.DomainObject, *http.Response, error)
+	RemoveSnapshotContextFunc    func(ctx context.Context, Id string, snapshotId string) (*veil.DomainObject, *http.Response, error)
+	StartRemoveSnapshotFunc      func(ctx context.Context, Id string, snapshotId string) (*veil.DomainOperation, *http.Response, error)
+	MigrateFunc                  func(Id string, config veil.DomainMigrateConfig) (*veil.DomainObject, *http.Response, error)
+	MigrateContextFunc           func(ctx context.Context, Id string, config veil.DomainMigrateConfig) (*veil.DomainObject, *http.Response, error)
+	StartMigrateFunc             func(ctx context.Context, Id string, config veil.DomainMigrateConfig) (*veil.DomainOperation, *http.Response, error)
+	AttachVdiskFunc              func(Id string, config veil.VdiskAttach) (*veil.DomainObject, *http.Response, error)
+	AttachVdiskContextFunc       func(ctx context.Context, Id string, config veil.VdiskAttach) (*veil.DomainObject, *http.Response, error)
+	DetachVdiskFunc              func(Id string, vdiskId string) (*veil.DomainObject, *http.Response, error)
+	DetachVdiskContextFunc       func(ctx context.Context, Id string, vdiskId string) (*veil.DomainObject, *http.Response, error)
+	CreateAttachVdiskFunc        func(Id string, config veil.VdiskCreateAttach) (*veil.VdiskObject, *http.Response, error)
+	CreateAttachVdiskContextFunc func(ctx context.Context, Id string, config veil.VdiskCreateAttach) (*veil.VdiskObject, *http.Response, error)
+	StartCreateAttachVdiskFunc   func(ctx context.Context, Id string, config veil.VdiskCreateAttach) (*veil.VdiskOperation, *http.Response, error)
+	InsertIsoFunc                func(Id string, config veil.IsoAttach) (*veil.DomainObject, *http.Response, error)
+	InsertIsoContextFunc         func(ctx context.Context, Id string, config veil.IsoAttach) (*veil.DomainObject, *http.Response, error)
+	EjectIsoFunc                 func(Id string, cdromId string) (*veil.DomainObject, *http.Response, error)
+	EjectIsoContextFunc          func(ctx context.Context, Id string, cdromId string) (*veil.DomainObject, *http.Response, error)
+	AddInterfaceFunc             func(Id string, config veil.VMachineInfSoftCreate) (*veil.VMachineInfObject, *http.Response, error)
+	AddInterfaceContextFunc      func(ctx context.Context, Id string, config veil.VMachineInfSoftCreate) (*veil.VMachineInfObject, *http.Response, error)
+	RemoveInterfaceFunc          func(Id string, vmachineInfId string) (*veil.DomainObject, *http.Response, error)
+	RemoveInterfaceContextFunc   func(ctx context.Context, Id string, vmachineInfId string) (*veil.DomainObject, *http.Response, error)
+	RelinkInterfaceFunc          func(Id string, vmachineInfId string, vnetworkId string) (*veil.VMachineInfObject, *http.Response, error)
+	RelinkInterfaceContextFunc   func(ctx context.Context, Id string, vmachineInfId string, vnetworkId string) (*veil.VMachineInfObject, *http.Response, error)
+	ListVdisksFunc               func(ctx context.Context, Id string) ([]veil.VdiskObjectsList, error)
+	ListCdromsFunc               func(ctx context.Context, Id string) ([]veil.CdromObject, error)
+	ListInterfacesFunc           func(ctx context.Context, Id string) ([]veil.VMachineInfObjectsList, error)
+	UpdateCpuFunc                func(domain *veil.DomainObject, config veil.DomainCpuConfig) (*veil.ResourcePlan, *http.Response, error)
+	UpdateCpuContextFunc         func(ctx context.Context, domain *veil.DomainObject, config veil.DomainCpuConfig) (*veil.ResourcePlan, *http.Response, error)
+	UpdateMemoryFunc             func(domain *veil.DomainObject, config veil.DomainMemoryConfig) (*veil.ResourcePlan, *http.Response, error)
+	UpdateMemoryContextFunc      func(ctx context.Context, domain *veil.DomainObject, config veil.DomainMemoryConfig) (*veil.ResourcePlan, *http.Response, error)
+	RemoteAccessFunc             func(domain *veil.DomainObject, enabled bool) (*veil.DomainObject, *http.Response, error)
+	RemoteAccessContextFunc      func(ctx context.Context, domain *veil.DomainObject, enabled bool) (*veil.DomainObject, *http.Response, error)
+	ConsoleFunc                  func(Id string, protocol string) (*veil.ConsoleConnection, *http.Response, error)
+	ConsoleContextFunc           func(ctx context.Context, Id string, protocol string) (*veil.ConsoleConnection, *http.Response, error)
+}
+
+var _ veil.DomainAPI = (*DomainAPI)(nil)
+
+func (m *DomainAPI) List() (*veil.DomainsResponse, *http.Response, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc()
+	}
+	if m.ListContextFunc != nil {
+		return m.ListContextFunc(context.Background())
+	}
+	return nil, nil, notMocked("DomainAPI.List")
+}
+
+func (m *DomainAPI) ListContext(ctx context.Context) (*veil.DomainsResponse, *http.Response, error) {
+	if m.ListContextFunc != nil {
+		return m.ListContextFunc(ctx)
+	}
+	return nil, nil, notMocked("DomainAPI.ListContext")
+}
+
+func (m *DomainAPI) ListParams(queryParams map[string]string) (*veil.DomainsResponse, *http.Response, error) {
+	if m.ListParamsFunc != nil {
+		return m.ListParamsFunc(queryParams)
+	}
+	if m.ListParamsContextFunc != nil {
+		return m.ListParamsContextFunc(context.Background(), queryParams)
+	}
+	return nil, nil, notMocked("DomainAPI.ListParams")
+}
+
+func (m *DomainAPI) ListParamsContext(ctx context.Context, queryParams map[string]string) (*veil.DomainsResponse, *http.Response, error) {
+	if m.ListParamsContextFunc != nil {
+		return m.ListParamsContextFunc(ctx, queryParams)
+	}
+	return nil, nil, notMocked("DomainAPI.ListParamsContext")
+}
+
+func (m *DomainAPI) Create(config veil.DomainCreateConfig) (*veil.DomainObject, *http.Response, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(config)
+	}
+	if m.CreateContextFunc != nil {
+		return m.CreateContextFunc(context.Background(), config)
+	}
+	return nil, nil, notMocked("DomainAPI.Create")
+}
+
+func (m *DomainAPI) CreateContext(ctx context.Context, config veil.DomainCreateConfig) (*veil.DomainObject, *http.Response, error) {
+	if m.CreateContextFunc != nil {
+		return m.CreateContextFunc(ctx, config)
+	}
+	return nil, nil, notMocked("DomainAPI.CreateContext")
+}
+
+func (m *DomainAPI) MultiCreate(config veil.DomainMultiCreateConfig) (*veil.DomainObject, *http.Response, error) {
+	if m.MultiCreateFunc != nil {
+		return m.MultiCreateFunc(config)
+	}
+	if m.MultiCreateContextFunc != nil {
+		return m.MultiCreateContextFunc(context.Background(), config)
+	}
+	return nil, nil, notMocked("DomainAPI.MultiCreate")
+}
+
+func (m *DomainAPI) MultiCreateContext(ctx context.Context, config veil.DomainMultiCreateConfig) (*veil.DomainObject, *http.Response, error) {
+	if m.MultiCreateContextFunc != nil {
+		return m.MultiCreateContextFunc(ctx, config)
+	}
+	return nil, nil, notMocked("DomainAPI.MultiCreateContext")
+}
+
+func (m *DomainAPI) StartMultiCreate(ctx context.Context, config veil.DomainMultiCreateConfig) (*veil.DomainOperation, *http.Response, error) {
+	if m.StartMultiCreateFunc != nil {
+		return m.StartMultiCreateFunc(ctx, config)
+	}
+	return nil, nil, notMocked("DomainAPI.StartMultiCreate")
+}
+
+func (m *DomainAPI) ListFiltered(filter veil.DomainFilter) (*veil.DomainsResponse, *http.Response, error) {
+	if m.ListFilteredFunc != nil {
+		return m.ListFilteredFunc(filter)
+	}
+	if m.ListFilteredContextFunc != nil {
+		return m.ListFilteredContextFunc(context.Background(), filter)
+	}
+	return nil, nil, notMocked("DomainAPI.ListFiltered")
+}
+
+func (m *DomainAPI) ListFilteredContext(ctx context.Context, filter veil.DomainFilter) (*veil.DomainsResponse, *http.Response, error) {
+	if m.ListFilteredContextFunc != nil {
+		return m.ListFilteredContextFunc(ctx, filter)
+	}
+	return nil, nil, notMocked("DomainAPI.ListFilteredContext")
+}
+
+func (m *DomainAPI) Iter(ctx context.Context, filter veil.ListFilter, opts *veil.PageOptions) *veil.DomainIterator {
+	if m.IterFunc != nil {
+		return m.IterFunc(ctx, filter, opts)
+	}
+	return nil
+}
+
+func (m *DomainAPI) ListAll(ctx context.Context, filter veil.ListFilter) ([]veil.DomainObjectsList, error) {
+	if m.ListAllFunc != nil {
+		return m.ListAllFunc(ctx, filter)
+	}
+	return nil, notMocked("DomainAPI.ListAll")
+}
+
+func (m *DomainAPI) Get(Id string) (*veil.DomainObject, *http.Response, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(Id)
+	}
+	if m.GetContextFunc != nil {
+		return m.GetContextFunc(context.Background(), Id)
+	}
+	return nil, nil, notMocked("DomainAPI.Get")
+}
+
+func (m *DomainAPI) GetContext(ctx context.Context, Id string) (*veil.DomainObject, *http.Response, error) {
+	if m.GetContextFunc != nil {
+		return m.GetContextFunc(ctx, Id)
+	}
+	return nil, nil, notMocked("DomainAPI.GetContext")
+}
+
+func (m *DomainAPI) Update(Id string, config veil.DomainUpdateConfig) (*veil.DomainObject, *http.Response, error) {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(Id, config)
+	}
+	if m.UpdateContextFunc != nil {
+		return m.UpdateContextFunc(context.Background(), Id, config)
+	}
+	return nil, nil, notMocked("DomainAPI.Update")
+}
+
+func (m *DomainAPI) UpdateContext(ctx context.Context, Id string, config veil.DomainUpdateConfig) (*veil.DomainObject, *http.Response, error) {
+	if m.UpdateContextFunc != nil {
+		return m.UpdateContextFunc(ctx, Id, config)
+	}
+	return nil, nil, notMocked("DomainAPI.UpdateContext")
+}
+
+func (m *DomainAPI) Start(domain *veil.DomainObject) (*veil.DomainObject, *http.Response, error) {
+	if m.StartFunc != nil {
+		return m.StartFunc(domain)
+	}
+	if m.StartContextFunc != nil {
+		return m.StartContextFunc(context.Background(), domain)
+	}
+	return nil, nil, notMocked("DomainAPI.Start")
+}
+
+func (m *DomainAPI) StartContext(ctx context.Context, domain *veil.DomainObject) (*veil.DomainObject, *http.Response, error) {
+	if m.StartContextFunc != nil {
+		return m.StartContextFunc(ctx, domain)
+	}
+	return nil, nil, notMocked("DomainAPI.StartContext")
+}
+
+func (m *DomainAPI) Suspend(domain *veil.DomainObject) (*veil.DomainObject, *http.Response, error) {
+	if m.SuspendFunc != nil {
+		return m.SuspendFunc(domain)
+	}
+	if m.SuspendContextFunc != nil {
+		return m.SuspendContextFunc(context.Background(), domain)
+	}
+	return nil, nil, notMocked("DomainAPI.Suspend")
+}
+
+func (m *DomainAPI) SuspendContext(ctx context.Context, domain *veil.DomainObject) (*veil.DomainObject, *http.Response, error) {
+	if m.SuspendContextFunc != nil {
+		return m.SuspendContextFunc(ctx, domain)
+	}
+	return nil, nil, notMocked("DomainAPI.SuspendContext")
+}
+
+func (m *DomainAPI) Resume(domain *veil.DomainObject) (*veil.DomainObject, *http.Response, error) {
+	if m.ResumeFunc != nil {
+		return m.ResumeFunc(domain)
+	}
+	if m.ResumeContextFunc != nil {
+		return m.ResumeContextFunc(context.Background(), domain)
+	}
+	return nil, nil, notMocked("DomainAPI.Resume")
+}
+
+func (m *DomainAPI) ResumeContext(ctx context.Context, domain *veil.DomainObject) (*veil.DomainObject, *http.Response, error) {
+	if m.ResumeContextFunc != nil {
+		return m.ResumeContextFunc(ctx, domain)
+	}
+	return nil, nil, notMocked("DomainAPI.ResumeContext")
+}
+
+func (m *DomainAPI) Shutdown(domain *veil.DomainObject, force bool) (*veil.DomainObject, *http.Response, error) {
+	if m.ShutdownFunc != nil {
+		return m.ShutdownFunc(domain, force)
+	}
+	if m.ShutdownContextFunc != nil {
+		return m.ShutdownContextFunc(context.Background(), domain, force)
+	}
+	return nil, nil, notMocked("DomainAPI.Shutdown")
+}
+
+func (m *DomainAPI) ShutdownContext(ctx context.Context, domain *veil.DomainObject, force bool) (*veil.DomainObject, *http.Response, error) {
+	if m.ShutdownContextFunc != nil {
+		return m.ShutdownContextFunc(ctx, domain, force)
+	}
+	return nil, nil, notMocked("DomainAPI.ShutdownContext")
+}
+
+func (m *DomainAPI) Reboot(domain *veil.DomainObject, force bool) (*veil.DomainObject, *http.Response, error) {
+	if m.RebootFunc != nil {
+		return m.RebootFunc(domain, force)
+	}
+	if m.RebootContextFunc != nil {
+		return m.RebootContextFunc(context.Background(), domain, force)
+	}
+	return nil, nil, notMocked("DomainAPI.Reboot")
+}
+
+func (m *DomainAPI) RebootContext(ctx context.Context, domain *veil.DomainObject, force bool) (*veil.DomainObject, *http.Response, error) {
+	if m.RebootContextFunc != nil {
+		return m.RebootContextFunc(ctx, domain, force)
+	}
+	return nil, nil, notMocked("DomainAPI.RebootContext")
+}
+
+func (m *DomainAPI) Template(domain *veil.DomainObject, template bool) (*veil.DomainObject, *http.Response, error) {
+	if m.TemplateFunc != nil {
+		return m.TemplateFunc(domain, template)
+	}
+	if m.TemplateContextFunc != nil {
+		return m.TemplateContextFunc(context.Background(), domain, template)
+	}
+	return nil, nil, notMocked("DomainAPI.Template")
+}
+
+func (m *DomainAPI) TemplateContext(ctx context.Context, domain *veil.DomainObject, template bool) (*veil.DomainObject, *http.Response, error) {
+	if m.TemplateContextFunc != nil {
+		return m.TemplateContextFunc(ctx, domain, template)
+	}
+	return nil, nil, notMocked("DomainAPI.TemplateContext")
+}
+
+func (m *DomainAPI) Clone(Id string, config veil.DomainCloneConfig) (*veil.DomainObject, *http.Response, error) {
+	if m.CloneFunc != nil {
+		return m.CloneFunc(Id, config)
+	}
+	if m.CloneContextFunc != nil {
+		return m.CloneContextFunc(context.Background(), Id, config)
+	}
+	return nil, nil, notMocked("DomainAPI.Clone")
+}
+
+func (m *DomainAPI) CloneContext(ctx context.Context, Id string, config veil.DomainCloneConfig) (*veil.DomainObject, *http.Response, error) {
+	if m.CloneContextFunc != nil {
+		return m.CloneContextFunc(ctx, Id, config)
+	}
+	return nil, nil, notMocked("DomainAPI.CloneContext")
+}
+
+func (m *DomainAPI) StartClone(ctx context.Context, Id string, config veil.DomainCloneConfig) (*veil.DomainOperation, *http.Response, error) {
+	if m.StartCloneFunc != nil {
+		return m.StartCloneFunc(ctx, Id, config)
+	}
+	return nil, nil, notMocked("DomainAPI.StartClone")
+}
+
+func (m *DomainAPI) ResumeOperation(state veil.OperationState) (*veil.DomainOperation, error) {
+	if m.ResumeOperationFunc != nil {
+		return m.ResumeOperationFunc(state)
+	}
+	return nil, notMocked("DomainAPI.ResumeOperation")
+}
+
+func (m *DomainAPI) CloudInit(domain *veil.DomainObject, config veil.CloudInitConf) (*veil.DomainObject, *http.Response, error) {
+	if m.CloudInitFunc != nil {
+		return m.CloudInitFunc(domain, config)
+	}
+	if m.CloudInitContextFunc != nil {
+		return m.CloudInitContextFunc(context.Background(), domain, config)
+	}
+	return nil, nil, notMocked("DomainAPI.CloudInit")
+}
+
+func (m *DomainAPI) CloudInitContext(ctx context.Context, domain *veil.DomainObject, config veil.CloudInitConf) (*veil.DomainObject, *http.Response, error) {
+	if m.CloudInitContextFunc != nil {
+		return m.CloudInitContextFunc(ctx, domain, config)
+	}
+	return nil, nil, notMocked("DomainAPI.CloudInitContext")
+}
+
+func (m *DomainAPI) Remove(domainID string, full bool, force bool) (bool, *http.Response, error) {
+	if m.RemoveFunc != nil {
+		return m.RemoveFunc(domainID, full, force)
+	}
+	if m.RemoveContextFunc != nil {
+		return m.RemoveContextFunc(context.Background(), domainID, full, force)
+	}
+	return false, nil, notMocked("DomainAPI.Remove")
+}
+
+func (m *DomainAPI) RemoveContext(ctx context.Context, domainID string, full bool, force bool) (bool, *http.Response, error) {
+	if m.RemoveContextFunc != nil {
+		return m.RemoveContextFunc(ctx, domainID, full, force)
+	}
+	return false, nil, notMocked("DomainAPI.RemoveContext")
+}
+
+func (m *DomainAPI) ListSnapshots(Id string) (*veil.DomainSnapshotsResponse, *http.Response, error) {
+	if m.ListSnapshotsFunc != nil {
+		return m.ListSnapshotsFunc(Id)
+	}
+	if m.ListSnapshotsContextFunc != nil {
+		return m.ListSnapshotsContextFunc(context.Background(), Id)
+	}
+	return nil, nil, notMocked("DomainAPI.ListSnapshots")
+}
+
+func (m *DomainAPI) ListSnapshotsContext(ctx context.Context, Id string) (*veil.DomainSnapshotsResponse, *http.Response, error) {
+	if m.ListSnapshotsContextFunc != nil {
+		return m.ListSnapshotsContextFunc(ctx, Id)
+	}
+	return nil, nil, notMocked("DomainAPI.ListSnapshotsContext")
+}
+
+func (m *DomainAPI) GetSnapshot(Id string, snapshotId string) (*veil.DomainSnapshot, *http.Response, error) {
+	if m.GetSnapshotFunc != nil {
+		return m.GetSnapshotFunc(Id, snapshotId)
+	}
+	if m.GetSnapshotContextFunc != nil {
+		return m.GetSnapshotContextFunc(context.Background(), Id, snapshotId)
+	}
+	return nil, nil, notMocked("DomainAPI.GetSnapshot")
+}
+
+func (m *DomainAPI) GetSnapshotContext(ctx context.Context, Id string, snapshotId string) (*veil.DomainSnapshot, *http.Response, error) {
+	if m.GetSnapshotContextFunc != nil {
+		return m.GetSnapshotContextFunc(ctx, Id, snapshotId)
+	}
+	return nil, nil, notMocked("DomainAPI.GetSnapshotContext")
+}
+
+func (m *DomainAPI) CreateSnapshot(Id string, config veil.SnapshotCreateConfig) (*veil.DomainSnapshot, *http.Response, error) {
+	if m.CreateSnapshotFunc != nil {
+		return m.CreateSnapshotFunc(Id, config)
+	}
+	if m.CreateSnapshotContextFunc != nil {
+		return m.CreateSnapshotContextFunc(context.Background(), Id, config)
+	}
+	return nil, nil, notMocked("DomainAPI.CreateSnapshot")
+}
+
+func (m *DomainAPI) CreateSnapshotContext(ctx context.Context, Id string, config veil.SnapshotCreateConfig) (*veil.DomainSnapshot, *http.Response, error) {
+	if m.CreateSnapshotContextFunc != nil {
+		return m.CreateSnapshotContextFunc(ctx, Id, config)
+	}
+	return nil, nil, notMocked("DomainAPI.CreateSnapshotContext")
+}
+
+func (m *DomainAPI) StartCreateSnapshot(ctx context.Context, Id string, config veil.SnapshotCreateConfig) (*veil.SnapshotOperation, *http.Response, error) {
+	if m.StartCreateSnapshotFunc != nil {
+		return m.StartCreateSnapshotFunc(ctx, Id, config)
+	}
+	return nil, nil, notMocked("DomainAPI.StartCreateSnapshot")
+}
+
+func (m *DomainAPI) ResumeSnapshotOperation(state veil.OperationState) (*veil.SnapshotOperation, error) {
+	if m.ResumeSnapshotOperationFunc != nil {
+		return m.ResumeSnapshotOperationFunc(state)
+	}
+	return nil, notMocked("DomainAPI.ResumeSnapshotOperation")
+}
+
+func (m *DomainAPI) RevertSnapshot(Id string, snapshotId string) (*veil.DomainObject, *http.Response, error) {
+	if m.RevertSnapshotFunc != nil {
+		return m.RevertSnapshotFunc(Id, snapshotId)
+	}
+	if m.RevertSnapshotContextFunc != nil {
+		return m.RevertSnapshotContextFunc(context.Background(), Id, snapshotId)
+	}
+	return nil, nil, notMocked("DomainAPI.RevertSnapshot")
+}
+
+func (m *DomainAPI) RevertSnapshotContext(ctx context.Context, Id string, snapshotId string) (*veil.DomainObject, *http.Response, error) {
+	if m.RevertSnapshotContextFunc != nil {
+		return m.RevertSnapshotContextFunc(ctx, Id, snapshotId)
+	}
+	return nil, nil, notMocked("DomainAPI.RevertSnapshotContext")
+}
+
+func (m *DomainAPI) StartRevertSnapshot(ctx context.Context, Id string, snapshotId string) (*veil.DomainOperation, *http.Response, error) {
+	if m.StartRevertSnapshotFunc != nil {
+		return m.StartRevertSnapshotFunc(ctx, Id, snapshotId)
+	}
+	return nil, nil, notMocked("DomainAPI.StartRevertSnapshot")
+}
+
+func (m *DomainAPI) RemoveSnapshot(Id string, snapshotId string) (*veil.DomainObject, *http.Response, error) {
+	if m.RemoveSnapshotFunc != nil {
+		return m.RemoveSnapshotFunc(Id, snapshotId)
+	}
+	if m.RemoveSnapshotContextFunc != nil {
+		return m.RemoveSnapshotContextFunc(context.Background(), Id, snapshotId)
+	}
+	return nil, nil, notMocked("DomainAPI.RemoveSnapshot")
+}
+
+func (m *DomainAPI) RemoveSnapshotContext(ctx context.Context, Id string, snapshotId string) (*veil.DomainObject, *http.Response, error) {
+	if m.RemoveSnapshotContextFunc != nil {
+		return m.RemoveSnapshotContextFunc(ctx, Id, snapshotId)
+	}
+	return nil, nil, notMocked("DomainAPI.RemoveSnapshotContext")
+}
+
+func (m *DomainAPI) StartRemoveSnapshot(ctx context.Context, Id string, snapshotId string) (*veil.DomainOperation, *http.Response, error) {
+	if m.StartRemoveSnapshotFunc != nil {
+		return m.StartRemoveSnapshotFunc(ctx, Id, snapshotId)
+	}
+	return nil, nil, notMocked("DomainAPI.StartRemoveSnapshot")
+}
+
+func (m *DomainAPI) Migrate(Id string, config veil.DomainMigrateConfig) (*veil.DomainObject, *http.Response, error) {
+	if m.MigrateFunc != nil {
+		return m.MigrateFunc(Id, config)
+	}
+	if m.MigrateContextFunc != nil {
+		return m.MigrateContextFunc(context.Background(), Id, config)
+	}
+	return nil, nil, notMocked("DomainAPI.Migrate")
+}
+
+func (m *DomainAPI) MigrateContext(ctx context.Context, Id string, config veil.DomainMigrateConfig) (*veil.DomainObject, *http.Response, error) {
+	if m.MigrateContextFunc != nil {
+		return m.MigrateContextFunc(ctx, Id, config)
+	}
+	return nil, nil, notMocked("DomainAPI.MigrateContext")
+}
+
+func (m *DomainAPI) StartMigrate(ctx context.Context, Id string, config veil.DomainMigrateConfig) (*veil.DomainOperation, *http.Response, error) {
+	if m.StartMigrateFunc != nil {
+		return m.StartMigrateFunc(ctx, Id, config)
+	}
+	return nil, nil, notMocked("DomainAPI.StartMigrate")
+}
+
+func (m *DomainAPI) AttachVdisk(Id string, config veil.VdiskAttach) (*veil.DomainObject, *http.Response, error) {
+	if m.AttachVdiskFunc != nil {
+		return m.AttachVdiskFunc(Id, config)
+	}
+	if m.AttachVdiskContextFunc != nil {
+		return m.AttachVdiskContextFunc(context.Background(), Id, config)
+	}
+	return nil, nil, notMocked("DomainAPI.AttachVdisk")
+}
+
+func (m *DomainAPI) AttachVdiskContext(ctx context.Context, Id string, config veil.VdiskAttach) (*veil.DomainObject, *http.Response, error) {
+	if m.AttachVdiskContextFunc != nil {
+		return m.AttachVdiskContextFunc(ctx, Id, config)
+	}
+	return nil, nil, notMocked("DomainAPI.AttachVdiskContext")
+}
+
+func (m *DomainAPI) DetachVdisk(Id string, vdiskId string) (*veil.DomainObject, *http.Response, error) {
+	if m.DetachVdiskFunc != nil {
+		return m.DetachVdiskFunc(Id, vdiskId)
+	}
+	if m.DetachVdiskContextFunc != nil {
+		return m.DetachVdiskContextFunc(context.Background(), Id, vdiskId)
+	}
+	return nil, nil, notMocked("DomainAPI.DetachVdisk")
+}
+
+func (m *DomainAPI) DetachVdiskContext(ctx context.Context, Id string, vdiskId string) (*veil.DomainObject, *http.Response, error) {
+	if m.DetachVdiskContextFunc != nil {
+		return m.DetachVdiskContextFunc(ctx, Id, vdiskId)
+	}
+	return nil, nil, notMocked("DomainAPI.DetachVdiskContext")
+}
+
+func (m *DomainAPI) CreateAttachVdisk(Id string, config veil.VdiskCreateAttach) (*veil.VdiskObject, *http.Response, error) {
+	if m.CreateAttachVdiskFunc != nil {
+		return m.CreateAttachVdiskFunc(Id, config)
+	}
+	if m.CreateAttachVdiskContextFunc != nil {
+		return m.CreateAttachVdiskContextFunc(context.Background(), Id, config)
+	}
+	return nil, nil, notMocked("DomainAPI.CreateAttachVdisk")
+}
+
+func (m *DomainAPI) CreateAttachVdiskContext(ctx context.Context, Id string, config veil.VdiskCreateAttach) (*veil.VdiskObject, *http.Response, error) {
+	if m.CreateAttachVdiskContextFunc != nil {
+		return m.CreateAttachVdiskContextFunc(ctx, Id, config)
+	}
+	return nil, nil, notMocked("DomainAPI.CreateAttachVdiskContext")
+}
+
+func (m *DomainAPI) StartCreateAttachVdisk(ctx context.Context, Id string, config veil.VdiskCreateAttach) (*veil.VdiskOperation, *http.Response, error) {
+	if m.StartCreateAttachVdiskFunc != nil {
+		return m.StartCreateAttachVdiskFunc(ctx, Id, config)
+	}
+	return nil, nil, notMocked("DomainAPI.StartCreateAttachVdisk")
+}
+
+func (m *DomainAPI) InsertIso(Id string, config veil.IsoAttach) (*veil.DomainObject, *http.Response, error) {
+	if m.InsertIsoFunc != nil {
+		return m.InsertIsoFunc(Id, config)
+	}
+	if m.InsertIsoContextFunc != nil {
+		return m.InsertIsoContextFunc(context.Background(), Id, config)
+	}
+	return nil, nil, notMocked("DomainAPI.InsertIso")
+}
+
+func (m *DomainAPI) InsertIsoContext(ctx context.Context, Id string, config veil.IsoAttach) (*veil.DomainObject, *http.Response, error) {
+	if m.InsertIsoContextFunc != nil {
+		return m.InsertIsoContextFunc(ctx, Id, config)
+	}
+	return nil, nil, notMocked("DomainAPI.InsertIsoContext")
+}
+
+func (m *DomainAPI) EjectIso(Id string, cdromId string) (*veil.DomainObject, *http.Response, error) {
+	if m.EjectIsoFunc != nil {
+		return m.EjectIsoFunc(Id, cdromId)
+	}
+	if m.EjectIsoContextFunc != nil {
+		return m.EjectIsoContextFunc(context.Background(), Id, cdromId)
+	}
+	return nil, nil, notMocked("DomainAPI.EjectIso")
+}
+
+func (m *DomainAPI) EjectIsoContext(ctx context.Context, Id string, cdromId string) (*veil.DomainObject, *http.Response, error) {
+	if m.EjectIsoContextFunc != nil {
+		return m.EjectIsoContextFunc(ctx, Id, cdromId)
+	}
+	return nil, nil, notMocked("DomainAPI.EjectIsoContext")
+}
+
+func (m *DomainAPI) AddInterface(Id string, config veil.VMachineInfSoftCreate) (*veil.VMachineInfObject, *http.Response, error) {
+	if m.AddInterfaceFunc != nil {
+		return m.AddInterfaceFunc(Id, config)
+	}
+	if m.AddInterfaceContextFunc != nil {
+		return m.AddInterfaceContextFunc(context.Background(), Id, config)
+	}
+	return nil, nil, notMocked("DomainAPI.AddInterface")
+}
+
+func (m *DomainAPI) AddInterfaceContext(ctx context.Context, Id string, config veil.VMachineInfSoftCreate) (*veil.VMachineInfObject, *http.Response, error) {
+	if m.AddInterfaceContextFunc != nil {
+		return m.AddInterfaceContextFunc(ctx, Id, config)
+	}
+	return nil, nil, notMocked("DomainAPI.AddInterfaceContext")
+}
+
+func (m *DomainAPI) RemoveInterface(Id string, vmachineInfId string) (*veil.DomainObject, *http.Response, error) {
+	if m.RemoveInterfaceFunc != nil {
+		return m.RemoveInterfaceFunc(Id, vmachineInfId)
+	}
+	if m.RemoveInterfaceContextFunc != nil {
+		return m.RemoveInterfaceContextFunc(context.Background(), Id, vmachineInfId)
+	}
+	return nil, nil, notMocked("DomainAPI.RemoveInterface")
+}
+
+func (m *DomainAPI) RemoveInterfaceContext(ctx context.Context, Id string, vmachineInfId string) (*veil.DomainObject, *http.Response, error) {
+	if m.RemoveInterfaceContextFunc != nil {
+		return m.RemoveInterfaceContextFunc(ctx, Id, vmachineInfId)
+	}
+	return nil, nil, notMocked("DomainAPI.RemoveInterfaceContext")
+}
+
+func (m *DomainAPI) RelinkInterface(Id string, vmachineInfId string, vnetworkId string) (*veil.VMachineInfObject, *http.Response, error) {
+	if m.RelinkInterfaceFunc != nil {
+		return m.RelinkInterfaceFunc(Id, vmachineInfId, vnetworkId)
+	}
+	if m.RelinkInterfaceContextFunc != nil {
+		return m.RelinkInterfaceContextFunc(context.Background(), Id, vmachineInfId, vnetworkId)
+	}
+	return nil, nil, notMocked("DomainAPI.RelinkInterface")
+}
+
+func (m *DomainAPI) RelinkInterfaceContext(ctx context.Context, Id string, vmachineInfId string, vnetworkId string) (*veil.VMachineInfObject, *http.Response, error) {
+	if m.RelinkInterfaceContextFunc != nil {
+		return m.RelinkInterfaceContextFunc(ctx, Id, vmachineInfId, vnetworkId)
+	}
+	return nil, nil, notMocked("DomainAPI.RelinkInterfaceContext")
+}
+
+func (m *DomainAPI) ListVdisks(ctx context.Context, Id string) ([]veil.VdiskObjectsList, error) {
+	if m.ListVdisksFunc != nil {
+		return m.ListVdisksFunc(ctx, Id)
+	}
+	return nil, notMocked("DomainAPI.ListVdisks")
+}
+
+func (m *DomainAPI) ListCdroms(ctx context.Context, Id string) ([]veil.CdromObject, error) {
+	if m.ListCdromsFunc != nil {
+		return m.ListCdromsFunc(ctx, Id)
+	}
+	return nil, notMocked("DomainAPI.ListCdroms")
+}
+
+func (m *DomainAPI) ListInterfaces(ctx context.Context, Id string) ([]veil.VMachineInfObjectsList, error) {
+	if m.ListInterfacesFunc != nil {
+		return m.ListInterfacesFunc(ctx, Id)
+	}
+	return nil, notMocked("DomainAPI.ListInterfaces")
+}
+
+func (m *DomainAPI) UpdateCpu(domain *veil.DomainObject, config veil.DomainCpuConfig) (*veil.ResourcePlan, *http.Response, error) {
+	if m.UpdateCpuFunc != nil {
+		return m.UpdateCpuFunc(domain, config)
+	}
+	if m.UpdateCpuContextFunc != nil {
+		return m.UpdateCpuContextFunc(context.Background(), domain, config)
+	}
+	return nil, nil, notMocked("DomainAPI.UpdateCpu")
+}
+
+func (m *DomainAPI) UpdateCpuContext(ctx context.Context, domain *veil.DomainObject, config veil.DomainCpuConfig) (*veil.ResourcePlan, *http.Response, error) {
+	if m.UpdateCpuContextFunc != nil {
+		return m.UpdateCpuContextFunc(ctx, domain, config)
+	}
+	return nil, nil, notMocked("DomainAPI.UpdateCpuContext")
+}
+
+func (m *DomainAPI) UpdateMemory(domain *veil.DomainObject, config veil.DomainMemoryConfig) (*veil.ResourcePlan, *http.Response, error) {
+	if m.UpdateMemoryFunc != nil {
+		return m.UpdateMemoryFunc(domain, config)
+	}
+	if m.UpdateMemoryContextFunc != nil {
+		return m.UpdateMemoryContextFunc(context.Background(), domain, config)
+	}
+	return nil, nil, notMocked("DomainAPI.UpdateMemory")
+}
+
+func (m *DomainAPI) UpdateMemoryContext(ctx context.Context, domain *veil.DomainObject, config veil.DomainMemoryConfig) (*veil.ResourcePlan, *http.Response, error) {
+	if m.UpdateMemoryContextFunc != nil {
+		return m.UpdateMemoryContextFunc(ctx, domain, config)
+	}
+	return nil, nil, notMocked("DomainAPI.UpdateMemoryContext")
+}
+
+func (m *DomainAPI) RemoteAccess(domain *veil.DomainObject, enabled bool) (*veil.DomainObject, *http.Response, error) {
+	if m.RemoteAccessFunc != nil {
+		return m.RemoteAccessFunc(domain, enabled)
+	}
+	if m.RemoteAccessContextFunc != nil {
+		return m.RemoteAccessContextFunc(context.Background(), domain, enabled)
+	}
+	return nil, nil, notMocked("DomainAPI.RemoteAccess")
+}
+
+func (m *DomainAPI) RemoteAccessContext(ctx context.Context, domain *veil.DomainObject, enabled bool) (*veil.DomainObject, *http.Response, error) {
+	if m.RemoteAccessContextFunc != nil {
+		return m.RemoteAccessContextFunc(ctx, domain, enabled)
+	}
+	return nil, nil, notMocked("DomainAPI.RemoteAccessContext")
+}
+
+func (m *DomainAPI) Console(Id string, protocol string) (*veil.ConsoleConnection, *http.Response, error) {
+	if m.ConsoleFunc != nil {
+		return m.ConsoleFunc(Id, protocol)
+	}
+	if m.ConsoleContextFunc != nil {
+		return m.ConsoleContextFunc(context.Background(), Id, protocol)
+	}
+	return nil, nil, notMocked("DomainAPI.Console")
+}
+
+func (m *DomainAPI) ConsoleContext(ctx context.Context, Id string, protocol string) (*veil.ConsoleConnection, *http.Response, error) {
+	if m.ConsoleContextFunc != nil {
+		return m.ConsoleContextFunc(ctx, Id, protocol)
+	}
+	return nil, nil, notMocked("DomainAPI.ConsoleContext")
+}
+
+// NodeAPI mock of veil.NodeAPI
+type NodeAPI struct {
+	ListFunc                func() (*veil.NodesResponse, *http.Response, error)
+	ListContextFunc         func(ctx context.Context) (*veil.NodesResponse, *http.Response, error)
+	ListParamsFunc          func(queryParams map[string]string) (*veil.NodesResponse, *http.Response, error)
+	ListParamsContextFunc   func(ctx context.Context, queryParams map[string]string) (*veil.NodesResponse, *http.Response, error)
+	ListFilteredFunc        func(filter veil.NodeFilter) (*veil.NodesResponse, *http.Response, error)
+	ListFilteredContextFunc func(ctx context.Context, filter veil.NodeFilter) (*veil.NodesResponse, *http.Response, error)
+	IterFunc                func(ctx context.Context, filter veil.ListFilter, opts *veil.PageOptions) *veil.NodeIterator
+	ListAllFunc             func(ctx context.Context, filter veil.ListFilter) ([]veil.NodeObjectsList, error)
+	GetFunc                 func(Id string) (*veil.NodeObject, *http.Response, error)
+	GetContextFunc          func(ctx context.Context, Id string) (*veil.NodeObject, *http.Response, error)
+}
+
+var _ veil.NodeAPI = (*NodeAPI)(nil)
+
+func (m *NodeAPI) List() (*veil.NodesResponse, *http.Response, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc()
+	}
+	if m.ListContextFunc != nil {
+		return m.ListContextFunc(context.Background())
+	}
+	return nil, nil, notMocked("NodeAPI.List")
+}
+
+func (m *NodeAPI) ListContext(ctx context.Context) (*veil.NodesResponse, *http.Response, error) {
+	if m.ListContextFunc != nil {
+		return m.ListContextFunc(ctx)
+	}
+	return nil, nil, notMocked("NodeAPI.ListContext")
+}
+
+func (m *NodeAPI) ListParams(queryParams map[string]string) (*veil.NodesResponse, *http.Response, error) {
+	if m.ListParamsFunc != nil {
+		return m.ListParamsFunc(queryParams)
+	}
+	if m.ListParamsContextFunc != nil {
+		return m.ListParamsContextFunc(context.Background(), queryParams)
+	}
+	return nil, nil, notMocked("NodeAPI.ListParams")
+}
+
+func (m *NodeAPI) ListParamsContext(ctx context.Context, queryParams map[string]string) (*veil.NodesResponse, *http.Response, error) {
+	if m.ListParamsContextFunc != nil {
+		return m.ListParamsContextFunc(ctx, queryParams)
+	}
+	return nil, nil, notMocked("NodeAPI.ListParamsContext")
+}
+
+func (m *NodeAPI) ListFiltered(filter veil.NodeFilter) (*veil.NodesResponse, *http.Response, error) {
+	if m.ListFilteredFunc != nil {
+		return m.ListFilteredFunc(filter)
+	}
+	if m.ListFilteredContextFunc != nil {
+		return m.ListFilteredContextFunc(context.Background(), filter)
+	}
+	return nil, nil, notMocked("NodeAPI.ListFiltered")
+}
+
+func (m *NodeAPI) ListFilteredContext(ctx context.Context, filter veil.NodeFilter) (*veil.NodesResponse, *http.Response, error) {
+	if m.ListFilteredContextFunc != nil {
+		return m.ListFilteredContextFunc(ctx, filter)
+	}
+	return nil, nil, notMocked("NodeAPI.ListFilteredContext")
+}
+
+func (m *NodeAPI) Iter(ctx context.Context, filter veil.ListFilter, opts *veil.PageOptions) *veil.NodeIterator {
+	if m.IterFunc != nil {
+		return m.IterFunc(ctx, filter, opts)
+	}
+	return nil
+}
+
+func (m *NodeAPI) ListAll(ctx context.Context, filter veil.ListFilter) ([]veil.NodeObjectsList, error) {
+	if m.ListAllFunc != nil {
+		return m.ListAllFunc(ctx, filter)
+	}
+	return nil, notMocked("NodeAPI.ListAll")
+}
+
+func (m *NodeAPI) Get(Id string) (*veil.NodeObject, *http.Response, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(Id)
+	}
+	if m.GetContextFunc != nil {
+		return m.GetContextFunc(context.Background(), Id)
+	}
+	return nil, nil, notMocked("NodeAPI.Get")
+}
+
+func (m *NodeAPI) GetContext(ctx context.Context, Id string) (*veil.NodeObject, *http.Response, error) {
+	if m.GetContextFunc != nil {
+		return m.GetContextFunc(ctx, Id)
+	}
+	return nil, nil, notMocked("NodeAPI.GetContext")
+}
+
+// ClusterAPI mock of veil.ClusterAPI
+type ClusterAPI struct {
+	ListFunc                func() (*veil.ClustersResponse, *http.Response, error)
+	ListContextFunc         func(ctx context.Context) (*veil.ClustersResponse, *http.Response, error)
+	ListParamsFunc          func(queryParams map[string]string) (*veil.ClustersResponse, *http.Response, error)
+	ListParamsContextFunc   func(ctx context.Context, queryParams map[string]string) (*veil.ClustersResponse, *http.Response, error)
+	ListFilteredFunc        func(filter veil.ClusterFilter) (*veil.ClustersResponse, *http.Response, error)
+	ListFilteredContextFunc func(ctx context.Context, filter veil.ClusterFilter) (*veil.ClustersResponse, *http.Response, error)
+	IterFunc                func(ctx context.Context, filter veil.ListFilter, opts *veil.PageOptions) *veil.ClusterIterator
+	ListAllFunc             func(ctx context.Context, filter veil.ListFilter) ([]veil.ClusterObjectsList, error)
+	GetFunc                 func(Id string) (*veil.ClusterObject, *http.Response, error)
+	GetContextFunc          func(ctx context.Context, Id string) (*veil.ClusterObject, *http.Response, error)
+}
+
+var _ veil.ClusterAPI = (*ClusterAPI)(nil)
+
+func (m *ClusterAPI) List() (*veil.ClustersResponse, *http.Response, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc()
+	}
+	if m.ListContextFunc != nil {
+		return m.ListContextFunc(context.Background())
+	}
+	return nil, nil, notMocked("ClusterAPI.List")
+}
+
+func (m *ClusterAPI) ListContext(ctx context.Context) (*veil.ClustersResponse, *http.Response, error) {
+	if m.ListContextFunc != nil {
+		return m.ListContextFunc(ctx)
+	}
+	return nil, nil, notMocked("ClusterAPI.ListContext")
+}
+
+func (m *ClusterAPI) ListParams(queryParams map[string]string) (*veil.ClustersResponse, *http.Response, error) {
+	if m.ListParamsFunc != nil {
+		return m.ListParamsFunc(queryParams)
+	}
+	if m.ListParamsContextFunc != nil {
+		return m.ListParamsContextFunc(context.Background(), queryParams)
+	}
+	return nil, nil, notMocked("ClusterAPI.ListParams")
+}
+
+func (m *ClusterAPI) ListParamsContext(ctx context.Context, queryParams map[string]string) (*veil.ClustersResponse, *http.Response, error) {
+	if m.ListParamsContextFunc != nil {
+		return m.ListParamsContextFunc(ctx, queryParams)
+	}
+	return nil, nil, notMocked("ClusterAPI.ListParamsContext")
+}
+
+func (m *ClusterAPI) ListFiltered(filter veil.ClusterFilter) (*veil.ClustersResponse, *http.Response, error) {
+	if m.ListFilteredFunc != nil {
+		return m.ListFilteredFunc(filter)
+	}
+	if m.ListFilteredContextFunc != nil {
+		return m.ListFilteredContextFunc(context.Background(), filter)
+	}
+	return nil, nil, notMocked("ClusterAPI.ListFiltered")
+}
+
+func (m *ClusterAPI) ListFilteredContext(ctx context.Context, filter veil.ClusterFilter) (*veil.ClustersResponse, *http.Response, error) {
+	if m.ListFilteredContextFunc != nil {
+		return m.ListFilteredContextFunc(ctx, filter)
+	}
+	return nil, nil, notMocked("ClusterAPI.ListFilteredContext")
+}
+
+func (m *ClusterAPI) Iter(ctx context.Context, filter veil.ListFilter, opts *veil.PageOptions) *veil.ClusterIterator {
+	if m.IterFunc != nil {
+		return m.IterFunc(ctx, filter, opts)
+	}
+	return nil
+}
+
+func (m *ClusterAPI) ListAll(ctx context.Context, filter veil.ListFilter) ([]veil.ClusterObjectsList, error) {
+	if m.ListAllFunc != nil {
+		return m.ListAllFunc(ctx, filter)
+	}
+	return nil, notMocked("ClusterAPI.ListAll")
+}
+
+func (m *ClusterAPI) Get(Id string) (*veil.ClusterObject, *http.Response, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(Id)
+	}
+	if m.GetContextFunc != nil {
+		return m.GetContextFunc(context.Background(), Id)
+	}
+	return nil, nil, notMocked("ClusterAPI.Get")
+}
+
+func (m *ClusterAPI) GetContext(ctx context.Context, Id string) (*veil.ClusterObject, *http.Response, error) {
+	if m.GetContextFunc != nil {
+		return m.GetContextFunc(ctx, Id)
+	}
+	return nil, nil, notMocked("ClusterAPI.GetContext")
+}
+
+// DataCenterAPI mock of veil.DataCenterAPI
+type DataCenterAPI struct {
+	ListFunc                func() (*veil.DataCentersResponse, *http.Response, error)
+	ListContextFunc         func(ctx context.Context) (*veil.DataCentersResponse, *http.Response, error)
+	ListParamsFunc          func(queryParams map[string]string) (*veil.DataCentersResponse, *http.Response, error)
+	ListParamsContextFunc   func(ctx context.Context, queryParams map[string]string) (*veil.DataCentersResponse, *http.Response, error)
+	ListFilteredFunc        func(filter veil.DataCenterFilter) (*veil.DataCentersResponse, *http.Response, error)
+	ListFilteredContextFunc func(ctx context.Context, filter veil.DataCenterFilter) (*veil.DataCentersResponse, *http.Response, error)
+	IterFunc                func(ctx context.Context, filter veil.ListFilter, opts *veil.PageOptions) *veil.DataCenterIterator
+	ListAllFunc             func(ctx context.Context, filter veil.ListFilter) ([]veil.DataCenterObjectsList, error)
+	GetFunc                 func(Id string) (*veil.DataCenterObject, *http.Response, error)
+	GetContextFunc          func(ctx context.Context, Id string) (*veil.DataCenterObject, *http.Response, error)
+}
+
+var _ veil.DataCenterAPI = (*DataCenterAPI)(nil)
+
+func (m *DataCenterAPI) List() (*veil.DataCentersResponse, *http.Response, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc()
+	}
+	if m.ListContextFunc != nil {
+		return m.ListContextFunc(context.Background())
+	}
+	return nil, nil, notMocked("DataCenterAPI.List")
+}
+
+func (m *DataCenterAPI) ListContext(ctx context.Context) (*veil.DataCentersResponse, *http.Response, error) {
+	if m.ListContextFunc != nil {
+		return m.ListContextFunc(ctx)
+	}
+	return nil, nil, notMocked("DataCenterAPI.ListContext")
+}
+
+func (m *DataCenterAPI) ListParams(queryParams map[string]string) (*veil.DataCentersResponse, *http.Response, error) {
+	if m.ListParamsFunc != nil {
+		return m.ListParamsFunc(queryParams)
+	}
+	if m.ListParamsContextFunc != nil {
+		return m.ListParamsContextFunc(context.Background(), queryParams)
+	}
+	return nil, nil, notMocked("DataCenterAPI.ListParams")
+}
+
+func (m *DataCenterAPI) ListParamsContext(ctx context.Context, queryParams map[string]string) (*veil.DataCentersResponse, *http.Response, error) {
+	if m.ListParamsContextFunc != nil {
+		return m.ListParamsContextFunc(ctx, queryParams)
+	}
+	return nil, nil, notMocked("DataCenterAPI.ListParamsContext")
+}
+
+func (m *DataCenterAPI) ListFiltered(filter veil.DataCenterFilter) (*veil.DataCentersResponse, *http.Response, error) {
+	if m.ListFilteredFunc != nil {
+		return m.ListFilteredFunc(filter)
+	}
+	if m.ListFilteredContextFunc != nil {
+		return m.ListFilteredContextFunc(context.Background(), filter)
+	}
+	return nil, nil, notMocked("DataCenterAPI.ListFiltered")
+}
+
+func (m *DataCenterAPI) ListFilteredContext(ctx context.Context, filter veil.DataCenterFilter) (*veil.DataCentersResponse, *http.Response, error) {
+	if m.ListFilteredContextFunc != nil {
+		return m.ListFilteredContextFunc(ctx, filter)
+	}
+	return nil, nil, notMocked("DataCenterAPI.ListFilteredContext")
+}
+
+func (m *DataCenterAPI) Iter(ctx context.Context, filter veil.ListFilter, opts *veil.PageOptions) *veil.DataCenterIterator {
+	if m.IterFunc != nil {
+		return m.IterFunc(ctx, filter, opts)
+	}
+	return nil
+}
+
+func (m *DataCenterAPI) ListAll(ctx context.Context, filter veil.ListFilter) ([]veil.DataCenterObjectsList, error) {
+	if m.ListAllFunc != nil {
+		return m.ListAllFunc(ctx, filter)
+	}
+	return nil, notMocked("DataCenterAPI.ListAll")
+}
+
+func (m *DataCenterAPI) Get(Id string) (*veil.DataCenterObject, *http.Response, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(Id)
+	}
+	if m.GetContextFunc != nil {
+		return m.GetContextFunc(context.Background(), Id)
+	}
+	return nil, nil, notMocked("DataCenterAPI.Get")
+}
+
+func (m *DataCenterAPI) GetContext(ctx context.Context, Id string) (*veil.DataCenterObject, *http.Response, error) {
+	if m.GetContextFunc != nil {
+		return m.GetContextFunc(ctx, Id)
+	}
+	return nil, nil, notMocked("DataCenterAPI.GetContext")
+}
+
+// DataPoolAPI mock of veil.DataPoolAPI
+type DataPoolAPI struct {
+	ListFunc                func() (*veil.DataPoolsResponse, *http.Response, error)
+	ListContextFunc         func(ctx context.Context) (*veil.DataPoolsResponse, *http.Response, error)
+	ListParamsFunc          func(queryParams map[string]string) (*veil.DataPoolsResponse, *http.Response, error)
+	ListParamsContextFunc   func(ctx context.Context, queryParams map[string]string) (*veil.DataPoolsResponse, *http.Response, error)
+	ListFilteredFunc        func(filter veil.DataPoolFilter) (*veil.DataPoolsResponse, *http.Response, error)
+	ListFilteredContextFunc func(ctx context.Context, filter veil.DataPoolFilter) (*veil.DataPoolsResponse, *http.Response, error)
+	IterFunc                func(ctx context.Context, filter veil.ListFilter, opts *veil.PageOptions) *veil.DataPoolIterator
+	ListAllFunc             func(ctx context.Context, filter veil.ListFilter) ([]veil.DataPoolObjectsList, error)
+	GetFunc                 func(Id string) (*veil.DataPoolObject, *http.Response, error)
+	GetContextFunc          func(ctx context.Context, Id string) (*veil.DataPoolObject, *http.Response, error)
+}
+
+var _ veil.DataPoolAPI = (*DataPoolAPI)(nil)
+
+func (m *DataPoolAPI) List() (*veil.DataPoolsResponse, *http.Response, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc()
+	}
+	if m.ListContextFunc != nil {
+		return m.ListContextFunc(context.Background())
+	}
+	return nil, nil, notMocked("DataPoolAPI.List")
+}
+
+func (m *DataPoolAPI) ListContext(ctx context.Context) (*veil.DataPoolsResponse, *http.Response, error) {
+	if m.ListContextFunc != nil {
+		return m.ListContextFunc(ctx)
+	}
+	return nil, nil, notMocked("DataPoolAPI.ListContext")
+}
+
+func (m *DataPoolAPI) ListParams(queryParams map[string]string) (*veil.DataPoolsResponse, *http.Response, error) {
+	if m.ListParamsFunc != nil {
+		return m.ListParamsFunc(queryParams)
+	}
+	if m.ListParamsContextFunc != nil {
+		return m.ListParamsContextFunc(context.Background(), queryParams)
+	}
+	return nil, nil, notMocked("DataPoolAPI.ListParams")
+}
+
+func (m *DataPoolAPI) ListParamsContext(ctx context.Context, queryParams map[string]string) (*veil.DataPoolsResponse, *http.Response, error) {
+	if m.ListParamsContextFunc != nil {
+		return m.ListParamsContextFunc(ctx, queryParams)
+	}
+	return nil, nil, notMocked("DataPoolAPI.ListParamsContext")
+}
+
+func (m *DataPoolAPI) ListFiltered(filter veil.DataPoolFilter) (*veil.DataPoolsResponse, *http.Response, error) {
+	if m.ListFilteredFunc != nil {
+		return m.ListFilteredFunc(filter)
+	}
+	if m.ListFilteredContextFunc != nil {
+		return m.ListFilteredContextFunc(context.Background(), filter)
+	}
+	return nil, nil, notMocked("DataPoolAPI.ListFiltered")
+}
+
+func (m *DataPoolAPI) ListFilteredContext(ctx context.Context, filter veil.DataPoolFilter) (*veil.DataPoolsResponse, *http.Response, error) {
+	if m.ListFilteredContextFunc != nil {
+		return m.ListFilteredContextFunc(ctx, filter)
+	}
+	return nil, nil, notMocked("DataPoolAPI.ListFilteredContext")
+}
+
+func (m *DataPoolAPI) Iter(ctx context.Context, filter veil.ListFilter, opts *veil.PageOptions) *veil.DataPoolIterator {
+	if m.IterFunc != nil {
+		return m.IterFunc(ctx, filter, opts)
+	}
+	return nil
+}
+
+func (m *DataPoolAPI) ListAll(ctx context.Context, filter veil.ListFilter) ([]veil.DataPoolObjectsList, error) {
+	if m.ListAllFunc != nil {
+		return m.ListAllFunc(ctx, filter)
+	}
+	return nil, notMocked("DataPoolAPI.ListAll")
+}
+
+func (m *DataPoolAPI) Get(Id string) (*veil.DataPoolObject, *http.Response, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(Id)
+	}
+	if m.GetContextFunc != nil {
+		return m.GetContextFunc(context.Background(), Id)
+	}
+	return nil, nil, notMocked("DataPoolAPI.Get")
+}
+
+func (m *DataPoolAPI) GetContext(ctx context.Context, Id string) (*veil.DataPoolObject, *http.Response, error) {
+	if m.GetContextFunc != nil {
+		return m.GetContextFunc(ctx, Id)
+	}
+	return nil, nil, notMocked("DataPoolAPI.GetContext")
+}
+
+// VdiskAPI mock of veil.VdiskAPI
+type VdiskAPI struct {
+	ListFunc                func() (*veil.VdisksResponse, *http.Response, error)
+	ListContextFunc         func(ctx context.Context) (*veil.VdisksResponse, *http.Response, error)
+	ListParamsFunc          func(queryParams map[string]string) (*veil.VdisksResponse, *http.Response, error)
+	ListParamsContextFunc   func(ctx context.Context, queryParams map[string]string) (*veil.VdisksResponse, *http.Response, error)
+	ListFilteredFunc        func(filter veil.VdiskFilter) (*veil.VdisksResponse, *http.Response, error)
+	ListFilteredContextFunc func(ctx context.Context, filter veil.VdiskFilter) (*veil.VdisksResponse, *http.Response, error)
+	IterFunc                func(ctx context.Context, filter veil.ListFilter, opts *veil.PageOptions) *veil.VdiskIterator
+	ListAllFunc             func(ctx context.Context, filter veil.ListFilter) ([]veil.VdiskObjectsList, error)
+	GetFunc                 func(Id string) (*veil.VdiskObject, *http.Response, error)
+	GetContextFunc          func(ctx context.Context, Id string) (*veil.VdiskObject, *http.Response, error)
+	CreateFunc              func(config *veil.VdiskCreate, asynced bool) (*veil.VdiskObject, *http.Response, error)
+	CreateContextFunc       func(ctx context.Context, config *veil.VdiskCreate, asynced bool) (*veil.VdiskObject, *http.Response, error)
+	StartCreateFunc         func(ctx context.Context, config *veil.VdiskCreate) (*veil.VdiskOperation, *http.Response, error)
+	ResumeOperationFunc     func(state veil.OperationState) (*veil.VdiskOperation, error)
+	UpdateFunc              func(Id string, description string) (*veil.VdiskObject, *http.Response, error)
+	UpdateContextFunc       func(ctx context.Context, Id string, description string) (*veil.VdiskObject, *http.Response, error)
+	ExtendFunc              func(Id string, size float64) (*veil.VdiskObject, *http.Response, error)
+	ExtendContextFunc       func(ctx context.Context, Id string, size float64) (*veil.VdiskObject, *http.Response, error)
+	ConsolidateFunc         func(Id string) (*veil.VdiskObject, *http.Response, error)
+	ConsolidateContextFunc  func(ctx context.Context, Id string) (*veil.VdiskObject, *http.Response, error)
+	StartConsolidateFunc    func(ctx context.Context, Id string) (*veil.VdiskOperation, *http.Response, error)
+	RemoveFunc              func(Id string) (bool, *http.Response, error)
+	RemoveContextFunc       func(ctx context.Context, Id string) (bool, *http.Response, error)
+}
+
+var _ veil.VdiskAPI = (*VdiskAPI)(nil)
+
+func (m *VdiskAPI) List() (*veil.VdisksResponse, *http.Response, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc()
+	}
+	if m.ListContextFunc != nil {
+		return m.ListContextFunc(context.Background())
+	}
+	return nil, nil, notMocked("VdiskAPI.List")
+}
+
+func (m *VdiskAPI) ListContext(ctx context.Context) (*veil.VdisksResponse, *http.Response, error) {
+	if m.ListContextFunc != nil {
+		return m.ListContextFunc(ctx)
+	}
+	return nil, nil, notMocked("VdiskAPI.ListContext")
+}
+
+func (m *VdiskAPI) ListParams(queryParams map[string]string) (*veil.VdisksResponse, *http.Response, error) {
+	if m.ListParamsFunc != nil {
+		return m.ListParamsFunc(queryParams)
+	}
+	if m.ListParamsContextFunc != nil {
+		return m.ListParamsContextFunc(context.Background(), queryParams)
+	}
+	return nil, nil, notMocked("VdiskAPI.ListParams")
+}
+
+func (m *VdiskAPI) ListParamsContext(ctx context.Context, queryParams map[string]string) (*veil.VdisksResponse, *http.Response, error) {
+	if m.ListParamsContextFunc != nil {
+		return m.ListParamsContextFunc(ctx, queryParams)
+	}
+	return nil, nil, notMocked("VdiskAPI.ListParamsContext")
+}
+
+func (m *VdiskAPI) ListFiltered(filter veil.VdiskFilter) (*veil.VdisksResponse, *http.Response, error) {
+	if m.ListFilteredFunc != nil {
+		return m.ListFilteredFunc(filter)
+	}
+	if m.ListFilteredContextFunc != nil {
+		return m.ListFilteredContextFunc(context.Background(), filter)
+	}
+	return nil, nil, notMocked("VdiskAPI.ListFiltered")
+}
+
+func (m *VdiskAPI) ListFilteredContext(ctx context.Context, filter veil.VdiskFilter) (*veil.VdisksResponse, *http.Response, error) {
+	if m.ListFilteredContextFunc != nil {
+		return m.ListFilteredContextFunc(ctx, filter)
+	}
+	return nil, nil, notMocked("VdiskAPI.ListFilteredContext")
+}
+
+func (m *VdiskAPI) Iter(ctx context.Context, filter veil.ListFilter, opts *veil.PageOptions) *veil.VdiskIterator {
+	if m.IterFunc != nil {
+		return m.IterFunc(ctx, filter, opts)
+	}
+	return nil
+}
+
+func (m *VdiskAPI) ListAll(ctx context.Context, filter veil.ListFilter) ([]veil.VdiskObjectsList, error) {
+	if m.ListAllFunc != nil {
+		return m.ListAllFunc(ctx, filter)
+	}
+	return nil, notMocked("VdiskAPI.ListAll")
+}
+
+func (m *VdiskAPI) Get(Id string) (*veil.VdiskObject, *http.Response, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(Id)
+	}
+	if m.GetContextFunc != nil {
+		return m.GetContextFunc(context.Background(), Id)
+	}
+	return nil, nil, notMocked("VdiskAPI.Get")
+}
+
+func (m *VdiskAPI) GetContext(ctx context.Context, Id string) (*veil.VdiskObject, *http.Response, error) {
+	if m.GetContextFunc != nil {
+		return m.GetContextFunc(ctx, Id)
+	}
+	return nil, nil, notMocked("VdiskAPI.GetContext")
+}
+
+func (m *VdiskAPI) Create(config *veil.VdiskCreate, asynced bool) (*veil.VdiskObject, *http.Response, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(config, asynced)
+	}
+	if m.CreateContextFunc != nil {
+		return m.CreateContextFunc(context.Background(), config, asynced)
+	}
+	return nil, nil, notMocked("VdiskAPI.Create")
+}
+
+func (m *VdiskAPI) CreateContext(ctx context.Context, config *veil.VdiskCreate, asynced bool) (*veil.VdiskObject, *http.Response, error) {
+	if m.CreateContextFunc != nil {
+		return m.CreateContextFunc(ctx, config, asynced)
+	}
+	return nil, nil, notMocked("VdiskAPI.CreateContext")
+}
+
+func (m *VdiskAPI) StartCreate(ctx context.Context, config *veil.VdiskCreate) (*veil.VdiskOperation, *http.Response, error) {
+	if m.StartCreateFunc != nil {
+		return m.StartCreateFunc(ctx, config)
+	}
+	return nil, nil, notMocked("VdiskAPI.StartCreate")
+}
+
+func (m *VdiskAPI) ResumeOperation(state veil.OperationState) (*veil.VdiskOperation, error) {
+	if m.ResumeOperationFunc != nil {
+		return m.ResumeOperationFunc(state)
+	}
+	return nil, notMocked("VdiskAPI.ResumeOperation")
+}
+
+func (m *VdiskAPI) Update(Id string, description string) (*veil.VdiskObject, *http.Response, error) {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(Id, description)
+	}
+	if m.UpdateContextFunc != nil {
+		return m.UpdateContextFunc(context.Background(), Id, description)
+	}
+	return nil, nil, notMocked("VdiskAPI.Update")
+}
+
+func (m *VdiskAPI) UpdateContext(ctx context.Context, Id string, description string) (*veil.VdiskObject, *http.Response, error) {
+	if m.UpdateContextFunc != nil {
+		return m.UpdateContextFunc(ctx, Id, description)
+	}
+	return nil, nil, notMocked("VdiskAPI.UpdateContext")
+}
+
+func (m *VdiskAPI) Extend(Id string, size float64) (*veil.VdiskObject, *http.Response, error) {
+	if m.ExtendFunc != nil {
+		return m.ExtendFunc(Id, size)
+	}
+	if m.ExtendContextFunc != nil {
+		return m.ExtendContextFunc(context.Background(), Id, size)
+	}
+	return nil, nil, notMocked("VdiskAPI.Extend")
+}
+
+func (m *VdiskAPI) ExtendContext(ctx context.Context, Id string, size float64) (*veil.VdiskObject, *http.Response, error) {
+	if m.ExtendContextFunc != nil {
+		return m.ExtendContextFunc(ctx, Id, size)
+	}
+	return nil, nil, notMocked("VdiskAPI.ExtendContext")
+}
+
+func (m *VdiskAPI) Consolidate(Id string) (*veil.VdiskObject, *http.Response, error) {
+	if m.ConsolidateFunc != nil {
+		return m.ConsolidateFunc(Id)
+	}
+	if m.ConsolidateContextFunc != nil {
+		return m.ConsolidateContextFunc(context.Background(), Id)
+	}
+	return nil, nil, notMocked("VdiskAPI.Consolidate")
+}
+
+func (m *VdiskAPI) ConsolidateContext(ctx context.Context, Id string) (*veil.VdiskObject, *http.Response, error) {
+	if m.ConsolidateContextFunc != nil {
+		return m.ConsolidateContextFunc(ctx, Id)
+	}
+	return nil, nil, notMocked("VdiskAPI.ConsolidateContext")
+}
+
+func (m *VdiskAPI) StartConsolidate(ctx context.Context, Id string) (*veil.VdiskOperation, *http.Response, error) {
+	if m.StartConsolidateFunc != nil {
+		return m.StartConsolidateFunc(ctx, Id)
+	}
+	return nil, nil, notMocked("VdiskAPI.StartConsolidate")
+}
+
+func (m *VdiskAPI) Remove(Id string) (bool, *http.Response, error) {
+	if m.RemoveFunc != nil {
+		return m.RemoveFunc(Id)
+	}
+	if m.RemoveContextFunc != nil {
+		return m.RemoveContextFunc(context.Background(), Id)
+	}
+	return false, nil, notMocked("VdiskAPI.Remove")
+}
+
+func (m *VdiskAPI) RemoveContext(ctx context.Context, Id string) (bool, *http.Response, error) {
+	if m.RemoveContextFunc != nil {
+		return m.RemoveContextFunc(ctx, Id)
+	}
+	return false, nil, notMocked("VdiskAPI.RemoveContext")
+}
+
+// IsoAPI mock of veil.IsoAPI
+type IsoAPI struct {
+	ListFunc                func() (*veil.IsosResponse, *http.Response, error)
+	ListContextFunc         func(ctx context.Context) (*veil.IsosResponse, *http.Response, error)
+	ListParamsFunc          func(queryParams map[string]string) (*veil.IsosResponse, *http.Response, error)
+	ListParamsContextFunc   func(ctx context.Context, queryParams map[string]string) (*veil.IsosResponse, *http.Response, error)
+	ListFilteredFunc        func(filter veil.IsoFilter) (*veil.IsosResponse, *http.Response, error)
+	ListFilteredContextFunc func(ctx context.Context, filter veil.IsoFilter) (*veil.IsosResponse, *http.Response, error)
+	IterFunc                func(ctx context.Context, filter veil.ListFilter, opts *veil.PageOptions) *veil.IsoIterator
+	ListAllFunc             func(ctx context.Context, filter veil.ListFilter) ([]veil.IsoObjectsList, error)
+	GetFunc                 func(Id string) (*veil.IsoObject, *http.Response, error)
+	GetContextFunc          func(ctx context.Context, Id string) (*veil.IsoObject, *http.Response, error)
+	CreateFunc              func(DataPoolId string, FilenameUrl string, timeout int64) (*veil.IsoObject, error)
+	CreateContextFunc       func(ctx context.Context, DataPoolId string, FilenameUrl string, timeout int64) (*veil.IsoObject, error)
+	DownloadFunc            func(entity *veil.IsoObject) (*veil.IsoObject, *http.Response, error)
+	DownloadContextFunc     func(ctx context.Context, entity *veil.IsoObject) (*veil.IsoObject, *http.Response, error)
+	RemoveFunc              func(Id string) (bool, *http.Response, error)
+	RemoveContextFunc       func(ctx context.Context, Id string) (bool, *http.Response, error)
+}
+
+var _ veil.IsoAPI = (*IsoAPI)(nil)
+
+func (m *IsoAPI) List() (*veil.IsosResponse, *http.Response, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc()
+	}
+	if m.ListContextFunc != nil {
+		return m.ListContextFunc(context.Background())
+	}
+	return nil, nil, notMocked("IsoAPI.List")
+}
+
+func (m *IsoAPI) ListContext(ctx context.Context) (*veil.IsosResponse, *http.Response, error) {
+	if m.ListContextFunc != nil {
+		return m.ListContextFunc(ctx)
+	}
+	return nil, nil, notMocked("IsoAPI.ListContext")
+}
+
+func (m *IsoAPI) ListParams(queryParams map[string]string) (*veil.IsosResponse, *http.Response, error) {
+	if m.ListParamsFunc != nil {
+		return m.ListParamsFunc(queryParams)
+	}
+	if m.ListParamsContextFunc != nil {
+		return m.ListParamsContextFunc(context.Background(), queryParams)
+	}
+	return nil, nil, notMocked("IsoAPI.ListParams")
+}
+
+func (m *IsoAPI) ListParamsContext(ctx context.Context, queryParams map[string]string) (*veil.IsosResponse, *http.Response, error) {
+	if m.ListParamsContextFunc != nil {
+		return m.ListParamsContextFunc(ctx, queryParams)
+	}
+	return nil, nil, notMocked("IsoAPI.ListParamsContext")
+}
+
+func (m *IsoAPI) ListFiltered(filter veil.IsoFilter) (*veil.IsosResponse, *http.Response, error) {
+	if m.ListFilteredFunc != nil {
+		return m.ListFilteredFunc(filter)
+	}
+	if m.ListFilteredContextFunc != nil {
+		return m.ListFilteredContextFunc(context.Background(), filter)
+	}
+	return nil, nil, notMocked("IsoAPI.ListFiltered")
+}
+
+func (m *IsoAPI) ListFilteredContext(ctx context.Context, filter veil.IsoFilter) (*veil.IsosResponse, *http.Response, error) {
+	if m.ListFilteredContextFunc != nil {
+		return m.ListFilteredContextFunc(ctx, filter)
+	}
+	return nil, nil, notMocked("IsoAPI.ListFilteredContext")
+}
+
+func (m *IsoAPI) Iter(ctx context.Context, filter veil.ListFilter, opts *veil.PageOptions) *veil.IsoIterator {
+	if m.IterFunc != nil {
+		return m.IterFunc(ctx, filter, opts)
+	}
+	return nil
+}
+
+func (m *IsoAPI) ListAll(ctx context.Context, filter veil.ListFilter) ([]veil.IsoObjectsList, error) {
+	if m.ListAllFunc != nil {
+		return m.ListAllFunc(ctx, filter)
+	}
+	return nil, notMocked("IsoAPI.ListAll")
+}
+
+func (m *IsoAPI) Get(Id string) (*veil.IsoObject, *http.Response, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(Id)
+	}
+	if m.GetContextFunc != nil {
+		return m.GetContextFunc(context.Background(), Id)
+	}
+	return nil, nil, notMocked("IsoAPI.Get")
+}
+
+func (m *IsoAPI) GetContext(ctx context.Context, Id string) (*veil.IsoObject, *http.Response, error) {
+	if m.GetContextFunc != nil {
+		return m.GetContextFunc(ctx, Id)
+	}
+	return nil, nil, notMocked("IsoAPI.GetContext")
+}
+
+func (m *IsoAPI) Create(DataPoolId string, FilenameUrl string, timeout int64) (*veil.IsoObject, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(DataPoolId, FilenameUrl, timeout)
+	}
+	if m.CreateContextFunc != nil {
+		return m.CreateContextFunc(context.Background(), DataPoolId, FilenameUrl, timeout)
+	}
+	return nil, notMocked("IsoAPI.Create")
+}
+
+func (m *IsoAPI) CreateContext(ctx context.Context, DataPoolId string, FilenameUrl string, timeout int64) (*veil.IsoObject, error) {
+	if m.CreateContextFunc != nil {
+		return m.CreateContextFunc(ctx, DataPoolId, FilenameUrl, timeout)
+	}
+	return nil, notMocked("IsoAPI.CreateContext")
+}
+
+func (m *IsoAPI) Download(entity *veil.IsoObject) (*veil.IsoObject, *http.Response, error) {
+	if m.DownloadFunc != nil {
+		return m.DownloadFunc(entity)
+	}
+	if m.DownloadContextFunc != nil {
+		return m.DownloadContextFunc(context.Background(), entity)
+	}
+	return nil, nil, notMocked("IsoAPI.Download")
+}
+
+func (m *IsoAPI) DownloadContext(ctx context.Context, entity *veil.IsoObject) (*veil.IsoObject, *http.Response, error) {
+	if m.DownloadContextFunc != nil {
+		return m.DownloadContextFunc(ctx, entity)
+	}
+	return nil, nil, notMocked("IsoAPI.DownloadContext")
+}
+
+func (m *IsoAPI) Remove(Id string) (bool, *http.Response, error) {
+	if m.RemoveFunc != nil {
+		return m.RemoveFunc(Id)
+	}
+	if m.RemoveContextFunc != nil {
+		return m.RemoveContextFunc(context.Background(), Id)
+	}
+	return false, nil, notMocked("IsoAPI.Remove")
+}
+
+func (m *IsoAPI) RemoveContext(ctx context.Context, Id string) (bool, *http.Response, error) {
+	if m.RemoveContextFunc != nil {
+		return m.RemoveContextFunc(ctx, Id)
+	}
+	return false, nil, notMocked("IsoAPI.RemoveContext")
+}
+
+// LibraryAPI mock of veil.LibraryAPI
+type LibraryAPI struct {
+	ListFunc                func() (*veil.LibraryResponse, *http.Response, error)
+	ListContextFunc         func(ctx context.Context) (*veil.LibraryResponse, *http.Response, error)
+	ListParamsFunc          func(queryParams map[string]string) (*veil.LibraryResponse, *http.Response, error)
+	ListParamsContextFunc   func(ctx context.Context, queryParams map[string]string) (*veil.LibraryResponse, *http.Response, error)
+	ListFilteredFunc        func(filter veil.LibraryFilter) (*veil.LibraryResponse, *http.Response, error)
+	ListFilteredContextFunc func(ctx context.Context, filter veil.LibraryFilter) (*veil.LibraryResponse, *http.Response, error)
+	IterFunc                func(ctx context.Context, filter veil.ListFilter, opts *veil.PageOptions) *veil.LibraryIterator
+	ListAllFunc             func(ctx context.Context, filter veil.ListFilter) ([]veil.LibraryObjectsList, error)
+	GetFunc                 func(Id string) (*veil.LibraryObject, *http.Response, error)
+	GetContextFunc          func(ctx context.Context, Id string) (*veil.LibraryObject, *http.Response, error)
+	ImportFunc              func(Id string, config veil.FileImportConfig) (*veil.VdiskObject, *http.Response, error)
+	ImportContextFunc       func(ctx context.Context, Id string, config veil.FileImportConfig) (*veil.VdiskObject, *http.Response, error)
+	StartImportFunc         func(ctx context.Context, Id string, config veil.FileImportConfig) (*veil.VdiskOperation, *http.Response, error)
+	ResumeOperationFunc     func(state veil.OperationState) (*veil.VdiskOperation, error)
+	CreateFunc              func(DataPoolId string, FilenameUrl string, timeout int64) (*veil.LibraryObject, error)
+	CreateContextFunc       func(ctx context.Context, DataPoolId string, FilenameUrl string, timeout int64) (*veil.LibraryObject, error)
+	DownloadFunc            func(entity *veil.LibraryObject) (*veil.LibraryObject, *http.Response, error)
+	DownloadContextFunc     func(ctx context.Context, entity *veil.LibraryObject) (*veil.LibraryObject, *http.Response, error)
+	RemoveFunc              func(Id string) (bool, *http.Response, error)
+	RemoveContextFunc       func(ctx context.Context, Id string) (bool, *http.Response, error)
+}
+
+var _ veil.LibraryAPI = (*LibraryAPI)(nil)
+
+func (m *LibraryAPI) List() (*veil.LibraryResponse, *http.Response, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc()
+	}
+	if m.ListContextFunc != nil {
+		return m.ListContextFunc(context.Background())
+	}
+	return nil, nil, notMocked("LibraryAPI.List")
+}
+
+func (m *LibraryAPI) ListContext(ctx context.Context) (*veil.LibraryResponse, *http.Response, error) {
+	if m.ListContextFunc != nil {
+		return m.ListContextFunc(ctx)
+	}
+	return nil, nil, notMocked("LibraryAPI.ListContext")
+}
+
+func (m *LibraryAPI) ListParams(queryParams map[string]string) (*veil.LibraryResponse, *http.Response, error) {
+	if m.ListParamsFunc != nil {
+		return m.ListParamsFunc(queryParams)
+	}
+	if m.ListParamsContextFunc != nil {
+		return m.ListParamsContextFunc(context.Background(), queryParams)
+	}
+	return nil, nil, notMocked("LibraryAPI.ListParams")
+}
+
+func (m *LibraryAPI) ListParamsContext(ctx context.Context, queryParams map[string]string) (*veil.LibraryResponse, *http.Response, error) {
+	if m.ListParamsContextFunc != nil {
+		return m.ListParamsContextFunc(ctx, queryParams)
+	}
+	return nil, nil, notMocked("LibraryAPI.ListParamsContext")
+}
+
+func (m *LibraryAPI) ListFiltered(filter veil.LibraryFilter) (*veil.LibraryResponse, *http.Response, error) {
+	if m.ListFilteredFunc != nil {
+		return m.ListFilteredFunc(filter)
+	}
+	if m.ListFilteredContextFunc != nil {
+		return m.ListFilteredContextFunc(context.Background(), filter)
+	}
+	return nil, nil, notMocked("LibraryAPI.ListFiltered")
+}
+
+func (m *LibraryAPI) ListFilteredContext(ctx context.Context, filter veil.LibraryFilter) (*veil.LibraryResponse, *http.Response, error) {
+	if m.ListFilteredContextFunc != nil {
+		return m.ListFilteredContextFunc(ctx, filter)
+	}
+	return nil, nil, notMocked("LibraryAPI.ListFilteredContext")
+}
+
+func (m *LibraryAPI) Iter(ctx context.Context, filter veil.ListFilter, opts *veil.PageOptions) *veil.LibraryIterator {
+	if m.IterFunc != nil {
+		return m.IterFunc(ctx, filter, opts)
+	}
+	return nil
+}
+
+func (m *LibraryAPI) ListAll(ctx context.Context, filter veil.ListFilter) ([]veil.LibraryObjectsList, error) {
+	if m.ListAllFunc != nil {
+		return m.ListAllFunc(ctx, filter)
+	}
+	return nil, notMocked("LibraryAPI.ListAll")
+}
+
+func (m *LibraryAPI) Get(Id string) (*veil.LibraryObject, *http.Response, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(Id)
+	}
+	if m.GetContextFunc != nil {
+		return m.GetContextFunc(context.Background(), Id)
+	}
+	return nil, nil, notMocked("LibraryAPI.Get")
+}
+
+func (m *LibraryAPI) GetContext(ctx context.Context, Id string) (*veil.LibraryObject, *http.Response, error) {
+	if m.GetContextFunc != nil {
+		return m.GetContextFunc(ctx, Id)
+	}
+	return nil, nil, notMocked("LibraryAPI.GetContext")
+}
+
+func (m *LibraryAPI) Import(Id string, config veil.FileImportConfig) (*veil.VdiskObject, *http.Response, error) {
+	if m.ImportFunc != nil {
+		return m.ImportFunc(Id, config)
+	}
+	if m.ImportContextFunc != nil {
+		return m.ImportContextFunc(context.Background(), Id, config)
+	}
+	return nil, nil, notMocked("LibraryAPI.Import")
+}
+
+func (m *LibraryAPI) ImportContext(ctx context.Context, Id string, config veil.FileImportConfig) (*veil.VdiskObject, *http.Response, error) {
+	if m.ImportContextFunc != nil {
+		return m.ImportContextFunc(ctx, Id, config)
+	}
+	return nil, nil, notMocked("LibraryAPI.ImportContext")
+}
+
+func (m *LibraryAPI) StartImport(ctx context.Context, Id string, config veil.FileImportConfig) (*veil.VdiskOperation, *http.Response, error) {
+	if m.StartImportFunc != nil {
+		return m.StartImportFunc(ctx, Id, config)
+	}
+	return nil, nil, notMocked("LibraryAPI.StartImport")
+}
+
+func (m *LibraryAPI) ResumeOperation(state veil.OperationState) (*veil.VdiskOperation, error) {
+	if m.ResumeOperationFunc != nil {
+		return m.ResumeOperationFunc(state)
+	}
+	return nil, notMocked("LibraryAPI.ResumeOperation")
+}
+
+func (m *LibraryAPI) Create(DataPoolId string, FilenameUrl string, timeout int64) (*veil.LibraryObject, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(DataPoolId, FilenameUrl, timeout)
+	}
+	if m.CreateContextFunc != nil {
+		return m.CreateContextFunc(context.Background(), DataPoolId, FilenameUrl, timeout)
+	}
+	return nil, notMocked("LibraryAPI.Create")
+}
+
+func (m *LibraryAPI) CreateContext(ctx context.Context, DataPoolId string, FilenameUrl string, timeout int64) (*veil.LibraryObject, error) {
+	if m.CreateContextFunc != nil {
+		return m.CreateContextFunc(ctx, DataPoolId, FilenameUrl, timeout)
+	}
+	return nil, notMocked("LibraryAPI.CreateContext")
+}
+
+func (m *LibraryAPI) Download(entity *veil.LibraryObject) (*veil.LibraryObject, *http.Response, error) {
+	if m.DownloadFunc != nil {
+		return m.DownloadFunc(entity)
+	}
+	if m.DownloadContextFunc != nil {
+		return m.DownloadContextFunc(context.Background(), entity)
+	}
+	return nil, nil, notMocked("LibraryAPI.Download")
+}
+
+func (m *LibraryAPI) DownloadContext(ctx context.Context, entity *veil.LibraryObject) (*veil.LibraryObject, *http.Response, error) {
+	if m.DownloadContextFunc != nil {
+		return m.DownloadContextFunc(ctx, entity)
+	}
+	return nil, nil, notMocked("LibraryAPI.DownloadContext")
+}
+
+func (m *LibraryAPI) Remove(Id string) (bool, *http.Response, error) {
+	if m.RemoveFunc != nil {
+		return m.RemoveFunc(Id)
+	}
+	if m.RemoveContextFunc != nil {
+		return m.RemoveContextFunc(context.Background(), Id)
+	}
+	return false, nil, notMocked("LibraryAPI.Remove")
+}
+
+func (m *LibraryAPI) RemoveContext(ctx context.Context, Id string) (bool, *http.Response, error) {
+	if m.RemoveContextFunc != nil {
+		return m.RemoveContextFunc(ctx, Id)
+	}
+	return false, nil, notMocked("LibraryAPI.RemoveContext")
+}
+
+// TaskAPI mock of veil.TaskAPI
+type TaskAPI struct {
+	ListFunc                func() (*veil.TasksResponse, *http.Response, error)
+	ListContextFunc         func(ctx context.Context) (*veil.TasksResponse, *http.Response, error)
+	ListParamsFunc          func(queryParams map[string]string) (*veil.TasksResponse, *http.Response, error)
+	ListParamsContextFunc   func(ctx context.Context, queryParams map[string]string) (*veil.TasksResponse, *http.Response, error)
+	ListFilteredFunc        func(filter veil.TaskFilter) (*veil.TasksResponse, *http.Response, error)
+	ListFilteredContextFunc func(ctx context.Context, filter veil.TaskFilter) (*veil.TasksResponse, *http.Response, error)
+	SubtasksFunc            func(Id string) (*veil.TasksResponse, *http.Response, error)
+	SubtasksContextFunc     func(ctx context.Context, Id string) (*veil.TasksResponse, *http.Response, error)
+	CancelFunc              func(Id string) (bool, *http.Response, error)
+	CancelContextFunc       func(ctx context.Context, Id string) (bool, *http.Response, error)
+	SummaryFunc             func(Id string) (*veil.TaskSummary, *http.Response, error)
+	SummaryContextFunc      func(ctx context.Context, Id string) (*veil.TaskSummary, *http.Response, error)
+	IterFunc                func(ctx context.Context, filter veil.ListFilter, opts *veil.PageOptions) *veil.TaskIterator
+	ListAllFunc             func(ctx context.Context, filter veil.ListFilter) ([]veil.TaskObjectsList, error)
+	GetFunc                 func(Id string) (*veil.TaskObject, *http.Response, error)
+	GetContextFunc          func(ctx context.Context, Id string) (*veil.TaskObject, *http.Response, error)
+	ResponseFunc            func(Id string, object interface{}) (*http.Response, error)
+	ResponseContextFunc     func(ctx context.Context, Id string, object interface{}) (*http.Response, error)
+	WaitFunc                func(ctx context.Context, Id string, opts *veil.TaskWaitOptions) (*veil.TaskObject, error)
+}
+
+var _ veil.TaskAPI = (*TaskAPI)(nil)
+
+func (m *TaskAPI) List() (*veil.TasksResponse, *http.Response, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc()
+	}
+	if m.ListContextFunc != nil {
+		return m.ListContextFunc(context.Background())
+	}
+	return nil, nil, notMocked("TaskAPI.List")
+}
+
+func (m *TaskAPI) ListContext(ctx context.Context) (*veil.TasksResponse, *http.Response, error) {
+	if m.ListContextFunc != nil {
+		return m.ListContextFunc(ctx)
+	}
+	return nil, nil, notMocked("TaskAPI.ListContext")
+}
+
+func (m *TaskAPI) ListParams(queryParams map[string]string) (*veil.TasksResponse, *http.Response, error) {
+	if m.ListParamsFunc != nil {
+		return m.ListParamsFunc(queryParams)
+	}
+	if m.ListParamsContextFunc != nil {
+		return m.ListParamsContextFunc(context.Background(), queryParams)
+	}
+	return nil, nil, notMocked("TaskAPI.ListParams")
+}
+
+func (m *TaskAPI) ListParamsContext(ctx context.Context, queryParams map[string]string) (*veil.TasksResponse, *http.Response, error) {
+	if m.ListParamsContextFunc != nil {
+		return m.ListParamsContextFunc(ctx, queryParams)
+	}
+	return nil, nil, notMocked("TaskAPI.ListParamsContext")
+}
+
+func (m *TaskAPI) ListFiltered(filter veil.TaskFilter) (*veil.TasksResponse, *http.Response, error) {
+	if m.ListFilteredFunc != nil {
+		return m.ListFilteredFunc(filter)
+	}
+	if m.ListFilteredContextFunc != nil {
+		return m.ListFilteredContextFunc(context.Background(), filter)
+	}
+	return nil, nil, notMocked("TaskAPI.ListFiltered")
+}
+
+func (m *TaskAPI) ListFilteredContext(ctx context.Context, filter veil.TaskFilter) (*veil.TasksResponse, *http.Response, error) {
+	if m.ListFilteredContextFunc != nil {
+		return m.ListFilteredContextFunc(ctx, filter)
+	}
+	return nil, nil, notMocked("TaskAPI.ListFilteredContext")
+}
+
+func (m *TaskAPI) Subtasks(Id string) (*veil.TasksResponse, *http.Response, error) {
+	if m.SubtasksFunc != nil {
+		return m.SubtasksFunc(Id)
+	}
+	if m.SubtasksContextFunc != nil {
+		return m.SubtasksContextFunc(context.Background(), Id)
+	}
+	return nil, nil, notMocked("TaskAPI.Subtasks")
+}
+
+func (m *TaskAPI) SubtasksContext(ctx context.Context, Id string) (*veil.TasksResponse, *http.Response, error) {
+	if m.SubtasksContextFunc != nil {
+		return m.SubtasksContextFunc(ctx, Id)
+	}
+	return nil, nil, notMocked("TaskAPI.SubtasksContext")
+}
+
+func (m *TaskAPI) Cancel(Id string) (bool, *http.Response, error) {
+	if m.CancelFunc != nil {
+		return m.CancelFunc(Id)
+	}
+	if m.CancelContextFunc != nil {
+		return m.CancelContextFunc(context.Background(), Id)
+	}
+	return false, nil, notMocked("TaskAPI.Cancel")
+}
+
+func (m *TaskAPI) CancelContext(ctx context.Context, Id string) (bool, *http.Response, error) {
+	if m.CancelContextFunc != nil {
+		return m.CancelContextFunc(ctx, Id)
+	}
+	return false, nil, notMocked("TaskAPI.CancelContext")
+}
+
+func (m *TaskAPI) Summary(Id string) (*veil.TaskSummary, *http.Response, error) {
+	if m.SummaryFunc != nil {
+		return m.SummaryFunc(Id)
+	}
+	if m.SummaryContextFunc != nil {
+		return m.SummaryContextFunc(context.Background(), Id)
+	}
+	return nil, nil, notMocked("TaskAPI.Summary")
+}
+
+func (m *TaskAPI) SummaryContext(ctx context.Context, Id string) (*veil.TaskSummary, *http.Response, error) {
+	if m.SummaryContextFunc != nil {
+		return m.SummaryContextFunc(ctx, Id)
+	}
+	return nil, nil, notMocked("TaskAPI.SummaryContext")
+}
+
+func (m *TaskAPI) Iter(ctx context.Context, filter veil.ListFilter, opts *veil.PageOptions) *veil.TaskIterator {
+	if m.IterFunc != nil {
+		return m.IterFunc(ctx, filter, opts)
+	}
+	return nil
+}
+
+func (m *TaskAPI) ListAll(ctx context.Context, filter veil.ListFilter) ([]veil.TaskObjectsList, error) {
+	if m.ListAllFunc != nil {
+		return m.ListAllFunc(ctx, filter)
+	}
+	return nil, notMocked("TaskAPI.ListAll")
+}
+
+func (m *TaskAPI) Get(Id string) (*veil.TaskObject, *http.Response, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(Id)
+	}
+	if m.GetContextFunc != nil {
+		return m.GetContextFunc(context.Background(), Id)
+	}
+	return nil, nil, notMocked("TaskAPI.Get")
+}
+
+func (m *TaskAPI) GetContext(ctx context.Context, Id string) (*veil.TaskObject, *http.Response, error) {
+	if m.GetContextFunc != nil {
+		return m.GetContextFunc(ctx, Id)
+	}
+	return nil, nil, notMocked("TaskAPI.GetContext")
+}
+
+func (m *TaskAPI) Response(Id string, object interface{}) (*http.Response, error) {
+	if m.ResponseFunc != nil {
+		return m.ResponseFunc(Id, object)
+	}
+	if m.ResponseContextFunc != nil {
+		return m.ResponseContextFunc(context.Background(), Id, object)
+	}
+	return nil, notMocked("TaskAPI.Response")
+}
+
+func (m *TaskAPI) ResponseContext(ctx context.Context, Id string, object interface{}) (*http.Response, error) {
+	if m.ResponseContextFunc != nil {
+		return m.ResponseContextFunc(ctx, Id, object)
+	}
+	return nil, notMocked("TaskAPI.ResponseContext")
+}
+
+func (m *TaskAPI) Wait(ctx context.Context, Id string, opts *veil.TaskWaitOptions) (*veil.TaskObject, error) {
+	if m.WaitFunc != nil {
+		return m.WaitFunc(ctx, Id, opts)
+	}
+	return nil, notMocked("TaskAPI.Wait")
+}
+
+// EventAPI mock of veil.EventAPI
+type EventAPI struct {
+	ListFunc                func() (*veil.EventsResponse, *http.Response, error)
+	ListContextFunc         func(ctx context.Context) (*veil.EventsResponse, *http.Response, error)
+	ListFilteredFunc        func(filter veil.EventFilter) (*veil.EventsResponse, *http.Response, error)
+	ListFilteredContextFunc func(ctx context.Context, filter veil.EventFilter) (*veil.EventsResponse, *http.Response, error)
+	IterFunc                func(ctx context.Context, filter veil.ListFilter, opts *veil.PageOptions) *veil.EventIterator
+	ListAllFunc             func(ctx context.Context, filter veil.ListFilter) ([]veil.EventObjectsList, error)
+	GetFunc                 func(Id string) (*veil.EventObject, *http.Response, error)
+	GetContextFunc          func(ctx context.Context, Id string) (*veil.EventObject, *http.Response, error)
+}
+
+var _ veil.EventAPI = (*EventAPI)(nil)
+
+func (m *EventAPI) List() (*veil.EventsResponse, *http.Response, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc()
+	}
+	if m.ListContextFunc != nil {
+		return m.ListContextFunc(context.Background())
+	}
+	return nil, nil, notMocked("EventAPI.List")
+}
+
+func (m *EventAPI) ListContext(ctx context.Context) (*veil.EventsResponse, *http.Response, error) {
+	if m.ListContextFunc != nil {
+		return m.ListContextFunc(ctx)
+	}
+	return nil, nil, notMocked("EventAPI.ListContext")
+}
+
+func (m *EventAPI) ListFiltered(filter veil.EventFilter) (*veil.EventsResponse, *http.Response, error) {
+	if m.ListFilteredFunc != nil {
+		return m.ListFilteredFunc(filter)
+	}
+	if m.ListFilteredContextFunc != nil {
+		return m.ListFilteredContextFunc(context.Background(), filter)
+	}
+	return nil, nil, notMocked("EventAPI.ListFiltered")
+}
+
+func (m *EventAPI) ListFilteredContext(ctx context.Context, filter veil.EventFilter) (*veil.EventsResponse, *http.Response, error) {
+	if m.ListFilteredContextFunc != nil {
+		return m.ListFilteredContextFunc(ctx, filter)
+	}
+	return nil, nil, notMocked("EventAPI.ListFilteredContext")
+}
+
+func (m *EventAPI) Iter(ctx context.Context, filter veil.ListFilter, opts *veil.PageOptions) *veil.EventIterator {
+	if m.IterFunc != nil {
+		return m.IterFunc(ctx, filter, opts)
+	}
+	return nil
+}
+
+func (m *EventAPI) ListAll(ctx context.Context, filter veil.ListFilter) ([]veil.EventObjectsList, error) {
+	if m.ListAllFunc != nil {
+		return m.ListAllFunc(ctx, filter)
+	}
+	return nil, notMocked("EventAPI.ListAll")
+}
+
+func (m *EventAPI) Get(Id string) (*veil.EventObject, *http.Response, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(Id)
+	}
+	if m.GetContextFunc != nil {
+		return m.GetContextFunc(context.Background(), Id)
+	}
+	return nil, nil, notMocked("EventAPI.Get")
+}
+
+func (m *EventAPI) GetContext(ctx context.Context, Id string) (*veil.EventObject, *http.Response, error) {
+	if m.GetContextFunc != nil {
+		return m.GetContextFunc(ctx, Id)
+	}
+	return nil, nil, notMocked("EventAPI.GetContext")
+}
+
+// UserAPI mock of veil.UserAPI
+type UserAPI struct {
+	ListFunc                func() (*veil.UsersResponse, *http.Response, error)
+	ListContextFunc         func(ctx context.Context) (*veil.UsersResponse, *http.Response, error)
+	ListParamsFunc          func(queryParams map[string]string) (*veil.UsersResponse, *http.Response, error)
+	ListParamsContextFunc   func(ctx context.Context, queryParams map[string]string) (*veil.UsersResponse, *http.Response, error)
+	ListFilteredFunc        func(filter veil.UserFilter) (*veil.UsersResponse, *http.Response, error)
+	ListFilteredContextFunc func(ctx context.Context, filter veil.UserFilter) (*veil.UsersResponse, *http.Response, error)
+	IterFunc                func(ctx context.Context, filter veil.ListFilter, opts *veil.PageOptions) *veil.UserIterator
+	ListAllFunc             func(ctx context.Context, filter veil.ListFilter) ([]veil.UserObjectsList, error)
+	GetFunc                 func(Id int) (*veil.UserObject, *http.Response, error)
+	GetContextFunc          func(ctx context.Context, Id int) (*veil.UserObject, *http.Response, error)
+}
+
+var _ veil.UserAPI = (*UserAPI)(nil)
+
+func (m *UserAPI) List() (*veil.UsersResponse, *http.Response, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc()
+	}
+	if m.ListContextFunc != nil {
+		return m.ListContextFunc(context.Background())
+	}
+	return nil, nil, notMocked("UserAPI.List")
+}
+
+func (m *UserAPI) ListContext(ctx context.Context) (*veil.UsersResponse, *http.Response, error) {
+	if m.ListContextFunc != nil {
+		return m.ListContextFunc(ctx)
+	}
+	return nil, nil, notMocked("UserAPI.ListContext")
+}
+
+func (m *UserAPI) ListParams(queryParams map[string]string) (*veil.UsersResponse, *http.Response, error) {
+	if m.ListParamsFunc != nil {
+		return m.ListParamsFunc(queryParams)
+	}
+	if m.ListParamsContextFunc != nil {
+		return m.ListParamsContextFunc(context.Background(), queryParams)
+	}
+	return nil, nil, notMocked("UserAPI.ListParams")
+}
+
+func (m *UserAPI) ListParamsContext(ctx context.Context, queryParams map[string]string) (*veil.UsersResponse, *http.Response, error) {
+	if m.ListParamsContextFunc != nil {
+		return m.ListParamsContextFunc(ctx, queryParams)
+	}
+	return nil, nil, notMocked("UserAPI.ListParamsContext")
+}
+
+func (m *UserAPI) ListFiltered(filter veil.UserFilter) (*veil.UsersResponse, *http.Response, error) {
+	if m.ListFilteredFunc != nil {
+		return m.ListFilteredFunc(filter)
+	}
+	if m.ListFilteredContextFunc != nil {
+		return m.ListFilteredContextFunc(context.Background(), filter)
+	}
+	return nil, nil, notMocked("UserAPI.ListFiltered")
+}
+
+func (m *UserAPI) ListFilteredContext(ctx context.Context, filter veil.UserFilter) (*veil.UsersResponse, *http.Response, error) {
+	if m.ListFilteredContextFunc != nil {
+		return m.ListFilteredContextFunc(ctx, filter)
+	}
+	return nil, nil, notMocked("UserAPI.ListFilteredContext")
+}
+
+func (m *UserAPI) Iter(ctx context.Context, filter veil.ListFilter, opts *veil.PageOptions) *veil.UserIterator {
+	if m.IterFunc != nil {
+		return m.IterFunc(ctx, filter, opts)
+	}
+	return nil
+}
+
+func (m *UserAPI) ListAll(ctx context.Context, filter veil.ListFilter) ([]veil.UserObjectsList, error) {
+	if m.ListAllFunc != nil {
+		return m.ListAllFunc(ctx, filter)
+	}
+	return nil, notMocked("UserAPI.ListAll")
+}
+
+func (m *UserAPI) Get(Id int) (*veil.UserObject, *http.Response, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(Id)
+	}
+	if m.GetContextFunc != nil {
+		return m.GetContextFunc(context.Background(), Id)
+	}
+	return nil, nil, notMocked("UserAPI.Get")
+}
+
+func (m *UserAPI) GetContext(ctx context.Context, Id int) (*veil.UserObject, *http.Response, error) {
+	if m.GetContextFunc != nil {
+		return m.GetContextFunc(ctx, Id)
+	}
+	return nil, nil, notMocked("UserAPI.GetContext")
+}
+
+// VnetAPI mock of veil.VnetAPI
+type VnetAPI struct {
+	ListFunc                func() (*veil.VnetsResponse, *http.Response, error)
+	ListContextFunc         func(ctx context.Context) (*veil.VnetsResponse, *http.Response, error)
+	ListParamsFunc          func(queryParams map[string]string) (*veil.VnetsResponse, *http.Response, error)
+	ListParamsContextFunc   func(ctx context.Context, queryParams map[string]string) (*veil.VnetsResponse, *http.Response, error)
+	ListFilteredFunc        func(filter veil.VnetFilter) (*veil.VnetsResponse, *http.Response, error)
+	ListFilteredContextFunc func(ctx context.Context, filter veil.VnetFilter) (*veil.VnetsResponse, *http.Response, error)
+	IterFunc                func(ctx context.Context, filter veil.ListFilter, opts *veil.PageOptions) *veil.VnetIterator
+	ListAllFunc             func(ctx context.Context, filter veil.ListFilter) ([]veil.VnetObjectsList, error)
+	GetFunc                 func(Id string) (*veil.VnetObject, *http.Response, error)
+	GetContextFunc          func(ctx context.Context, Id string) (*veil.VnetObject, *http.Response, error)
+}
+
+var _ veil.VnetAPI = (*VnetAPI)(nil)
+
+func (m *VnetAPI) List() (*veil.VnetsResponse, *http.Response, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc()
+	}
+	if m.ListContextFunc != nil {
+		return m.ListContextFunc(context.Background())
+	}
+	return nil, nil, notMocked("VnetAPI.List")
+}
+
+func (m *VnetAPI) ListContext(ctx context.Context) (*veil.VnetsResponse, *http.Response, error) {
+	if m.ListContextFunc != nil {
+		return m.ListContextFunc(ctx)
+	}
+	return nil, nil, notMocked("VnetAPI.ListContext")
+}
+
+func (m *VnetAPI) ListParams(queryParams map[string]string) (*veil.VnetsResponse, *http.Response, error) {
+	if m.ListParamsFunc != nil {
+		return m.ListParamsFunc(queryParams)
+	}
+	if m.ListParamsContextFunc != nil {
+		return m.ListParamsContextFunc(context.Background(), queryParams)
+	}
+	return nil, nil, notMocked("VnetAPI.ListParams")
+}
+
+func (m *VnetAPI) ListParamsContext(ctx context.Context, queryParams map[string]string) (*veil.VnetsResponse, *http.Response, error) {
+	if m.ListParamsContextFunc != nil {
+		return m.ListParamsContextFunc(ctx, queryParams)
+	}
+	return nil, nil, notMocked("VnetAPI.ListParamsContext")
+}
+
+func (m *VnetAPI) ListFiltered(filter veil.VnetFilter) (*veil.VnetsResponse, *http.Response, error) {
+	if m.ListFilteredFunc != nil {
+		return m.ListFilteredFunc(filter)
+	}
+	if m.ListFilteredContextFunc != nil {
+		return m.ListFilteredContextFunc(context.Background(), filter)
+	}
+	return nil, nil, notMocked("VnetAPI.ListFiltered")
+}
+
+func (m *VnetAPI) ListFilteredContext(ctx context.Context, filter veil.VnetFilter) (*veil.VnetsResponse, *http.Response, error) {
+	if m.ListFilteredContextFunc != nil {
+		return m.ListFilteredContextFunc(ctx, filter)
+	}
+	return nil, nil, notMocked("VnetAPI.ListFilteredContext")
+}
+
+func (m *VnetAPI) Iter(ctx context.Context, filter veil.ListFilter, opts *veil.PageOptions) *veil.VnetIterator {
+	if m.IterFunc != nil {
+		return m.IterFunc(ctx, filter, opts)
+	}
+	return nil
+}
+
+func (m *VnetAPI) ListAll(ctx context.Context, filter veil.ListFilter) ([]veil.VnetObjectsList, error) {
+	if m.ListAllFunc != nil {
+		return m.ListAllFunc(ctx, filter)
+	}
+	return nil, notMocked("VnetAPI.ListAll")
+}
+
+func (m *VnetAPI) Get(Id string) (*veil.VnetObject, *http.Response, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(Id)
+	}
+	if m.GetContextFunc != nil {
+		return m.GetContextFunc(context.Background(), Id)
+	}
+	return nil, nil, notMocked("VnetAPI.Get")
+}
+
+func (m *VnetAPI) GetContext(ctx context.Context, Id string) (*veil.VnetObject, *http.Response, error) {
+	if m.GetContextFunc != nil {
+		return m.GetContextFunc(ctx, Id)
+	}
+	return nil, nil, notMocked("VnetAPI.GetContext")
+}
+
+// VMachineInfAPI mock of veil.VMachineInfAPI
+type VMachineInfAPI struct {
+	ListFunc                func() (*veil.VMachinesResponse, *http.Response, error)
+	ListContextFunc         func(ctx context.Context) (*veil.VMachinesResponse, *http.Response, error)
+	ListParamsFunc          func(queryParams map[string]string) (*veil.VMachinesResponse, *http.Response, error)
+	ListParamsContextFunc   func(ctx context.Context, queryParams map[string]string) (*veil.VMachinesResponse, *http.Response, error)
+	ListFilteredFunc        func(filter veil.VMachineInfFilter) (*veil.VMachinesResponse, *http.Response, error)
+	ListFilteredContextFunc func(ctx context.Context, filter veil.VMachineInfFilter) (*veil.VMachinesResponse, *http.Response, error)
+	IterFunc                func(ctx context.Context, filter veil.ListFilter, opts *veil.PageOptions) *veil.VMachineInfIterator
+	ListAllFunc             func(ctx context.Context, filter veil.ListFilter) ([]veil.VMachineInfObjectsList, error)
+	GetFunc                 func(Id string) (*veil.VMachineInfObject, *http.Response, error)
+	GetContextFunc          func(ctx context.Context, Id string) (*veil.VMachineInfObject, *http.Response, error)
+}
+
+var _ veil.VMachineInfAPI = (*VMachineInfAPI)(nil)
+
+func (m *VMachineInfAPI) List() (*veil.VMachinesResponse, *http.Response, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc()
+	}
+	if m.ListContextFunc != nil {
+		return m.ListContextFunc(context.Background())
+	}
+	return nil, nil, notMocked("VMachineInfAPI.List")
+}
+
+func (m *VMachineInfAPI) ListContext(ctx context.Context) (*veil.VMachinesResponse, *http.Response, error) {
+	if m.ListContextFunc != nil {
+		return m.ListContextFunc(ctx)
+	}
+	return nil, nil, notMocked("VMachineInfAPI.ListContext")
+}
+
+func (m *VMachineInfAPI) ListParams(queryParams map[string]string) (*veil.VMachinesResponse, *http.Response, error) {
+	if m.ListParamsFunc != nil {
+		return m.ListParamsFunc(queryParams)
+	}
+	if m.ListParamsContextFunc != nil {
+		return m.ListParamsContextFunc(context.Background(), queryParams)
+	}
+	return nil, nil, notMocked("VMachineInfAPI.ListParams")
+}
+
+func (m *VMachineInfAPI) ListParamsContext(ctx context.Context, queryParams map[string]string) (*veil.VMachinesResponse, *http.Response, error) {
+	if m.ListParamsContextFunc != nil {
+		return m.ListParamsContextFunc(ctx, queryParams)
+	}
+	return nil, nil, notMocked("VMachineInfAPI.ListParamsContext")
+}
+
+func (m *VMachineInfAPI) ListFiltered(filter veil.VMachineInfFilter) (*veil.VMachinesResponse, *http.Response, error) {
+	if m.ListFilteredFunc != nil {
+		return m.ListFilteredFunc(filter)
+	}
+	if m.ListFilteredContextFunc != nil {
+		return m.ListFilteredContextFunc(context.Background(), filter)
+	}
+	return nil, nil, notMocked("VMachineInfAPI.ListFiltered")
+}
+
+func (m *VMachineInfAPI) ListFilteredContext(ctx context.Context, filter veil.VMachineInfFilter) (*veil.VMachinesResponse, *http.Response, error) {
+	if m.ListFilteredContextFunc != nil {
+		return m.ListFilteredContextFunc(ctx, filter)
+	}
+	return nil, nil, notMocked("VMachineInfAPI.ListFilteredContext")
+}
+
+func (m *VMachineInfAPI) Iter(ctx context.Context, filter veil.ListFilter, opts *veil.PageOptions) *veil.VMachineInfIterator {
+	if m.IterFunc != nil {
+		return m.IterFunc(ctx, filter, opts)
+	}
+	return nil
+}
+
+func (m *VMachineInfAPI) ListAll(ctx context.Context, filter veil.ListFilter) ([]veil.VMachineInfObjectsList, error) {
+	if m.ListAllFunc != nil {
+		return m.ListAllFunc(ctx, filter)
+	}
+	return nil, notMocked("VMachineInfAPI.ListAll")
+}
+
+func (m *VMachineInfAPI) Get(Id string) (*veil.VMachineInfObject, *http.Response, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(Id)
+	}
+	if m.GetContextFunc != nil {
+		return m.GetContextFunc(context.Background(), Id)
+	}
+	return nil, nil, notMocked("VMachineInfAPI.Get")
+}
+
+func (m *VMachineInfAPI) GetContext(ctx context.Context, Id string) (*veil.VMachineInfObject, *http.Response, error) {
+	if m.GetContextFunc != nil {
+		return m.GetContextFunc(ctx, Id)
+	}
+	return nil, nil, notMocked("VMachineInfAPI.GetContext")
+}
+
+// SwaggerAPI mock of veil.SwaggerAPI
+type SwaggerAPI struct {
+	GetFunc        func() (*veil.Swagger, *http.Response, error)
+	GetContextFunc func(ctx context.Context) (*veil.Swagger, *http.Response, error)
+}
+
+var _ veil.SwaggerAPI = (*SwaggerAPI)(nil)
+
+func (m *SwaggerAPI) Get() (*veil.Swagger, *http.Response, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc()
+	}
+	if m.GetContextFunc != nil {
+		return m.GetContextFunc(context.Background())
+	}
+	return nil, nil, notMocked("SwaggerAPI.Get")
+}
+
+func (m *SwaggerAPI) GetContext(ctx context.Context) (*veil.Swagger, *http.Response, error) {
+	if m.GetContextFunc != nil {
+		return m.GetContextFunc(ctx)
+	}
+	return nil, nil, notMocked("SwaggerAPI.GetContext")
+}
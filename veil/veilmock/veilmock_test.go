@@ -0,0 +1,79 @@
+package veilmock
+
+import (
+	"context"
+	"github.com/jsc-masshtab/veil-api-client-go/veil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+// startedDomains is an example of code depending on a single operation
+func startedDomains(ctx context.Context, domains veil.DomainAPI, ids []string) ([]string, error) {
+	var started []string
+	for _, id := range ids {
+		domain, _, err := domains.GetContext(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if domain.UserPowerState == 3 {
+			started = append(started, domain.Id)
+		}
+	}
+	return started, nil
+}
+
+func Test_MockDomainAPI(t *testing.T) {
+	domains := &DomainAPI{
+		GetContextFunc: func(ctx context.Context, Id string) (*veil.DomainObject, *http.Response, error) {
+			state := 1
+			if Id == "on" {
+				state = 3
+			}
+			return &veil.DomainObject{Id: Id, UserPowerState: state}, nil, nil
+		},
+	}
+	started, err := startedDomains(context.Background(), domains, []string{"off", "on"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"on"}, started)
+
+	// Method without context uses the function of Context variant
+	domain, _, err := domains.Get("on")
+	require.NoError(t, err)
+	assert.Equal(t, "on", domain.Id)
+
+	_, _, err = domains.Start(domain)
+	assert.ErrorIs(t, err, ErrNotMocked)
+	assert.Contains(t, err.Error(), "DomainAPI.Start")
+	return
+}
+
+func Test_MockClientAsyncOperation(t *testing.T) {
+	const taskId = "task-1"
+	var polls int
+	client := &Client{
+		ExecuteRequestContextFunc: func(ctx context.Context, method, url string, body []byte, object interface{}) (*http.Response, error) {
+			switch url {
+			case "/api/domains/source/clone/?async=1":
+				return Respond(object, map[string]interface{}{"_task": map[string]string{"id": taskId}, "entity": "source"})
+			case "/api/tasks/" + taskId + "/":
+				polls++
+				return Respond(object, veil.TaskObject{Id: taskId, Status: veil.TaskStatus.Success, Progress: 100})
+			case "/api/tasks/" + taskId + "/response/":
+				return Respond(object, veil.DomainObject{Id: "clone", VerboseName: "vm-clone"})
+			}
+			t.Fatalf("unexpected request %s %s", method, url)
+			return nil, nil
+		},
+	}
+	domains := veil.NewDomainService(client)
+	clone, _, err := domains.Clone("source", veil.DomainCloneConfig{VerboseName: "vm-clone"})
+	require.NoError(t, err)
+	assert.Equal(t, "clone", clone.Id)
+	assert.Equal(t, 1, polls)
+
+	_, err = (&Client{}).Execute(nil)
+	assert.ErrorIs(t, err, ErrNotMocked)
+	return
+}
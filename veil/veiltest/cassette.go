@@ -0,0 +1,359 @@
+package veiltest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Redacted replaces secrets in cassettes
+const Redacted = "REDACTED"
+
+// DefaultRedactFields JSON fields of bodies which values are replaced with Redacted
+var DefaultRedactFields = []string{"password", "token", "jwt", "secret", "refresh_token", "access_token"}
+
+// DefaultIgnoreFields JSON fields of request bodies which are ignored by matching, they change on every run
+var DefaultIgnoreFields = []string{"idempotency_key"}
+
+// jwtPattern matches JWT tokens in urls and non JSON bodies
+var jwtPattern = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+
+// redactHeaders response headers which values are replaced with Redacted
+var redactHeaders = []string{"Authorization", "Set-Cookie", "Cookie"}
+
+// ErrNoInteraction is wrapped by the error returned in replay mode when the cassette has no matching request
+var ErrNoInteraction = errors.New("cassette has no matching interaction")
+
+// Mode of Recorder
+type Mode int
+
+const (
+	// ModeReplay responds from the cassette, requests are not sent
+	ModeReplay Mode = iota
+	// ModeRecord sends requests and records interactions, Save writes the cassette
+	ModeRecord
+	// ModeAuto replays if the cassette file exists and records otherwise
+	ModeAuto
+)
+
+// CassetteRequest recorded request, secrets are redacted
+type CassetteRequest struct {
+	Method string `yaml:"method" json:"method"`
+	Path   string `yaml:"path" json:"path"`
+	Query  string `yaml:"query,omitempty" json:"query,omitempty"`
+	Body   string `yaml:"body,omitempty" json:"body,omitempty"`
+}
+
+// CassetteResponse recorded response, secrets are redacted
+type CassetteResponse struct {
+	Status  int                 `yaml:"status" json:"status"`
+	Headers map[string][]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+	Body    string              `yaml:"body,omitempty" json:"body,omitempty"`
+}
+
+// Interaction request with its response
+type Interaction struct {
+	Request  CassetteRequest  `yaml:"request" json:"request"`
+	Response CassetteResponse `yaml:"response" json:"response"`
+}
+
+// Cassette recorded interactions in order, files with .json extension are JSON and YAML otherwise
+type Cassette struct {
+	Interactions []Interaction `yaml:"interactions" json:"interactions"`
+}
+
+// RecorderOptions of Recorder, zero values mean defaults
+type RecorderOptions struct {
+	// Mode ModeReplay by default
+	Mode Mode
+	// Transport sends requests in record mode, http.DefaultTransport by default
+	Transport http.RoundTripper
+	// RedactFields JSON fields which values are not stored, DefaultRedactFields by default
+	RedactFields []string
+	// IgnoreFields JSON fields of request bodies ignored by matching, DefaultIgnoreFields by default
+	IgnoreFields []string
+}
+
+// Recorder http.RoundTripper which records interactions with the controller to a cassette or replays them.
+// Requests are matched on method, path, query and normalized JSON body, the host is ignored. Equal requests,
+// e.g. polls of a task, are replayed in the recorded order and the last response is repeated after that.
+//
+//	recorder, err := veiltest.NewRecorder("testdata/clone.yaml", &veiltest.RecorderOptions{Mode: veiltest.ModeAuto})
+//	defer recorder.Save()
+//	client, err := veil.NewClientWithOptions(veil.WithBaseURL(url), veil.WithTransport(recorder))
+type Recorder struct {
+	path   string
+	mode   Mode
+	opts   RecorderOptions
+	redact map[string]bool
+	ignore map[string]bool
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// NewRecorder creates recorder of cassette at path, the cassette is loaded in replay mode. opts may be nil
+func NewRecorder(path string, opts *RecorderOptions) (*Recorder, error) {
+	r := &Recorder{path: path, redact: map[string]bool{}, ignore: map[string]bool{}}
+	if opts != nil {
+		r.opts = *opts
+	}
+	if r.opts.Transport == nil {
+		r.opts.Transport = http.DefaultTransport
+	}
+	if r.opts.RedactFields == nil {
+		r.opts.RedactFields = DefaultRedactFields
+	}
+	if r.opts.IgnoreFields == nil {
+		r.opts.IgnoreFields = DefaultIgnoreFields
+	}
+	for _, f := range r.opts.RedactFields {
+		r.redact[strings.ToLower(f)] = true
+	}
+	for _, f := range r.opts.IgnoreFields {
+		r.ignore[strings.ToLower(f)] = true
+	}
+	r.mode = r.opts.Mode
+	if r.mode == ModeAuto {
+		r.mode = ModeRecord
+		if _, err := os.Stat(path); err == nil {
+			r.mode = ModeReplay
+		}
+	}
+	if r.mode == ModeReplay {
+		cassette, err := LoadCassette(path)
+		if err != nil {
+			return nil, err
+		}
+		r.cassette = *cassette
+		r.used = make([]bool, len(cassette.Interactions))
+	}
+	return r, nil
+}
+
+// Mode returns ModeRecord or ModeReplay
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Cassette returns copy of recorded or loaded interactions
+func (r *Recorder) Cassette() Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return Cassette{Interactions: append([]Interaction{}, r.cassette.Interactions...)}
+}
+
+// RoundTrip sends the request and records it or responds from the cassette
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	recorded := CassetteRequest{
+		Method: req.Method,
+		Path:   r.redactString(req.URL.Path),
+		Query:  r.redactQuery(req.URL.Query()),
+		Body:   r.redactBody(body),
+	}
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+	return r.record(req, recorded)
+}
+
+func (r *Recorder) record(req *http.Request, recorded CassetteRequest) (*http.Response, error) {
+	res, err := r.opts.Transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+	headers := map[string][]string{}
+	for name, values := range res.Header {
+		headers[name] = append([]string{}, values...)
+	}
+	for _, name := range redactHeaders {
+		if _, ok := headers[http.CanonicalHeaderKey(name)]; ok {
+			headers[http.CanonicalHeaderKey(name)] = []string{Redacted}
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request:  recorded,
+		Response: CassetteResponse{Status: res.StatusCode, Headers: headers, Body: r.redactBody(body)},
+	})
+	return res, nil
+}
+
+func (r *Recorder) replay(req *http.Request, recorded CassetteRequest) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	last := -1
+	for i, interaction := range r.cassette.Interactions {
+		if !r.matches(interaction.Request, recorded) {
+			continue
+		}
+		last = i
+		if !r.used[i] {
+			break
+		}
+	}
+	if last < 0 {
+		return nil, fmt.Errorf("%w: %s %s?%s", ErrNoInteraction, recorded.Method, recorded.Path, recorded.Query)
+	}
+	r.used[last] = true
+	response := r.cassette.Interactions[last].Response
+	header := http.Header{}
+	for name, values := range response.Headers {
+		header[name] = append([]string{}, values...)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", response.Status, http.StatusText(response.Status)),
+		StatusCode:    response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(response.Body)),
+		ContentLength: int64(len(response.Body)),
+		Request:       req,
+	}, nil
+}
+
+func (r *Recorder) matches(recorded CassetteRequest, req CassetteRequest) bool {
+	return recorded.Method == req.Method && recorded.Path == req.Path && recorded.Query == req.Query &&
+		r.normalizeBody(recorded.Body) == r.normalizeBody(req.Body)
+}
+
+// Save writes recorded interactions to the cassette file, it does nothing in replay mode
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	return r.Cassette().Save(r.path)
+}
+
+// LoadCassette reads cassette file
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cassette := new(Cassette)
+	if isJSONPath(path) {
+		err = json.Unmarshal(data, cassette)
+	} else {
+		err = yaml.Unmarshal(data, cassette)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read cassette %s: %w", path, err)
+	}
+	return cassette, nil
+}
+
+// Save writes cassette file, missing directories are created
+func (c Cassette) Save(path string) error {
+	var data []byte
+	var err error
+	if isJSONPath(path) {
+		data, err = json.MarshalIndent(c, "", "  ")
+	} else {
+		data, err = yaml.Marshal(c)
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+func isJSONPath(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".json")
+}
+
+// redactBody replaces values of redacted fields in JSON body and JWT tokens in other bodies
+func (r *Recorder) redactBody(body []byte) string {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return r.redactString(string(body))
+	}
+	value = r.walk(value, func(key string) bool { return r.redact[strings.ToLower(key)] }, Redacted)
+	b, _ := json.Marshal(value)
+	return r.redactString(string(b))
+}
+
+// redactQuery replaces values of redacted fields in query parameters and JWT tokens in others
+func (r *Recorder) redactQuery(query url.Values) string {
+	for key, values := range query {
+		if !r.redact[strings.ToLower(key)] {
+			continue
+		}
+		for i, value := range values {
+			if value != "" {
+				values[i] = Redacted
+			}
+		}
+	}
+	return r.redactString(query.Encode())
+}
+
+// normalizeBody sorts keys of JSON body and drops ignored fields, other bodies are compared as is
+func (r *Recorder) normalizeBody(body string) string {
+	var value interface{}
+	if err := json.Unmarshal([]byte(body), &value); err != nil {
+		return body
+	}
+	value = r.walk(value, func(key string) bool { return r.ignore[strings.ToLower(key)] }, nil)
+	b, _ := json.Marshal(value)
+	return string(b)
+}
+
+// walk replaces values of fields selected by match with replacement, nil replacement removes the fields
+func (r *Recorder) walk(value interface{}, match func(key string) bool, replacement interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if !match(key) {
+				v[key] = r.walk(item, match, replacement)
+			} else if replacement == nil {
+				delete(v, key)
+			} else if item != nil && item != "" {
+				v[key] = replacement
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = r.walk(item, match, replacement)
+		}
+	}
+	return value
+}
+
+func (r *Recorder) redactString(s string) string {
+	return jwtPattern.ReplaceAllString(s, Redacted)
+}
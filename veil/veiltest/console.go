@@ -0,0 +1,58 @@
+package veiltest
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Console ports of domains, TLS port is the next one
+const (
+	SpicePort = 5900
+	VncPort   = 5910
+)
+
+// consoleAction serves remote access switch and console connections, the password of a domain is constant
+// and the ticket is new on every request. It reports false for other actions
+func (s *Server) consoleAction(c *call, domain Object, action string, fields Object) bool {
+	switch {
+	case action == "remote-access" && c.r.Method == http.MethodPut:
+		enabled, _ := fields["remote_access"].(bool)
+		domain["remote_access"] = enabled
+		domain["modified"] = now()
+		writeJSON(c.w, http.StatusOK, domain)
+	case (action == "spice" || action == "vnc") && c.r.Method == http.MethodGet:
+		if enabled, _ := domain["remote_access"].(bool); !enabled {
+			writeError(c.w, http.StatusBadRequest, "remote access is disabled")
+			return true
+		}
+		if state, _ := number(domain["user_power_state"]); state != PowerOn {
+			writeError(c.w, http.StatusBadRequest, "domain is not running")
+			return true
+		}
+		port := SpicePort
+		if action == "vnc" {
+			port = VncPort
+		}
+		host := "127.0.0.1"
+		if node := s.get(Nodes, valueString(domain["node"])); node != nil && valueString(node["management_ip"]) != "" {
+			host = valueString(node["management_ip"])
+		}
+		writeJSON(c.w, http.StatusOK, Object{
+			"protocol":       action,
+			"host":           host,
+			"port":           port,
+			"tls_port":       port + 1,
+			"password":       fmt.Sprintf("%x", sha1.Sum([]byte(valueString(domain["id"]))))[:12],
+			"ticket":         strings.ReplaceAll(uuid.NewString(), "-", "")[:16],
+			"ticket_expires": time.Now().Add(time.Minute).UTC().Format(time.RFC3339),
+		})
+	default:
+		return false
+	}
+	return true
+}
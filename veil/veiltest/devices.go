@@ -0,0 +1,168 @@
+package veiltest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// deviceAction serves hot-plug of vdisks, cdroms and network interfaces, it reports false for other actions
+func (s *Server) deviceAction(c *call, domain Object, action string, fields Object) bool {
+	if c.r.Method == http.MethodGet && action == "cdroms" {
+		cdroms := append([]Object{}, s.cdroms[valueString(domain["id"])]...)
+		writeJSON(c.w, http.StatusOK, Object{"count": len(cdroms), "results": cdroms})
+		return true
+	}
+	if c.r.Method != http.MethodPost {
+		return false
+	}
+	switch action {
+	case "attach-vdisk":
+		s.attachVdisk(c, domain, fields)
+	case "detach-vdisk":
+		vdisk := s.get(Vdisks, valueString(fields["vdisk"]))
+		if vdisk == nil || valueString(vdisk["domain"]) != domain["id"] {
+			writeError(c.w, http.StatusBadRequest, "vdisk is not attached to the domain")
+			return true
+		}
+		vdisk["domain"] = nil
+		s.countDevices(domain, "vdisks_count", -1)
+		writeJSON(c.w, http.StatusOK, domain)
+	case "create-attach-vdisk":
+		vdisk := s.newVdisk(fields, "CREATING")
+		vdisk["target_bus"] = fields["target_bus"]
+		vdisk["driver_cache"] = fields["driver_cache"]
+		s.startAsync(c, "Create and attach virtual disk", Vdisks, vdisk, func() Object {
+			vdisk["domain"] = s.ref(Domains, valueString(domain["id"]))
+			s.countDevices(domain, "vdisks_count", 1)
+			return vdisk
+		})
+	case "attach-iso":
+		s.insertIso(c, domain, fields)
+	case "detach-iso":
+		cdrom := s.cdrom(domain, valueString(fields["cdrom"]))
+		if cdrom == nil {
+			writeError(c.w, http.StatusBadRequest, fmt.Sprintf("cdrom %s not found", fields["cdrom"]))
+			return true
+		}
+		cdrom["iso"] = nil
+		writeJSON(c.w, http.StatusOK, domain)
+	case "add-vmachine-inf":
+		s.addInterface(c, domain, fields)
+	case "remove-vmachine-inf", "relink-vmachine-inf":
+		id := valueString(fields["vmachine_inf"])
+		inf := s.get(VMachineInfs, id)
+		if inf == nil || valueString(inf["vmachine_info"]) != domain["id"] {
+			writeError(c.w, http.StatusBadRequest, fmt.Sprintf("interface %s of the domain not found", id))
+			return true
+		}
+		if action == "relink-vmachine-inf" {
+			s.relinkInterface(c, inf, fields)
+			return true
+		}
+		s.collection(VMachineInfs).remove(id)
+		s.countDevices(domain, "vmachine_infs_count", -1)
+		writeJSON(c.w, http.StatusOK, domain)
+	default:
+		return false
+	}
+	return true
+}
+
+func (s *Server) attachVdisk(c *call, domain Object, fields Object) {
+	id := valueString(fields["vdisk"])
+	vdisk := s.get(Vdisks, id)
+	switch {
+	case vdisk == nil:
+		writeError(c.w, http.StatusBadRequest, fmt.Sprintf("vdisk %s not found", id))
+		return
+	case valueString(vdisk["domain"]) != "":
+		writeError(c.w, http.StatusBadRequest, "vdisk is already attached")
+		return
+	}
+	vdisk["domain"] = s.ref(Domains, valueString(domain["id"]))
+	vdisk["target_bus"] = fields["target_bus"]
+	vdisk["driver_cache"] = fields["driver_cache"]
+	s.countDevices(domain, "vdisks_count", 1)
+	writeJSON(c.w, http.StatusOK, domain)
+}
+
+// insertIso inserts iso into the cdrom or into a new cdrom if cdrom is not set
+func (s *Server) insertIso(c *call, domain Object, fields Object) {
+	id := valueString(fields["iso"])
+	iso := s.get(Isos, id)
+	if iso == nil {
+		writeError(c.w, http.StatusBadRequest, fmt.Sprintf("iso %s not found", id))
+		return
+	}
+	cdrom := s.cdrom(domain, valueString(fields["cdrom"]))
+	if valueString(fields["cdrom"]) == "" {
+		cdrom = Object{"id": uuid.NewString(), "target_bus": "ide", "domain": s.ref(Domains, valueString(domain["id"]))}
+		s.cdroms[valueString(domain["id"])] = append(s.cdroms[valueString(domain["id"])], cdrom)
+	}
+	if cdrom == nil {
+		writeError(c.w, http.StatusBadRequest, fmt.Sprintf("cdrom %s not found", fields["cdrom"]))
+		return
+	}
+	cdrom["iso"] = Object{"id": id, "filename": iso["filename"]}
+	writeJSON(c.w, http.StatusOK, domain)
+}
+
+func (s *Server) cdrom(domain Object, id string) Object {
+	for _, cdrom := range s.cdroms[valueString(domain["id"])] {
+		if cdrom["id"] == id {
+			return cdrom
+		}
+	}
+	return nil
+}
+
+func (s *Server) addInterface(c *call, domain Object, fields Object) {
+	vnet := valueString(fields["vnetwork"])
+	if s.get(Vnets, vnet) == nil {
+		writeError(c.w, http.StatusBadRequest, fmt.Sprintf("vnetwork %s not found", vnet))
+		return
+	}
+	driver := valueString(fields["nic_driver"])
+	if driver == "" {
+		driver = "virtio"
+	}
+	mac := valueString(fields["mac_address"])
+	if mac == "" {
+		id := uuid.New()
+		mac = fmt.Sprintf("52:54:00:%02x:%02x:%02x", id[0], id[1], id[2])
+	}
+	linkState := valueString(fields["link_state"])
+	if linkState == "" {
+		linkState = "up"
+	}
+	inf := s.add(VMachineInfs, Object{
+		"name":          fmt.Sprint("vnet-", domain["vmachine_infs_count"]),
+		"vmachine_info": Object{"id": domain["id"], "verbose_name": domain["verbose_name"]},
+		"vnetwork_info": s.ref(Vnets, vnet),
+		"node_info":     domain["node"],
+		"nic_driver":    driver,
+		"mac_address":   mac,
+		"link_state":    linkState,
+	})
+	s.countDevices(domain, "vmachine_infs_count", 1)
+	writeJSON(c.w, http.StatusOK, inf)
+}
+
+func (s *Server) relinkInterface(c *call, inf Object, fields Object) {
+	vnet := valueString(fields["vnetwork"])
+	if s.get(Vnets, vnet) == nil {
+		writeError(c.w, http.StatusBadRequest, fmt.Sprintf("vnetwork %s not found", vnet))
+		return
+	}
+	inf["vnetwork_info"] = s.ref(Vnets, vnet)
+	inf["modified"] = now()
+	writeJSON(c.w, http.StatusOK, inf)
+}
+
+func (s *Server) countDevices(domain Object, field string, delta int) {
+	count, _ := number(domain[field])
+	domain[field] = int(count) + delta
+	domain["modified"] = now()
+}
@@ -0,0 +1,498 @@
+package veiltest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Power states of domains in user_power_state
+const (
+	PowerOff       = 1
+	PowerSuspended = 2
+	PowerOn        = 3
+)
+
+func (s *Server) login(c *call) {
+	credentials, err := c.object()
+	if err != nil || valueString(credentials["username"]) == "" {
+		writeError(c.w, http.StatusBadRequest, "username is required")
+		return
+	}
+	token := s.opts.Token
+	if token == "" {
+		token = Token
+	}
+	writeJSON(c.w, http.StatusOK, Object{"token": token, "username": credentials["username"]})
+}
+
+// create serves POST to a collection, vdisks are created asynchronously with ?async=1
+func (s *Server) create(c *call, res Resource) {
+	fields, err := c.object()
+	if err != nil {
+		writeError(c.w, http.StatusBadRequest, err.Error())
+		return
+	}
+	switch res {
+	case Domains:
+		domain := s.newDomain(fields, "ACTIVE")
+		writeJSON(c.w, http.StatusOK, domain)
+	case Vdisks:
+		if c.r.URL.Query().Get("async") != "1" {
+			writeJSON(c.w, http.StatusOK, s.newVdisk(fields, "ACTIVE"))
+			return
+		}
+		vdisk := s.newVdisk(fields, "CREATING")
+		s.startAsync(c, "Create virtual disk", Vdisks, vdisk, nil)
+	default:
+		writeError(c.w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) newDomain(fields Object, status string) Object {
+	domain := Object{
+		"verbose_name":     fields["verbose_name"],
+		"description":      fields["description"],
+		"memory_count":     fields["memory_count"],
+		"cpu_count":        fields["cpu_count"],
+		"cpu_count_max":    fields["cpu_count_max"],
+		"os_type":          fields["os_type"],
+		"status":           status,
+		"user_power_state": PowerOff,
+		"template":         false,
+		"guest_utils":      Object{"qemu_state": false},
+	}
+	if id := valueString(fields["domain_id"]); id != "" {
+		domain["id"] = id
+	}
+	node := valueString(fields["node"])
+	if node == "" {
+		node = NodeId
+	}
+	domain["node"] = s.ref(Nodes, node)
+	if fields["memory_count"] == nil {
+		domain["memory_count"] = 1024
+	}
+	if fields["cpu_count"] == nil {
+		domain["cpu_count"] = 1
+	}
+	if fields["cpu_count_max"] == nil {
+		domain["cpu_count_max"] = domain["cpu_count"]
+	}
+	domain["memory_count_max"] = domain["memory_count"]
+	vdisks, _ := fields["vdisks"].([]interface{})
+	newVdisks, _ := fields["new_vdisks"].([]interface{})
+	domain["vdisks_count"] = len(vdisks) + len(newVdisks)
+	interfaces, _ := fields["vmachine_infs"].([]interface{})
+	domain["vmachine_infs_count"] = len(interfaces)
+	return s.add(Domains, domain)
+}
+
+func (s *Server) newVdisk(fields Object, status string) Object {
+	datapool := valueString(fields["datapool"])
+	if datapool == "" {
+		datapool = DataPoolId
+	}
+	return s.add(Vdisks, Object{
+		"verbose_name": fields["verbose_name"],
+		"size":         fields["size"],
+		"status":       status,
+		"datapool":     s.ref(DataPools, datapool),
+		"disk_type":    "qcow2",
+		"domain":       nil,
+	})
+}
+
+// createFile serves PUT to iso and library collections, files from url are ready at once,
+// other files are ready after upload to upload_url
+func (s *Server) createFile(c *call, res Resource) {
+	if res != Isos && res != Library {
+		writeError(c.w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	fields, err := c.object()
+	if err != nil {
+		writeError(c.w, http.StatusBadRequest, err.Error())
+		return
+	}
+	datapool := valueString(fields["datapool"])
+	if s.get(DataPools, datapool) == nil {
+		writeError(c.w, http.StatusBadRequest, fmt.Sprintf("datapool %s not found", datapool))
+		return
+	}
+	file := Object{"datapool": s.ref(DataPools, datapool), "size": 0}
+	if source := valueString(fields["url"]); source != "" {
+		file["filename"] = source[strings.LastIndex(source, "/")+1:]
+		file["status"] = "ACTIVE"
+	} else {
+		file["filename"] = fields["filename"]
+		file["status"] = "CREATING"
+	}
+	file = s.add(res, file)
+	id := valueString(file["id"])
+	file["upload_url"] = fmt.Sprint(s.URL, "/upload/", res, "/", id, "/")
+	if file["status"] == "ACTIVE" {
+		s.files[string(res)+"/"+id] = []byte("veiltest " + valueString(file["filename"]))
+	}
+	writeJSON(c.w, http.StatusOK, file)
+}
+
+func (s *Server) upload(c *call, segments []string) {
+	if len(segments) != 2 || s.get(Resource(segments[0]), segments[1]) == nil {
+		writeError(c.w, http.StatusNotFound, "not found")
+		return
+	}
+	file := s.get(Resource(segments[0]), segments[1])
+	content := c.body
+	if part, _, err := c.r.FormFile("file"); err == nil {
+		content, _ = ioutil.ReadAll(part)
+		part.Close()
+	}
+	s.files[strings.Join(segments, "/")] = content
+	file["status"] = "ACTIVE"
+	file["size"] = len(content)
+	file["modified"] = now()
+	writeJSON(c.w, http.StatusOK, Object{})
+}
+
+func (s *Server) download(c *call, segments []string) {
+	content, ok := s.files[strings.Join(segments, "/")]
+	if !ok {
+		writeError(c.w, http.StatusNotFound, "not found")
+		return
+	}
+	c.w.Header().Set("Content-Type", "application/octet-stream")
+	c.w.Write(content)
+}
+
+// serveCollectionAction serves actions without entity, e.g. multi-create-domain
+func (s *Server) serveCollectionAction(c *call, res Resource, action string) {
+	if res != Domains || action != "multi-create-domain" || c.r.Method != http.MethodPost {
+		writeError(c.w, http.StatusNotFound, "not found")
+		return
+	}
+	fields, err := c.object()
+	if err != nil {
+		writeError(c.w, http.StatusBadRequest, err.Error())
+		return
+	}
+	domain := s.newDomain(fields, "CREATING")
+	startOn, _ := fields["start_on"].(bool)
+	s.startAsync(c, "Multi create domain", Domains, domain, func() Object {
+		if startOn {
+			domain["user_power_state"] = PowerOn
+		}
+		return domain
+	})
+}
+
+func (s *Server) serveEntityAction(c *call, res Resource, id string, action string) {
+	obj := s.get(res, id)
+	fields, err := c.object()
+	if err != nil {
+		writeError(c.w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if action == "remove" && c.r.Method == http.MethodPost && res != Tasks {
+		s.collection(res).remove(id)
+		writeJSON(c.w, http.StatusOK, Object{})
+		return
+	}
+	handled := true
+	switch res {
+	case Domains:
+		handled = s.domainAction(c, obj, action, fields)
+	case Vdisks:
+		handled = s.vdiskAction(c, obj, action, fields)
+	case Isos, Library:
+		handled = s.fileAction(c, res, obj, action, fields)
+	case Tasks:
+		handled = s.taskAction(c, id, action)
+	default:
+		handled = false
+	}
+	if !handled {
+		writeError(c.w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *Server) domainAction(c *call, domain Object, action string, fields Object) bool {
+	method := c.r.Method
+	switch {
+	case action == "start" && method == http.MethodPost, action == "resume" && method == http.MethodPost:
+		domain["user_power_state"] = PowerOn
+	case action == "reboot" && method == http.MethodPost:
+		if state, _ := number(domain["user_power_state"]); state != PowerOn {
+			writeError(c.w, http.StatusBadRequest, "domain is not running")
+			return true
+		}
+	case action == "suspend" && method == http.MethodPost:
+		domain["user_power_state"] = PowerSuspended
+	case action == "shutdown" && method == http.MethodPost:
+		domain["user_power_state"] = PowerOff
+	case action == "template" && method == http.MethodPut:
+		domain["template"] = fields["template"]
+	case action == "cloud-init" && method == http.MethodPut, action == "cpu" && method == http.MethodPut,
+		action == "memory" && method == http.MethodPut:
+		merge(domain, fields)
+	case action == "clone" && method == http.MethodPost:
+		s.clone(c, domain, fields)
+		return true
+	case action == "migrate" && method == http.MethodPost:
+		s.migrate(c, domain, fields)
+		return true
+	default:
+		return s.snapshotAction(c, domain, action, fields) || s.deviceAction(c, domain, action, fields) ||
+			s.consoleAction(c, domain, action, fields)
+	}
+	domain["modified"] = now()
+	writeJSON(c.w, http.StatusOK, domain)
+	return true
+}
+
+func (s *Server) clone(c *call, source Object, fields Object) {
+	name := valueString(fields["verbose_name"])
+	if name == "" {
+		name = valueString(source["verbose_name"]) + "-clone"
+	}
+	var snap *snapshot
+	if snapshotId := valueString(fields["snapshot"]); snapshotId != "" {
+		if snap = s.snapshot(c, valueString(source["id"]), snapshotId); snap == nil {
+			return
+		}
+	}
+	clone := copyObject(source)
+	if snap != nil {
+		merge(clone, copyObject(snap.state))
+	}
+	for _, field := range []string{"id", "created", "modified"} {
+		delete(clone, field)
+	}
+	clone["verbose_name"] = name
+	clone["status"] = "CREATING"
+	clone["user_power_state"] = PowerOff
+	clone["template"] = false
+	clone["parent"] = Object{"id": source["id"], "verbose_name": source["verbose_name"]}
+	if node := valueString(fields["node"]); node != "" {
+		clone["node"] = s.ref(Nodes, node)
+	}
+	clone = s.add(Domains, clone)
+	startOn, _ := fields["start_on"].(bool)
+	s.startAsync(c, "Clone domain", Domains, clone, func() Object {
+		if startOn {
+			clone["user_power_state"] = PowerOn
+		}
+		return clone
+	})
+}
+
+// migrate moves domain to target_node or to the first other node, live migration requires running domain
+// and offline one powered off domain
+func (s *Server) migrate(c *call, domain Object, fields Object) {
+	state, _ := number(domain["user_power_state"])
+	switch mode := valueString(fields["mode"]); {
+	case mode == "live" && state != PowerOn:
+		writeError(c.w, http.StatusBadRequest, "domain is not running, use offline migration")
+		return
+	case mode == "offline" && state != PowerOff:
+		writeError(c.w, http.StatusBadRequest, "domain must be powered off for offline migration")
+		return
+	case mode != "live" && mode != "offline":
+		writeError(c.w, http.StatusBadRequest, fmt.Sprintf("unknown migration mode %q", mode))
+		return
+	}
+	current := valueString(domain["node"])
+	target := valueString(fields["target_node"])
+	if target == "" {
+		for _, node := range s.collection(Nodes).all() {
+			if id := valueString(node["id"]); id != current && node["status"] == "ACTIVE" {
+				target = id
+				break
+			}
+		}
+	}
+	switch {
+	case target == "":
+		writeError(c.w, http.StatusBadRequest, "no suitable node for migration")
+		return
+	case target == current:
+		writeError(c.w, http.StatusBadRequest, "domain is already on the node")
+		return
+	case s.get(Nodes, target) == nil:
+		writeError(c.w, http.StatusBadRequest, fmt.Sprintf("node %s not found", target))
+		return
+	}
+	datapool := valueString(fields["datapool"])
+	if datapool != "" && s.get(DataPools, datapool) == nil {
+		writeError(c.w, http.StatusBadRequest, fmt.Sprintf("datapool %s not found", datapool))
+		return
+	}
+	s.startAsync(c, "Migrate domain", Domains, domain, func() Object {
+		domain["node"] = s.ref(Nodes, target)
+		if datapool != "" {
+			for _, vdisk := range s.collection(Vdisks).all() {
+				if valueString(vdisk["domain"]) == valueString(domain["id"]) {
+					vdisk["datapool"] = s.ref(DataPools, datapool)
+				}
+			}
+		}
+		domain["modified"] = now()
+		return domain
+	})
+}
+
+func (s *Server) vdiskAction(c *call, vdisk Object, action string, fields Object) bool {
+	if action == "consolidate" && c.r.Method == http.MethodPost {
+		s.startAsync(c, "Consolidate virtual disk", Vdisks, vdisk, func() Object {
+			vdisk["snapshots"] = []Object{}
+			return vdisk
+		})
+		return true
+	}
+	if action != "extend" || c.r.Method != http.MethodPost {
+		return false
+	}
+	size, _ := number(fields["size"])
+	current, _ := number(vdisk["size"])
+	if size <= current {
+		writeError(c.w, http.StatusBadRequest, "new size must be greater than current one")
+		return true
+	}
+	vdisk["size"] = size
+	vdisk["modified"] = now()
+	writeJSON(c.w, http.StatusOK, vdisk)
+	return true
+}
+
+func (s *Server) fileAction(c *call, res Resource, file Object, action string, fields Object) bool {
+	id := valueString(file["id"])
+	switch {
+	case action == "download" && c.r.Method == http.MethodPut:
+		file["download_url"] = fmt.Sprint(s.URL, "/download/", res, "/", id, "/")
+		writeJSON(c.w, http.StatusOK, file)
+	case action == "import-file" && c.r.Method == http.MethodPost && res == Library:
+		vdisk := s.newVdisk(Object{"verbose_name": fields["verbose_name"], "datapool": fields["datapool"], "size": file["size"]}, "CREATING")
+		if withDeletion, _ := fields["with_deletion"].(bool); withDeletion {
+			s.collection(Library).remove(id)
+		}
+		s.startAsync(c, "Import file", Vdisks, vdisk, nil)
+	default:
+		return false
+	}
+	return true
+}
+
+func (s *Server) taskAction(c *call, id string, action string) bool {
+	t := s.tasks[id]
+	switch {
+	case action == "response" && c.r.Method == http.MethodGet:
+		if t.object["status"] != "SUCCESS" {
+			writeError(c.w, http.StatusBadRequest, "task is not finished")
+			return true
+		}
+		writeJSON(c.w, http.StatusOK, t.response)
+	case action == "cancel" && c.r.Method == http.MethodPost:
+		if t.object["status"] != "IN_PROGRESS" {
+			writeError(c.w, http.StatusBadRequest, "task is already finished")
+			return true
+		}
+		t.cancel(s)
+		writeJSON(c.w, http.StatusOK, Object{})
+	default:
+		return false
+	}
+	return true
+}
+
+// task simulated VeiL task, it finishes after Options.TaskPolls polls
+type task struct {
+	object   Object
+	polls    int
+	res      Resource
+	entity   Object
+	fail     string
+	complete func() Object
+	response Object
+}
+
+// startAsync starts task creating entity and writes async response. complete is called when the task succeeds,
+// its result is the response of the task. By default the entity becomes ACTIVE and is the response
+func (s *Server) startAsync(c *call, name string, res Resource, entity Object, complete func() Object) {
+	t := &task{
+		object: Object{
+			"id":             uuid.NewString(),
+			"name":           name,
+			"verbose_name":   name,
+			"status":         "IN_PROGRESS",
+			"progress":       0,
+			"is_multitask":   false,
+			"is_cancellable": true,
+		},
+		res:      res,
+		entity:   entity,
+		fail:     c.taskError,
+		complete: complete,
+	}
+	t.object = s.add(Tasks, t.object)
+	t.object["status"] = "IN_PROGRESS"
+	s.tasks[valueString(t.object["id"])] = t
+	writeJSON(c.w, http.StatusAccepted, Object{"_task": t.object, "entity": entity["id"]})
+}
+
+// poll advances the task, the last poll finishes it
+func (t *task) poll(s *Server) {
+	if t.object["status"] != "IN_PROGRESS" {
+		return
+	}
+	t.polls++
+	if t.polls >= s.opts.TaskPolls {
+		t.finish(s, t.fail)
+		return
+	}
+	t.object["progress"] = t.polls * 100 / s.opts.TaskPolls
+}
+
+func (t *task) finish(s *Server, errorMessage string) {
+	if t.object["status"] != "IN_PROGRESS" {
+		return
+	}
+	t.object["finished_time"] = now()
+	t.object["is_cancellable"] = false
+	eventType := "info"
+	if errorMessage != "" {
+		t.object["status"] = "FAILED"
+		t.object["error_message"] = errorMessage
+		t.entity["status"] = "FAILED"
+		eventType = "error"
+	} else {
+		t.object["status"] = "SUCCESS"
+		t.object["progress"] = 100
+		t.entity["status"] = "ACTIVE"
+		t.response = t.entity
+		if t.complete != nil {
+			t.response = t.complete()
+		}
+	}
+	s.addEvent(t, eventType)
+}
+
+func (t *task) cancel(s *Server) {
+	t.object["status"] = "CANCELED"
+	t.object["finished_time"] = now()
+	t.object["is_cancellable"] = false
+	t.entity["status"] = "FAILED"
+	s.addEvent(t, "warning")
+}
+
+func (s *Server) addEvent(t *task, eventType string) {
+	s.add(Events, Object{
+		"message":  fmt.Sprintf("%s: %s", t.object["name"], strings.ToLower(valueString(t.object["status"]))),
+		"type":     eventType,
+		"task":     t.object["id"],
+		"user":     "admin",
+		"entities": []Object{{"entity_uuid": t.entity["id"], "entity_class": string(t.res)}},
+	})
+}
@@ -0,0 +1,674 @@
+// Package veiltest provides an in-process stateful fake of VeiL REST API for hermetic tests.
+// It serves the same urls and JSON shapes the veil services use, async calls start tasks
+// which progress every time they are polled.
+//
+//	server := veiltest.NewServer(nil)
+//	defer server.Close()
+//	client, err := veil.NewClientWithOptions(veil.WithBaseURL(server.URL), veil.WithToken(veiltest.Token))
+//
+// The package does not depend on veil, so it may be used by tests of veil itself.
+package veiltest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Ids of the entities every server is seeded with
+const (
+	ClusterId    = "6d1b2f3a-0c4e-4b8a-9e21-3f5a7c9d1e01"
+	NodeId       = "6d1b2f3a-0c4e-4b8a-9e21-3f5a7c9d1e02"
+	DataPoolId   = "6d1b2f3a-0c4e-4b8a-9e21-3f5a7c9d1e03"
+	VnetId       = "6d1b2f3a-0c4e-4b8a-9e21-3f5a7c9d1e04"
+	DataCenterId = "6d1b2f3a-0c4e-4b8a-9e21-3f5a7c9d1e05"
+	UserId       = 1
+)
+
+// Token returned by login if Options.Token is not set
+const Token = "veiltest-token"
+
+// Resource collection of entities, it is the url segment after /api/
+type Resource string
+
+const (
+	Domains      Resource = "domains"
+	Vdisks       Resource = "vdisks"
+	Isos         Resource = "iso"
+	Library      Resource = "library"
+	Tasks        Resource = "tasks"
+	Nodes        Resource = "nodes"
+	Clusters     Resource = "clusters"
+	DataPools    Resource = "data-pools"
+	DataCenters  Resource = "datacenters"
+	Vnets        Resource = "vnetworks"
+	VMachineInfs Resource = "vmachine-infs"
+	Users        Resource = "users"
+	Events       Resource = "events"
+)
+
+// Object entity as it is encoded to JSON
+type Object map[string]interface{}
+
+// Options of fake server, zero values mean defaults
+type Options struct {
+	// TaskPolls number of polls after which a task finishes, its progress grows evenly between polls. 1 if not set
+	TaskPolls int
+	// Latency delay of every response
+	Latency time.Duration
+	// Token if set, only requests with this token are authorized and login returns it
+	Token string
+}
+
+// Fault injected into responses of matching requests
+type Fault struct {
+	// Method of request, empty matches all methods
+	Method string
+	// Path pattern in path.Match syntax, e.g. "/api/domains/*/start/". Empty matches all paths
+	Path string
+	// Status of response, the request is not processed. Zero means the request is processed after Delay
+	Status int
+	// Body of response, VeiL error with the status text if empty
+	Body string
+	// Delay before response
+	Delay time.Duration
+	// TaskError makes the task started by the request fail with this message
+	TaskError string
+	// Times number of requests the fault is applied to, zero means all
+	Times int
+}
+
+func (f *Fault) matches(method string, urlPath string) bool {
+	if f.Method != "" && !strings.EqualFold(f.Method, method) {
+		return false
+	}
+	if f.Path == "" {
+		return true
+	}
+	ok, _ := path.Match(f.Path, urlPath)
+	return ok
+}
+
+// Request received by the server
+type Request struct {
+	Method   string
+	Path     string
+	RawQuery string
+	Body     []byte
+}
+
+// Server fake VeiL controller, it is safe for concurrent use
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	opts      Options
+	store     map[Resource]*collection
+	tasks     map[string]*task
+	snapshots map[string][]*snapshot
+	cdroms    map[string][]Object
+	files     map[string][]byte
+	faults    []*Fault
+	requests  []Request
+}
+
+// NewServer starts the fake server seeded with a cluster, node, datapool, vnet, datacenter and user.
+// opts may be nil, the caller closes the server
+func NewServer(opts *Options) *Server {
+	s := &Server{
+		store:     map[Resource]*collection{},
+		tasks:     map[string]*task{},
+		snapshots: map[string][]*snapshot{},
+		cdroms:    map[string][]Object{},
+		files:     map[string][]byte{},
+	}
+	if opts != nil {
+		s.opts = *opts
+	}
+	if s.opts.TaskPolls <= 0 {
+		s.opts.TaskPolls = 1
+	}
+	s.seed()
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+func (s *Server) seed() {
+	for _, res := range []Resource{Domains, Vdisks, Isos, Library, Tasks, Nodes, Clusters, DataPools, DataCenters,
+		Vnets, VMachineInfs, Users, Events} {
+		s.collection(res)
+	}
+	s.add(Clusters, Object{"id": ClusterId, "verbose_name": "cluster", "nodes_count": 1, "datacenter": Object{"id": DataCenterId, "verbose_name": "datacenter"}})
+	s.add(Nodes, Object{"id": NodeId, "verbose_name": "node", "management_ip": "127.0.0.1", "cluster": s.ref(Clusters, ClusterId)})
+	s.add(DataPools, Object{"id": DataPoolId, "verbose_name": "datapool", "type": "local", "free_space": 1024, "used_space": 0,
+		"node": s.ref(Nodes, NodeId), "cluster": s.ref(Clusters, ClusterId)})
+	s.add(Vnets, Object{"id": VnetId, "verbose_name": "default", "vlan_id": 0, "cluster": s.ref(Clusters, ClusterId)})
+	s.add(DataCenters, Object{"id": DataCenterId, "verbose_name": "datacenter"})
+	s.add(Users, Object{"id": UserId, "username": "admin", "is_active": true, "is_superuser": true})
+}
+
+// Add stores a copy of obj, id, created and status are filled if empty. It returns the stored object
+func (s *Server) Add(res Resource, obj Object) Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return copyObject(s.add(res, copyObject(obj)))
+}
+
+// Get returns a copy of the entity
+func (s *Server) Get(res Resource, id string) (Object, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj := s.get(res, id)
+	return copyObject(obj), obj != nil
+}
+
+// List returns copies of all entities in order of creation
+func (s *Server) List(res Resource) []Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var objects []Object
+	for _, obj := range s.collection(res).all() {
+		objects = append(objects, copyObject(obj))
+	}
+	return objects
+}
+
+// Update merges fields into the entity, it reports whether the entity exists
+func (s *Server) Update(res Resource, id string, fields Object) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj := s.get(res, id)
+	if obj == nil {
+		return false
+	}
+	merge(obj, copyObject(fields))
+	return true
+}
+
+// Delete removes the entity, it reports whether the entity existed
+func (s *Server) Delete(res Resource, id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.collection(res).remove(id)
+}
+
+// FinishTask finishes the task immediately, it succeeds if errorMessage is empty
+func (s *Server) FinishTask(id string, errorMessage string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.tasks[id]
+	if t == nil {
+		return false
+	}
+	t.finish(s, errorMessage)
+	return true
+}
+
+// Inject adds a fault, faults are checked in order of addition
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all faults
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// SetLatency changes delay of every response
+func (s *Server) SetLatency(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.opts.Latency = latency
+}
+
+// Requests returns all requests received by the server
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request{}, s.requests...)
+}
+
+// CountRequests returns number of received requests matching method and path pattern, empty values match all
+func (s *Server) CountRequests(method string, pattern string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f := Fault{Method: method, Path: pattern}
+	count := 0
+	for _, req := range s.requests {
+		if f.matches(req.Method, req.Path) {
+			count++
+		}
+	}
+	return count
+}
+
+// call request being served
+type call struct {
+	w         http.ResponseWriter
+	r         *http.Request
+	body      []byte
+	taskError string
+}
+
+// object decodes JSON body, empty body is an empty object
+func (c *call) object() (Object, error) {
+	obj := Object{}
+	if len(bytes.TrimSpace(c.body)) == 0 {
+		return obj, nil
+	}
+	err := json.Unmarshal(c.body, &obj)
+	return obj, err
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	c := &call{w: w, r: r, body: body}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, RawQuery: r.URL.RawQuery, Body: body})
+	latency := s.opts.Latency
+	fault := s.takeFault(r)
+	s.mu.Unlock()
+
+	delay := latency
+	if fault != nil {
+		delay += fault.Delay
+	}
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if fault != nil {
+		if fault.Status != 0 {
+			if fault.Body != "" {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(fault.Status)
+				w.Write([]byte(fault.Body))
+				return
+			}
+			writeError(w, fault.Status, http.StatusText(fault.Status))
+			return
+		}
+		c.taskError = fault.TaskError
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.route(c)
+}
+
+// takeFault returns the first matching fault and counts its use
+func (s *Server) takeFault(r *http.Request) *Fault {
+	for i, f := range s.faults {
+		if !f.matches(r.Method, r.URL.Path) {
+			continue
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	return s.opts.Token == "" || r.Header.Get("Authorization") == "jwt "+s.opts.Token
+}
+
+func (s *Server) route(c *call) {
+	urlPath := c.r.URL.Path
+	switch {
+	case urlPath == "/auth/":
+		s.login(c)
+		return
+	case urlPath == "/logout/":
+		writeJSON(c.w, http.StatusOK, Object{})
+		return
+	case strings.HasPrefix(urlPath, "/upload/"):
+		s.upload(c, splitPath(strings.TrimPrefix(urlPath, "/upload/")))
+		return
+	case strings.HasPrefix(urlPath, "/download/"):
+		s.download(c, splitPath(strings.TrimPrefix(urlPath, "/download/")))
+		return
+	case !strings.HasPrefix(urlPath, "/api/"):
+		writeError(c.w, http.StatusNotFound, "not found")
+		return
+	}
+	if !s.authorized(c.r) {
+		writeError(c.w, http.StatusUnauthorized, "invalid token")
+		return
+	}
+	segments := splitPath(strings.TrimPrefix(urlPath, "/api/"))
+	if len(segments) == 0 {
+		writeError(c.w, http.StatusNotFound, "not found")
+		return
+	}
+	res := Resource(segments[0])
+	if _, ok := s.store[res]; !ok {
+		writeError(c.w, http.StatusNotFound, "not found")
+		return
+	}
+	switch len(segments) {
+	case 1:
+		s.serveCollection(c, res)
+	case 2:
+		if c.r.Method == http.MethodGet || c.r.Method == http.MethodPut && s.get(res, segments[1]) != nil {
+			s.serveEntity(c, res, segments[1])
+			return
+		}
+		s.serveCollectionAction(c, res, segments[1])
+	case 3:
+		if s.get(res, segments[1]) == nil {
+			writeError(c.w, http.StatusNotFound, fmt.Sprintf("%s %s not found", res, segments[1]))
+			return
+		}
+		s.serveEntityAction(c, res, segments[1], segments[2])
+	case 4:
+		if res != Domains || segments[2] != "snapshots" || c.r.Method != http.MethodGet || s.get(res, segments[1]) == nil {
+			writeError(c.w, http.StatusNotFound, "not found")
+			return
+		}
+		s.getSnapshot(c, segments[1], segments[3])
+	default:
+		writeError(c.w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *Server) serveCollection(c *call, res Resource) {
+	switch c.r.Method {
+	case http.MethodGet:
+		s.list(c, res)
+	case http.MethodPost:
+		s.create(c, res)
+	case http.MethodPut:
+		s.createFile(c, res)
+	default:
+		writeError(c.w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) serveEntity(c *call, res Resource, id string) {
+	obj := s.get(res, id)
+	if obj == nil {
+		writeError(c.w, http.StatusNotFound, fmt.Sprintf("%s %s not found", res, id))
+		return
+	}
+	if c.r.Method == http.MethodGet {
+		// Tasks added by Add are not started by requests and do not progress
+		if t, ok := s.tasks[id]; ok && res == Tasks {
+			t.poll(s)
+		}
+		writeJSON(c.w, http.StatusOK, obj)
+		return
+	}
+	fields, err := c.object()
+	if err != nil {
+		writeError(c.w, http.StatusBadRequest, err.Error())
+		return
+	}
+	merge(obj, fields)
+	obj["modified"] = now()
+	writeJSON(c.w, http.StatusOK, obj)
+}
+
+// list serves list requests with limit/offset pagination, ordering, search and filters by fields of entities.
+// Filters by fields the entities do not have are ignored
+func (s *Server) list(c *call, res Resource) {
+	query := c.r.URL.Query()
+	var results []Object
+	for _, obj := range s.collection(res).all() {
+		if matchesQuery(obj, query) {
+			results = append(results, obj)
+		}
+	}
+	if ordering := query.Get("ordering"); ordering != "" {
+		field := strings.TrimPrefix(ordering, "-")
+		desc := field != ordering
+		sort.SliceStable(results, func(i, j int) bool {
+			if desc {
+				return compareValues(results[j][field], results[i][field])
+			}
+			return compareValues(results[i][field], results[j][field])
+		})
+	}
+	count := len(results)
+	offset, _ := strconv.Atoi(query.Get("offset"))
+	if offset > count {
+		offset = count
+	}
+	results = results[offset:]
+	response := Object{"count": count, "next": nil, "previous": nil}
+	if limit, _ := strconv.Atoi(query.Get("limit")); limit > 0 && limit < len(results) {
+		results = results[:limit]
+		next := *c.r.URL
+		query.Set("offset", strconv.Itoa(offset+limit))
+		next.RawQuery = query.Encode()
+		response["next"] = s.URL + next.RequestURI()
+	}
+	if results == nil {
+		results = []Object{}
+	}
+	response["results"] = results
+	writeJSON(c.w, http.StatusOK, response)
+}
+
+var listParams = map[string]bool{"limit": true, "offset": true, "ordering": true, "fields": true, "search": true, "async": true}
+
+// filterAliases maps filter names to fields of entities
+var filterAliases = map[string]string{"name": "verbose_name", "vmachine": "vmachine_info", "vnetwork": "vnetwork_info"}
+
+func matchesQuery(obj Object, query map[string][]string) bool {
+	for name, values := range query {
+		if listParams[name] {
+			continue
+		}
+		if alias, ok := filterAliases[name]; ok {
+			name = alias
+		}
+		value, ok := obj[name]
+		if !ok {
+			continue
+		}
+		if !matchesAny(valueString(value), values) {
+			return false
+		}
+	}
+	if search := strings.ToLower(firstValue(query, "search")); search != "" {
+		for _, field := range []string{"verbose_name", "filename", "username", "message", "name"} {
+			if strings.Contains(strings.ToLower(valueString(obj[field])), search) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func matchesAny(value string, values []string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func firstValue(query map[string][]string, name string) string {
+	if values := query[name]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// valueString string form of value for filtering, references are compared by id
+func valueString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case Object:
+		return valueString(v["id"])
+	case map[string]interface{}:
+		return valueString(v["id"])
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func compareValues(a, b interface{}) bool {
+	fa, aNumber := number(a)
+	fb, bNumber := number(b)
+	if aNumber && bNumber {
+		return fa < fb
+	}
+	return valueString(a) < valueString(b)
+}
+
+func number(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// collection entities of a resource in order of creation
+type collection struct {
+	ids   []string
+	items map[string]Object
+}
+
+func (c *collection) all() []Object {
+	objects := make([]Object, 0, len(c.ids))
+	for _, id := range c.ids {
+		objects = append(objects, c.items[id])
+	}
+	return objects
+}
+
+func (c *collection) remove(id string) bool {
+	if _, ok := c.items[id]; !ok {
+		return false
+	}
+	delete(c.items, id)
+	for i, v := range c.ids {
+		if v == id {
+			c.ids = append(c.ids[:i], c.ids[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+func (s *Server) collection(res Resource) *collection {
+	c := s.store[res]
+	if c == nil {
+		c = &collection{items: map[string]Object{}}
+		s.store[res] = c
+	}
+	return c
+}
+
+func (s *Server) get(res Resource, id string) Object {
+	if c := s.store[res]; c != nil {
+		return c.items[id]
+	}
+	return nil
+}
+
+func (s *Server) add(res Resource, obj Object) Object {
+	if valueString(obj["id"]) == "" {
+		obj["id"] = uuid.NewString()
+	}
+	if _, ok := obj["created"]; !ok {
+		obj["created"] = now()
+	}
+	if _, ok := obj["status"]; !ok {
+		obj["status"] = "ACTIVE"
+	}
+	c := s.collection(res)
+	id := valueString(obj["id"])
+	if _, ok := c.items[id]; !ok {
+		c.ids = append(c.ids, id)
+	}
+	c.items[id] = obj
+	return obj
+}
+
+// ref returns the short form of entity used in references, e.g. node of domain
+func (s *Server) ref(res Resource, id string) Object {
+	ref := Object{"id": id}
+	if obj := s.get(res, id); obj != nil {
+		ref["verbose_name"] = obj["verbose_name"]
+		if t, ok := obj["type"]; ok && res == DataPools {
+			ref["type"] = t
+		}
+	}
+	return ref
+}
+
+func copyObject(obj Object) Object {
+	if obj == nil {
+		return nil
+	}
+	b, _ := json.Marshal(obj)
+	copied := Object{}
+	json.Unmarshal(b, &copied)
+	return copied
+}
+
+func merge(obj Object, fields Object) {
+	for k, v := range fields {
+		if k == "id" {
+			continue
+		}
+		obj[k] = v
+	}
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+func splitPath(p string) []string {
+	p = strings.Trim(p, "/")
+	if p == "" {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes error in the format of VeiL
+func writeError(w http.ResponseWriter, status int, detail string) {
+	writeJSON(w, status, Object{"errors": []Object{{"detail": detail, "code": strconv.Itoa(status)}}})
+}
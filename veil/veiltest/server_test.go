@@ -0,0 +1,184 @@
+package veiltest
+
+import (
+	"context"
+	"errors"
+	"github.com/jsc-masshtab/veil-api-client-go/veil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func newClient(t *testing.T, server *Server) *veil.WebClient {
+	client, err := veil.NewClientWithOptions(veil.WithBaseURL(server.URL), veil.WithToken(Token))
+	require.NoError(t, err)
+	return client
+}
+
+func Test_ServerDomainLifecycle(t *testing.T) {
+	server := NewServer(nil)
+	defer server.Close()
+	client := newClient(t, server)
+
+	domain, _, err := client.Domain.MultiCreate(veil.DomainMultiCreateConfig{
+		DomainCreateConfig: veil.DomainCreateConfig{VerboseName: "vm", MemoryCount: 2048},
+		StartOn:            true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "vm", domain.VerboseName)
+	assert.Equal(t, veil.Status.Active, domain.Status)
+	assert.Equal(t, 2048, domain.MemoryCount)
+	assert.Equal(t, NodeId, domain.Node.Id)
+	assert.Equal(t, PowerOn, domain.UserPowerState)
+
+	_, _, err = client.Domain.Shutdown(domain, false)
+	require.NoError(t, err)
+	assert.Equal(t, PowerOff, domain.UserPowerState)
+	_, _, err = client.Domain.Reboot(domain, false)
+	assert.True(t, veil.IsBadRequest(err))
+
+	clone, _, err := client.Domain.Clone(domain.Id, veil.DomainCloneConfig{VerboseName: "vm-2"})
+	require.NoError(t, err)
+	assert.Equal(t, "vm-2", clone.VerboseName)
+	assert.Equal(t, domain.Id, clone.Parent.Id)
+
+	list, _, err := client.Domain.ListFiltered(veil.DomainFilter{Name: "vm-2"})
+	require.NoError(t, err)
+	require.Equal(t, 1, list.Count)
+	assert.Equal(t, clone.Id, list.Results[0].Id)
+
+	_, _, err = client.Domain.Remove(clone.Id, true, false)
+	require.NoError(t, err)
+	_, _, err = client.Domain.Get(clone.Id)
+	assert.True(t, veil.IsNotFound(err))
+
+	events, _, err := client.Event.List()
+	require.NoError(t, err)
+	assert.Equal(t, 2, events.Count)
+	return
+}
+
+func Test_ServerTaskProgress(t *testing.T) {
+	server := NewServer(&Options{TaskPolls: 4})
+	defer server.Close()
+	client := newClient(t, server)
+
+	op, _, err := client.Vdisk.StartCreate(context.Background(), &veil.VdiskCreate{VerboseName: "disk", Size: 1})
+	require.NoError(t, err)
+	var progress []int
+	task, err := client.Task.Wait(context.Background(), op.TaskId(), &veil.TaskWaitOptions{
+		PollInterval: time.Millisecond,
+		OnProgress:   func(task *veil.TaskObject) { progress = append(progress, task.Progress) },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{25, 50, 75, 100}, progress)
+	assert.Equal(t, veil.TaskStatus.Success, task.Status)
+	vdisk, err := op.Wait(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, veil.Status.Active, vdisk.Status)
+
+	op, _, err = client.Vdisk.StartCreate(context.Background(), &veil.VdiskCreate{VerboseName: "canceled", Size: 1})
+	require.NoError(t, err)
+	require.NoError(t, op.Cancel(context.Background()))
+	_, err = op.Wait(context.Background())
+	assert.True(t, veil.IsTaskFailed(err))
+
+	op, _, err = client.Vdisk.StartCreate(context.Background(), &veil.VdiskCreate{VerboseName: "finished", Size: 1})
+	require.NoError(t, err)
+	assert.True(t, server.FinishTask(op.TaskId(), ""))
+	_, err = op.Wait(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, server.CountRequests("GET", "/api/tasks/"+op.TaskId()+"/"))
+	return
+}
+
+func Test_ServerFaults(t *testing.T) {
+	server := NewServer(nil)
+	defer server.Close()
+	client := newClient(t, server)
+
+	server.Inject(Fault{Method: "GET", Path: "/api/nodes/*/", Status: http.StatusServiceUnavailable, Times: 1})
+	_, _, err := client.Node.Get(NodeId)
+	assert.True(t, veil.IsServerError(err))
+	node, _, err := client.Node.Get(NodeId)
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", node.ManagementIp)
+
+	server.Inject(Fault{Path: "/api/domains/multi-create-domain/", TaskError: "no free memory"})
+	_, _, err = client.Domain.MultiCreate(veil.DomainMultiCreateConfig{DomainCreateConfig: veil.DomainCreateConfig{VerboseName: "vm"}})
+	var taskErr *veil.TaskFailedError
+	require.True(t, errors.As(err, &taskErr))
+	assert.Equal(t, "no free memory", taskErr.ErrorMessage)
+	server.ClearFaults()
+
+	server.SetLatency(50 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, _, err = client.Cluster.ListContext(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	return
+}
+
+func Test_ServerListPages(t *testing.T) {
+	server := NewServer(nil)
+	defer server.Close()
+	client := newClient(t, server)
+	for _, name := range []string{"c", "a", "e", "b", "d"} {
+		server.Add(Vdisks, Object{"verbose_name": name, "datapool": Object{"id": DataPoolId}})
+	}
+
+	it := client.Vdisk.Iter(context.Background(), new(veil.VdiskFilter).OrderBy("verbose_name"), &veil.PageOptions{PageSize: 2})
+	var names []string
+	for it.Next() {
+		names = append(names, it.Item().VerboseName)
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, names)
+	assert.Equal(t, 3, server.CountRequests("GET", "/api/vdisks/"))
+
+	vnets, _, err := client.Vnet.List()
+	require.NoError(t, err)
+	assert.Equal(t, 1, vnets.Count)
+	user, _, err := client.User.Get(UserId)
+	require.NoError(t, err)
+	assert.Equal(t, "admin", user.UserName)
+	return
+}
+
+func Test_ServerFiles(t *testing.T) {
+	server := NewServer(nil)
+	defer server.Close()
+	client := newClient(t, server)
+
+	iso, err := client.Iso.Create(DataPoolId, "https://example.com/images/os.iso", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "os.iso", iso.FileName)
+	assert.Equal(t, veil.Status.Active, iso.Status)
+
+	file, err := client.Library.Create(DataPoolId, "https://example.com/images/disk.qcow2", 0)
+	require.NoError(t, err)
+	vdisk, _, err := client.Library.Import(file.Id, veil.FileImportConfig{VerboseName: "imported", Datapool: DataPoolId})
+	require.NoError(t, err)
+	assert.Equal(t, "imported", vdisk.VerboseName)
+	assert.Equal(t, DataPoolId, vdisk.DataPool.Id)
+	return
+}
+
+func Test_ServerAuth(t *testing.T) {
+	server := NewServer(&Options{Token: "secret"})
+	defer server.Close()
+
+	client := newClient(t, server)
+	_, _, err := client.Cluster.List()
+	assert.True(t, veil.IsUnauthorized(err))
+
+	client, err = veil.NewClientWithOptions(veil.WithBaseURL(server.URL),
+		veil.WithCredentials(veil.LoginCredentials{Username: "admin", Password: "password"}))
+	require.NoError(t, err)
+	clusters, _, err := client.Cluster.List()
+	require.NoError(t, err)
+	assert.Equal(t, ClusterId, clusters.Results[0].Id)
+	return
+}
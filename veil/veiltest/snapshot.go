@@ -0,0 +1,150 @@
+package veiltest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// snapshotFields fields of a domain saved by a snapshot and restored by revert or clone from it
+var snapshotFields = []string{"memory_count", "cpu_count", "description", "os_type"}
+
+// snapshot domain snapshot with the saved state of the domain
+type snapshot struct {
+	object Object
+	state  Object
+}
+
+// snapshotAction serves snapshot endpoints of domains, it reports false for other actions
+func (s *Server) snapshotAction(c *call, domain Object, action string, fields Object) bool {
+	id := valueString(domain["id"])
+	switch {
+	case action == "snapshots" && c.r.Method == http.MethodGet:
+		results := []Object{}
+		for _, snap := range s.snapshots[id] {
+			results = append(results, snap.object)
+		}
+		writeJSON(c.w, http.StatusOK, Object{"count": len(results), "results": results})
+	case action == "create-snapshot" && c.r.Method == http.MethodPost:
+		s.createSnapshot(c, domain, fields)
+	case action == "revert-snapshot" && c.r.Method == http.MethodPost:
+		snap := s.snapshot(c, id, valueString(fields["snapshot"]))
+		if snap == nil {
+			return true
+		}
+		s.startAsync(c, "Revert snapshot", Domains, domain, func() Object {
+			merge(domain, copyObject(snap.state))
+			if memory, _ := snap.object["memory"].(bool); !memory {
+				domain["user_power_state"] = PowerOff
+			}
+			s.setCurrentSnapshot(id, snap)
+			return domain
+		})
+	case action == "remove-snapshot" && c.r.Method == http.MethodPost:
+		snap := s.snapshot(c, id, valueString(fields["snapshot"]))
+		if snap == nil {
+			return true
+		}
+		s.startAsync(c, "Remove snapshot", Domains, domain, func() Object {
+			s.removeSnapshot(id, snap)
+			return domain
+		})
+	default:
+		return false
+	}
+	return true
+}
+
+func (s *Server) createSnapshot(c *call, domain Object, fields Object) {
+	id := valueString(domain["id"])
+	if valueString(fields["verbose_name"]) == "" {
+		writeError(c.w, http.StatusBadRequest, "verbose_name is required")
+		return
+	}
+	memory, _ := fields["memory"].(bool)
+	if state, _ := number(domain["user_power_state"]); memory && state != PowerOn {
+		writeError(c.w, http.StatusBadRequest, "memory state can be saved for running domain only")
+		return
+	}
+	state := Object{"user_power_state": domain["user_power_state"]}
+	for _, field := range snapshotFields {
+		state[field] = domain[field]
+	}
+	snap := &snapshot{
+		object: Object{
+			"id":           uuid.NewString(),
+			"verbose_name": fields["verbose_name"],
+			"description":  fields["description"],
+			"memory":       memory,
+			"domain":       Object{"id": id, "verbose_name": domain["verbose_name"]},
+			"status":       "CREATING",
+			"current":      false,
+			"created":      now(),
+		},
+		state: copyObject(state),
+	}
+	if current := s.currentSnapshot(id); current != nil {
+		snap.object["parent"] = current.object["id"]
+	}
+	s.snapshots[id] = append(s.snapshots[id], snap)
+	s.startAsync(c, "Create snapshot", Domains, snap.object, func() Object {
+		s.setCurrentSnapshot(id, snap)
+		return snap.object
+	})
+}
+
+// getSnapshot serves GET of a single snapshot
+func (s *Server) getSnapshot(c *call, domainId string, snapshotId string) {
+	if snap := s.snapshot(c, domainId, snapshotId); snap != nil {
+		writeJSON(c.w, http.StatusOK, snap.object)
+	}
+}
+
+// snapshot finds snapshot of domain, missing snapshot is reported with 404
+func (s *Server) snapshot(c *call, domainId string, snapshotId string) *snapshot {
+	for _, snap := range s.snapshots[domainId] {
+		if snap.object["id"] == snapshotId {
+			return snap
+		}
+	}
+	writeError(c.w, http.StatusNotFound, fmt.Sprintf("snapshot %s not found", snapshotId))
+	return nil
+}
+
+func (s *Server) currentSnapshot(domainId string) *snapshot {
+	for _, snap := range s.snapshots[domainId] {
+		if current, _ := snap.object["current"].(bool); current {
+			return snap
+		}
+	}
+	return nil
+}
+
+func (s *Server) setCurrentSnapshot(domainId string, current *snapshot) {
+	for _, snap := range s.snapshots[domainId] {
+		snap.object["current"] = snap == current
+	}
+}
+
+// removeSnapshot deletes snapshot, its children are attached to its parent
+func (s *Server) removeSnapshot(domainId string, removed *snapshot) {
+	var kept []*snapshot
+	for _, snap := range s.snapshots[domainId] {
+		if snap == removed {
+			continue
+		}
+		if snap.object["parent"] == removed.object["id"] {
+			snap.object["parent"] = removed.object["parent"]
+		}
+		kept = append(kept, snap)
+	}
+	s.snapshots[domainId] = kept
+	if current, _ := removed.object["current"].(bool); current {
+		for _, snap := range kept {
+			if snap.object["id"] == removed.object["parent"] {
+				snap.object["current"] = true
+			}
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package veil
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -68,34 +69,111 @@ type VMachinesResponse struct {
 	Results []VMachineInfObjectsList `json:"results,omitempty"`
 }
 
-func (entity *VMachineInfObject) Refresh(client *WebClient) (*VMachineInfObject, error) {
-	_, err := client.ExecuteRequest("GET", fmt.Sprint(baseVMachineInfUrl, entity.Id, "/"), []byte{}, entity)
+func (entity *VMachineInfObject) Refresh(client Client) (*VMachineInfObject, error) {
+	return entity.RefreshContext(context.Background(), client)
+}
+
+func (entity *VMachineInfObject) RefreshContext(ctx context.Context, client Client) (*VMachineInfObject, error) {
+	_, err := client.ExecuteRequestContext(ctx, "GET", fmt.Sprint(baseVMachineInfUrl, entity.Id, "/"), []byte{}, entity)
 	return entity, err
 }
 
 func (d *VMachineInfService) List() (*VMachinesResponse, *http.Response, error) {
+	return d.ListContext(context.Background())
+}
+
+func (d *VMachineInfService) ListContext(ctx context.Context) (*VMachinesResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "VMachineInfService.List")
 	response := new(VMachinesResponse)
-	res, err := d.client.ExecuteRequest("GET", baseVMachineInfUrl, []byte{}, response)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", baseVMachineInfUrl, []byte{}, response)
 	return response, res, err
 }
 
 func (d *VMachineInfService) ListParams(queryParams map[string]string) (*VMachinesResponse, *http.Response, error) {
-	listUrl := baseVMachineInfUrl
-	if len(queryParams) != 0 {
-		params := url.Values{}
-		for k, v := range queryParams {
-			params.Add(k, v)
-		}
-		listUrl += "?"
-		listUrl += params.Encode()
-	}
+	return d.ListParamsContext(context.Background(), queryParams)
+}
+
+func (d *VMachineInfService) ListParamsContext(ctx context.Context, queryParams map[string]string) (*VMachinesResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "VMachineInfService.ListParams")
+	response := new(VMachinesResponse)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", buildListUrl(baseVMachineInfUrl, Params(queryParams)), []byte{}, response)
+	return response, res, err
+}
+
+// VMachineInfFilter typed filter of network interfaces, zero fields are not sent
+type VMachineInfFilter struct {
+	ListOptions
+	Status    []string
+	Vmachine  string
+	Vnetwork  string
+	NicDriver string
+}
+
+// Values encodes the filter to query parameters
+func (f VMachineInfFilter) Values() url.Values {
+	params := f.ListOptions.Values()
+	addStrings(params, "status", f.Status)
+	setString(params, "vmachine", f.Vmachine)
+	setString(params, "vnetwork", f.Vnetwork)
+	setString(params, "nic_driver", f.NicDriver)
+	return params
+}
+
+// ListFiltered returns the first page of network interfaces matching filter
+func (d *VMachineInfService) ListFiltered(filter VMachineInfFilter) (*VMachinesResponse, *http.Response, error) {
+	return d.ListFilteredContext(context.Background(), filter)
+}
+
+// ListFilteredContext returns the first page of network interfaces matching filter
+func (d *VMachineInfService) ListFilteredContext(ctx context.Context, filter VMachineInfFilter) (*VMachinesResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "VMachineInfService.ListFiltered")
 	response := new(VMachinesResponse)
-	res, err := d.client.ExecuteRequest("GET", listUrl, []byte{}, response)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", buildListUrl(baseVMachineInfUrl, filter), []byte{}, response)
 	return response, res, err
 }
 
+// VMachineInfIterator streams network interfaces page by page
+type VMachineInfIterator struct {
+	*Iterator
+	item VMachineInfObjectsList
+}
+
+// Next advances to the next network interface
+func (it *VMachineInfIterator) Next() bool {
+	it.item = VMachineInfObjectsList{}
+	return it.Iterator.Next() && it.decode(&it.item)
+}
+
+// Item returns the current network interface
+func (it *VMachineInfIterator) Item() VMachineInfObjectsList {
+	return it.item
+}
+
+// Iter returns an iterator over all network interfaces matching filter, filter and opts may be nil
+func (d *VMachineInfService) Iter(ctx context.Context, filter ListFilter, opts *PageOptions) *VMachineInfIterator {
+	ctx = withOperation(ctx, "VMachineInfService.Iter")
+	return &VMachineInfIterator{Iterator: newIterator(ctx, d.client, baseVMachineInfUrl, filter, opts)}
+}
+
+// ListAll returns all network interfaces matching filter following the pages until exhausted
+func (d *VMachineInfService) ListAll(ctx context.Context, filter ListFilter) ([]VMachineInfObjectsList, error) {
+	ctx = withOperation(ctx, "VMachineInfService.ListAll")
+	it := d.Iter(ctx, filter, nil)
+	defer it.Close()
+	var items []VMachineInfObjectsList
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
+
 func (d *VMachineInfService) Get(Id string) (*VMachineInfObject, *http.Response, error) {
+	return d.GetContext(context.Background(), Id)
+}
+
+func (d *VMachineInfService) GetContext(ctx context.Context, Id string) (*VMachineInfObject, *http.Response, error) {
+	ctx = withOperation(ctx, "VMachineInfService.Get")
 	entity := new(VMachineInfObject)
-	res, err := d.client.ExecuteRequest("GET", fmt.Sprint(baseVMachineInfUrl, Id, "/"), []byte{}, entity)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", fmt.Sprint(baseVMachineInfUrl, Id, "/"), []byte{}, entity)
 	return entity, res, err
 }
@@ -1,12 +1,14 @@
 package veil
 
 import (
+	"github.com/jsc-masshtab/veil-api-client-go/veil/veiltest"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
 
 func Test_VMachineInfListGet(t *testing.T) {
-	client := NewClient("", "", false)
+	server, client := newTestServerClient(t)
+	server.Add(veiltest.VMachineInfs, veiltest.Object{"nic_driver": "virtio", "mac_address": "52:54:00:00:00:01"})
 
 	response, _, err := client.VMachineInf.List()
 	assert.Nil(t, err)
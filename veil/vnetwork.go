@@ -1,6 +1,7 @@
 package veil
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -91,34 +92,113 @@ type VnetsResponse struct {
 	Results []VnetObjectsList `json:"results,omitempty"`
 }
 
-func (entity *VnetObject) Refresh(client *WebClient) (*VnetObject, error) {
-	_, err := client.ExecuteRequest("GET", fmt.Sprint(baseVnetUrl, entity.Id, "/"), []byte{}, entity)
+func (entity *VnetObject) Refresh(client Client) (*VnetObject, error) {
+	return entity.RefreshContext(context.Background(), client)
+}
+
+func (entity *VnetObject) RefreshContext(ctx context.Context, client Client) (*VnetObject, error) {
+	_, err := client.ExecuteRequestContext(ctx, "GET", fmt.Sprint(baseVnetUrl, entity.Id, "/"), []byte{}, entity)
 	return entity, err
 }
 
 func (d *VnetService) List() (*VnetsResponse, *http.Response, error) {
+	return d.ListContext(context.Background())
+}
+
+func (d *VnetService) ListContext(ctx context.Context) (*VnetsResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "VnetService.List")
 	response := new(VnetsResponse)
-	res, err := d.client.ExecuteRequest("GET", baseVnetUrl, []byte{}, response)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", baseVnetUrl, []byte{}, response)
 	return response, res, err
 }
 
 func (d *VnetService) ListParams(queryParams map[string]string) (*VnetsResponse, *http.Response, error) {
-	listUrl := baseVnetUrl
-	if len(queryParams) != 0 {
-		params := url.Values{}
-		for k, v := range queryParams {
-			params.Add(k, v)
-		}
-		listUrl += "?"
-		listUrl += params.Encode()
-	}
+	return d.ListParamsContext(context.Background(), queryParams)
+}
+
+func (d *VnetService) ListParamsContext(ctx context.Context, queryParams map[string]string) (*VnetsResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "VnetService.ListParams")
+	response := new(VnetsResponse)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", buildListUrl(baseVnetUrl, Params(queryParams)), []byte{}, response)
+	return response, res, err
+}
+
+// VnetFilter typed filter of virtual networks, zero fields are not sent
+type VnetFilter struct {
+	ListOptions
+	Status      []string
+	VerboseName string
+	Node        string
+	Cluster     string
+	Tags        []string
+}
+
+// Values encodes the filter to query parameters
+func (f VnetFilter) Values() url.Values {
+	params := f.ListOptions.Values()
+	addStrings(params, "status", f.Status)
+	setString(params, "verbose_name", f.VerboseName)
+	setString(params, "node", f.Node)
+	setString(params, "cluster", f.Cluster)
+	addStrings(params, "tags", f.Tags)
+	return params
+}
+
+// ListFiltered returns the first page of virtual networks matching filter
+func (d *VnetService) ListFiltered(filter VnetFilter) (*VnetsResponse, *http.Response, error) {
+	return d.ListFilteredContext(context.Background(), filter)
+}
+
+// ListFilteredContext returns the first page of virtual networks matching filter
+func (d *VnetService) ListFilteredContext(ctx context.Context, filter VnetFilter) (*VnetsResponse, *http.Response, error) {
+	ctx = withOperation(ctx, "VnetService.ListFiltered")
 	response := new(VnetsResponse)
-	res, err := d.client.ExecuteRequest("GET", listUrl, []byte{}, response)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", buildListUrl(baseVnetUrl, filter), []byte{}, response)
 	return response, res, err
 }
 
+// VnetIterator streams virtual networks page by page
+type VnetIterator struct {
+	*Iterator
+	item VnetObjectsList
+}
+
+// Next advances to the next virtual network
+func (it *VnetIterator) Next() bool {
+	it.item = VnetObjectsList{}
+	return it.Iterator.Next() && it.decode(&it.item)
+}
+
+// Item returns the current virtual network
+func (it *VnetIterator) Item() VnetObjectsList {
+	return it.item
+}
+
+// Iter returns an iterator over all virtual networks matching filter, filter and opts may be nil
+func (d *VnetService) Iter(ctx context.Context, filter ListFilter, opts *PageOptions) *VnetIterator {
+	ctx = withOperation(ctx, "VnetService.Iter")
+	return &VnetIterator{Iterator: newIterator(ctx, d.client, baseVnetUrl, filter, opts)}
+}
+
+// ListAll returns all virtual networks matching filter following the pages until exhausted
+func (d *VnetService) ListAll(ctx context.Context, filter ListFilter) ([]VnetObjectsList, error) {
+	ctx = withOperation(ctx, "VnetService.ListAll")
+	it := d.Iter(ctx, filter, nil)
+	defer it.Close()
+	var items []VnetObjectsList
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
+
 func (d *VnetService) Get(Id string) (*VnetObject, *http.Response, error) {
+	return d.GetContext(context.Background(), Id)
+}
+
+func (d *VnetService) GetContext(ctx context.Context, Id string) (*VnetObject, *http.Response, error) {
+	ctx = withOperation(ctx, "VnetService.Get")
 	entity := new(VnetObject)
-	res, err := d.client.ExecuteRequest("GET", fmt.Sprint(baseVnetUrl, Id, "/"), []byte{}, entity)
+	res, err := d.client.ExecuteRequestContext(ctx, "GET", fmt.Sprint(baseVnetUrl, Id, "/"), []byte{}, entity)
 	return entity, res, err
 }
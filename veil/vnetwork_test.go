@@ -6,7 +6,7 @@ import (
 )
 
 func Test_VnetListGet(t *testing.T) {
-	client := NewClient("", "", false)
+	_, client := newTestServerClient(t)
 
 	response, _, err := client.Vnet.List()
 	assert.Nil(t, err)